@@ -15,6 +15,16 @@ type Heuristic[W g.Weight] interface {
 	Evaluate(id g.NodeId) W
 }
 
+// DynamicHeuristic is a Heuristic that may change its values during a search, e.g. by activating further landmarks.
+// A* search notifies the heuristic of every settled node and updates the priorities of the queued nodes if the heuristic has changed.
+// The values of the heuristic must not decrease by an update, which guarantees correct results for consistent heuristics.
+type DynamicHeuristic[W g.Weight] interface {
+	Heuristic[W]
+	// Update is called after the node with ID=id has been settled at the given distance from the source.
+	// It reports whether the values of the heuristic have changed.
+	Update(id g.NodeId, distance W) bool
+}
+
 // AStarRouter implements the Router interface and provides A* search, a lower-bounding variant of Dijkstra's algorithm.
 type AStarRouter[N any, E g.IWeightedHalfEdge[W], W g.Weight] struct {
 	Graph     g.Graph[N, E]
@@ -41,18 +51,24 @@ func (r AStarRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSpace b
 	heap.Push(&pq, dijkstraItems[source])
 
 	r.Heuristic.Init(source, target)
+	dynamicHeuristic, isDynamic := r.Heuristic.(DynamicHeuristic[W])
 
+	edges := g.NewHalfEdgeReader(r.Graph)
 	pqPops := 0
 	for len(pq) > 0 {
 		currentPqItem := heap.Pop(&pq).(*AStarPqItem[W])
 		currentNodeId := currentPqItem.Id
 		pqPops++
 
+		if isDynamic && dynamicHeuristic.Update(currentNodeId, currentPqItem.Distance) {
+			reprioritize(&pq, r.Heuristic)
+		}
+
 		if recordSearchSpace {
 			searchSpace = append(searchSpace, currentNodeId)
 		}
 
-		for _, edge := range r.Graph.GetHalfEdgesFrom(currentNodeId) {
+		for _, edge := range edges.From(currentNodeId) {
 			successor := edge.To()
 
 			if dijkstraItems[successor] == nil {
@@ -85,3 +101,11 @@ func (r AStarRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSpace b
 	}
 	return res
 }
+
+// reprioritize recomputes the priorities of all queued items after the heuristic has changed.
+func reprioritize[W g.Weight](pq *AStarPriorityQueue[W], heuristic Heuristic[W]) {
+	for _, pqItem := range *pq {
+		pqItem.Priority = pqItem.Distance + heuristic.Evaluate(pqItem.Id)
+	}
+	heap.Init(pq)
+}
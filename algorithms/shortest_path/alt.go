@@ -2,6 +2,7 @@ package shortest_path
 
 import (
 	"math/rand"
+	"sort"
 	"sync"
 
 	g "github.com/dmholtz/graffiti/graph"
@@ -23,6 +24,16 @@ type AltHeuristic[W g.Weight] struct {
 	Target g.NodeId // target node of the current search: updated via Init
 
 	ActiveLandmarks []LandmarkDistances[W]
+
+	// ActiveLandmarkCount is the number of landmarks that are selected per query by Init, namely the landmarks giving
+	// the best lower bounds for the distance between source and target. All landmarks are active iff it is 0.
+	ActiveLandmarkCount int
+	// UpdateInterval is the number of settled nodes after which the active landmarks are re-evaluated during the search:
+	// a landmark is activated if it improves the lower bound from the last settled node to the target (see Update).
+	// Active landmarks are only selected at Init iff it is 0.
+	UpdateInterval int
+
+	settled int // number of settled nodes since the last update
 }
 
 func NewAltHeurisitc[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpose g.Graph[N, E], landmarks []g.NodeId) *AltHeuristic[W] {
@@ -53,6 +64,11 @@ func NewAltHeurisitc[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpo
 	close(jobs) // close the jobs channel, since producers have finished
 	<-done      // waint on the consumer
 
+	return NewAltHeuristicFromDistances(landmarkDistancesCollection)
+}
+
+// NewAltHeuristicFromDistances creates an ALT heuristic from precomputed landmark distances, e.g. loaded from a file.
+func NewAltHeuristicFromDistances[W g.Weight](landmarkDistancesCollection map[g.NodeId]LandmarkDistances[W]) *AltHeuristic[W] {
 	ah := AltHeuristic[W]{LandmarkDistancesCollection: landmarkDistancesCollection, ActiveLandmarks: make([]LandmarkDistances[W], 0)}
 
 	// default implementation for setting active landmarks: set all landmarks to active landmarks
@@ -66,32 +82,88 @@ func NewAltHeurisitc[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpo
 // altPreprocessing is a producer function that does the preprocessing for a single landmark.
 // The method is designed for parallel implementation following the producer/consumer pattern.
 func altPreprocessing[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpose g.Graph[N, E], landmark g.NodeId, jobs chan<- LandmarkDistances[W], wg *sync.WaitGroup) {
+	jobs <- computeLandmarkDistances[N, E, W](graph, transpose, landmark)
+	wg.Done()
+}
+
+// computeLandmarkDistances computes the lengths of shortest paths from and to a landmark.
+func computeLandmarkDistances[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpose g.Graph[N, E], landmark g.NodeId) LandmarkDistances[W] {
 	// compute distances from landmark l to every node: one-to-all-dijkstra in (forward) graph starting at l
 	distancesFrom := DijkstraOneToAll[N, E, W](graph, landmark).Lengths
 
 	// compute distances from every node to landmark l: one-to-all-dijsktra in transposed graph starting at l
 	distancesTo := DijkstraOneToAll[N, E, W](transpose, landmark).Lengths
 
-	jobs <- LandmarkDistances[W]{Landmark: landmark, From: distancesFrom, To: distancesTo}
-	wg.Done()
+	return LandmarkDistances[W]{Landmark: landmark, From: distancesFrom, To: distancesTo}
 }
 
 // Init implements Heuristic.Init
 func (ah *AltHeuristic[W]) Init(source g.NodeId, target g.NodeId) {
 	ah.Source = source
 	ah.Target = target
+	ah.settled = 0
+
+	if ah.ActiveLandmarkCount <= 0 || ah.ActiveLandmarkCount > len(ah.LandmarkDistancesCollection) {
+		return
+	}
+	// select the landmarks with the best lower bounds for the distance from source to target
+	candidates := make([]LandmarkDistances[W], 0, len(ah.LandmarkDistancesCollection))
+	for _, landmarkDistances := range ah.LandmarkDistancesCollection {
+		candidates = append(candidates, landmarkDistances)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		boundI, boundJ := landmarkBound(candidates[i], source, target), landmarkBound(candidates[j], source, target)
+		if boundI != boundJ {
+			return boundI > boundJ
+		}
+		return candidates[i].Landmark < candidates[j].Landmark
+	})
+	ah.ActiveLandmarks = append(ah.ActiveLandmarks[:0], candidates[:ah.ActiveLandmarkCount]...)
 }
 
 // Evaluate implements Heuristic.Evaluate
 func (ah AltHeuristic[W]) Evaluate(id g.NodeId) W {
 	upper_bound := W(0)
 	for _, landmark := range ah.ActiveLandmarks {
-		upper_bound = max(upper_bound, landmark.From[ah.Target]-landmark.From[id])
-		upper_bound = max(upper_bound, landmark.To[id]-landmark.To[ah.Target])
+		upper_bound = max(upper_bound, landmarkBound(landmark, id, ah.Target))
 	}
 	return upper_bound
 }
 
+// Update implements DynamicHeuristic.Update
+//
+// Every UpdateInterval settled nodes, the inactive landmark giving the best lower bound from the settled node to the target
+// is activated if it improves the current lower bound. Since landmarks are only added, the heuristic does not decrease.
+func (ah *AltHeuristic[W]) Update(id g.NodeId, distance W) bool {
+	if ah.UpdateInterval <= 0 || len(ah.ActiveLandmarks) == len(ah.LandmarkDistancesCollection) {
+		return false
+	}
+	ah.settled++
+	if ah.settled < ah.UpdateInterval {
+		return false
+	}
+	ah.settled = 0
+
+	current := ah.Evaluate(id)
+	best, bestBound := -1, current
+	for landmark, landmarkDistances := range ah.LandmarkDistancesCollection {
+		if bound := landmarkBound(landmarkDistances, id, ah.Target); bound > bestBound || (bound == bestBound && bound > current && landmark < best) {
+			best, bestBound = landmark, bound
+		}
+	}
+	if best == -1 {
+		// no inactive landmark improves the lower bound
+		return false
+	}
+	ah.ActiveLandmarks = append(ah.ActiveLandmarks, ah.LandmarkDistancesCollection[best])
+	return true
+}
+
+// landmarkBound returns the lower bound for the distance from source to target using the triangle inequalities of a single landmark.
+func landmarkBound[W g.Weight](landmark LandmarkDistances[W], source, target g.NodeId) W {
+	return max(landmark.From[target]-landmark.From[source], landmark.To[source]-landmark.To[target])
+}
+
 // Maximum Implementation for generic (weight) number types
 // max(a, b) returns a iff a is greater or equal than b.
 func max[W g.Weight](a, b W) W {
@@ -103,13 +175,20 @@ func max[W g.Weight](a, b W) W {
 }
 
 // UniformLandmarks chooses n nodes uniformly and at random from the graph.
+// See FarthestLandmarks, AvoidLandmarks, PlanarLandmarks and MaxCoverLandmarks for landmarks of higher quality.
 func UniformLandmarks[N any, E g.IHalfEdge](graph g.Graph[N, E], n int) []g.NodeId {
-	landmarks := make([]g.NodeId, 0, n)
 	// choose a seed that is different from the seed generating the test sequences
 	// otherwise, the landmarks would be severely biased
-	rand.Seed(rand.Int63())
+	return UniformLandmarksWithSeed(graph, n, rand.Int63())
+}
+
+// UniformLandmarksWithSeed chooses n nodes uniformly and at random from the graph, which is deterministic for a given seed.
+// In contrast to UniformLandmarks, the global random number generator is left untouched.
+func UniformLandmarksWithSeed[N any, E g.IHalfEdge](graph g.Graph[N, E], n int, seed int64) []g.NodeId {
+	rng := rand.New(rand.NewSource(seed))
+	landmarks := make([]g.NodeId, 0, n)
 	for i := 0; i < n; i++ {
-		landmark := rand.Intn(graph.NodeCount())
+		landmark := rng.Intn(graph.NodeCount())
 		landmarks = append(landmarks, landmark)
 	}
 	return landmarks
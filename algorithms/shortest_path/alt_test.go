@@ -37,3 +37,40 @@ func TestBidirectionalAlt(t *testing.T) {
 
 	DifferentialTesting(t, testedRouter, baselineRouter, aag.NodeCount())
 }
+
+// Differential testing: Compare the output of ALT with per-query active landmarks with Dijkstra's algorithm.
+func TestAltActiveLandmarks(t *testing.T) {
+	aag := loadAdjacencyArrayFromGob[g.GeoPoint, g.WeightedHalfEdge[int]](defaultGraphFile) // aag is a undirected graph
+
+	landmarks := sp.AvoidLandmarks[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, 32, 1)
+	baselineRouter := sp.DijkstraRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag}
+
+	for _, updateInterval := range []int{0, 64} {
+		altHeuristic := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, landmarks)
+		altHeuristic.ActiveLandmarkCount = 4
+		altHeuristic.UpdateInterval = updateInterval
+
+		testedRouter := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: altHeuristic}
+		DifferentialTesting(t, testedRouter, baselineRouter, aag.NodeCount())
+
+		if len(altHeuristic.ActiveLandmarks) < 4 || (updateInterval == 0 && len(altHeuristic.ActiveLandmarks) != 4) {
+			t.Errorf("Expected 4 active landmarks (update interval %d), got %d", updateInterval, len(altHeuristic.ActiveLandmarks))
+		}
+	}
+}
+
+// Differential testing: Compare the output of bidirectional ALT with consistent potentials with Dijkstra's algorithm.
+func TestBidirectionalAltRouter(t *testing.T) {
+	aag := loadAdjacencyArrayFromGob[g.GeoPoint, g.WeightedHalfEdge[int]](defaultGraphFile) // aag is a undirected graph
+
+	landmarks := sp.AvoidLandmarks[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, 16, 1)
+	baselineRouter := sp.DijkstraRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag}
+
+	for _, activeLandmarkCount := range []int{0, 4} {
+		altHeuristic := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, landmarks)
+		altHeuristic.ActiveLandmarkCount = activeLandmarkCount
+
+		testedRouter := sp.BidirectionalAltRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Transpose: aag, Heuristic: altHeuristic, MaxInitializerValue: math.MaxInt}
+		DifferentialTesting(t, testedRouter, baselineRouter, aag.NodeCount())
+	}
+}
@@ -37,7 +37,10 @@ func (r ArcFlagAStarRouter[N, E, W]) Route(source, target g.NodeId, recordSearch
 	targetPart := r.Graph.GetNode(target).Partition()
 
 	r.Heuristic.Init(source, target)
+	dynamicHeuristic, isDynamic := r.Heuristic.(DynamicHeuristic[W])
 
+	edges := g.NewHalfEdgeReader(r.Graph)
+	transposedEdges := g.NewHalfEdgeReader(r.Transpose)
 	pqPops := 0
 	for len(pq) > 0 {
 		// A* search is unidirectional
@@ -45,11 +48,15 @@ func (r ArcFlagAStarRouter[N, E, W]) Route(source, target g.NodeId, recordSearch
 		currentNodeId := currentPqItem.Id
 		pqPops++
 
+		if isDynamic && dynamicHeuristic.Update(currentNodeId, currentPqItem.Distance) {
+			reprioritize(&pq, r.Heuristic)
+		}
+
 		if recordSearchSpace {
 			searchSpace = append(searchSpace, currentNodeId)
 		}
 
-		for _, edge := range r.Graph.GetHalfEdgesFrom(currentNodeId) {
+		for _, edge := range edges.From(currentNodeId) {
 			successor := edge.To()
 
 			if !edge.IsFlagged(targetPart) {
@@ -59,7 +66,7 @@ func (r ArcFlagAStarRouter[N, E, W]) Route(source, target g.NodeId, recordSearch
 			// incorporate bidirectional arcflags without having to run a bidirectional A* search
 			// find the reverse edge
 			var revEdge E
-			for _, e := range r.Transpose.GetHalfEdgesFrom(successor) {
+			for _, e := range transposedEdges.From(successor) {
 				if e.To() == currentNodeId {
 					revEdge = e
 					break
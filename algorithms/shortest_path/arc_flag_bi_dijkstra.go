@@ -59,6 +59,9 @@ func (r BidirectionalArcFlagRouter[N, E, W]) Route(source, target g.NodeId, reco
 	sourcePart := r.Transpose.GetNode(source).Partition()
 	targetPart := r.Graph.GetNode(target).Partition()
 
+	edges := g.NewHalfEdgeReader(r.Graph)
+	reverseEdges := g.NewHalfEdgeReader(r.Graph)
+	transposedEdges := g.NewHalfEdgeReader(r.Transpose)
 	pqPops := 0
 	for len(pqForward) > 0 && len(pqBackward) > 0 {
 		forwardPqItem := heap.Pop(&pqForward).(*DijkstraPqItem[W])
@@ -73,7 +76,7 @@ func (r BidirectionalArcFlagRouter[N, E, W]) Route(source, target g.NodeId, reco
 		}
 
 		// forward search
-		for _, edge := range r.Graph.GetHalfEdgesFrom(forwardNodeId) {
+		for _, edge := range edges.From(forwardNodeId) {
 			successor := edge.To()
 
 			if !edge.IsFlagged(targetPart) {
@@ -81,7 +84,7 @@ func (r BidirectionalArcFlagRouter[N, E, W]) Route(source, target g.NodeId, reco
 			}
 			// find the reverse edge
 			var revEdge E
-			for _, e := range r.Transpose.GetHalfEdgesFrom(successor) {
+			for _, e := range transposedEdges.From(successor) {
 				if e.To() == forwardNodeId {
 					revEdge = e
 					break
@@ -112,7 +115,7 @@ func (r BidirectionalArcFlagRouter[N, E, W]) Route(source, target g.NodeId, reco
 		}
 
 		// backward search
-		for _, edge := range r.Graph.GetHalfEdgesFrom(backwardNodeId) {
+		for _, edge := range edges.From(backwardNodeId) {
 			successor := edge.To()
 
 			if !edge.IsFlagged(sourcePart) {
@@ -120,7 +123,7 @@ func (r BidirectionalArcFlagRouter[N, E, W]) Route(source, target g.NodeId, reco
 			}
 			// find the reverse edge
 			var revEdge E
-			for _, e := range r.Graph.GetHalfEdgesFrom(successor) {
+			for _, e := range reverseEdges.From(successor) {
 				if e.To() == backwardNodeId {
 					revEdge = e
 					break
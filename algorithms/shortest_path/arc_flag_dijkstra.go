@@ -32,6 +32,7 @@ func (r ArcFlagRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSpace
 
 	targetPartition := r.Graph.GetNode(target).Partition()
 
+	edges := g.NewHalfEdgeReader(r.Graph)
 	pqPops := 0
 	for len(pq) > 0 {
 		currentPqItem := heap.Pop(&pq).(*DijkstraPqItem[W])
@@ -42,7 +43,7 @@ func (r ArcFlagRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSpace
 			searchSpace = append(searchSpace, currentNodeId)
 		}
 
-		for _, edge := range r.Graph.GetHalfEdgesFrom(currentNodeId) {
+		for _, edge := range edges.From(currentNodeId) {
 			if !edge.IsFlagged(targetPartition) {
 				continue
 			}
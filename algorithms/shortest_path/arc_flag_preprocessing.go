@@ -1,22 +1,35 @@
 package shortest_path
 
 import (
+	"container/heap"
 	"fmt"
+	"math/bits"
+	"runtime"
+	"sort"
 	"sync"
 
 	g "github.com/dmholtz/graffiti/graph"
 )
 
-type addFlagJob struct {
-	from      g.NodeId
-	to        g.NodeId
-	partition g.PartitionId
-}
-
-const MAX_GOROUTINES = 8
+// Number of boundary nodes, whose distances are computed by a single centralized search.
+const CENTRALIZED_SOURCES = 16
 
-// Parallel implementation of arc flag preprocessing
+// ComputeArcFlags computes arc flags on one worker per CPU (see ComputeArcFlagsParallel).
 func ComputeArcFlags[N g.Partitioner, E g.IFlaggedHalfEdge[W], W g.Weight](forwardGraph, transposedGraph g.Graph[N, E], partitionCount int) *g.AdjacencyArrayGraph[N, E] {
+	return ComputeArcFlagsParallel[N, E, W](forwardGraph, transposedGraph, partitionCount, runtime.NumCPU())
+}
+
+// Parallel implementation of arc flag preprocessing with centralized shortest path DAGs, cf. Hilger et al.: "Fast Point-to-Point
+// Shortest Path Computations with Arc-Flags", 2009.
+//
+// An edge (u, v) is flagged for partition p iff it is part of a shortest path from u to a boundary node b of p, i.e. iff
+// w(u, v) + dist(v, b) = dist(u, b). Instead of building a shortest path tree per boundary node, a centralized search on the
+// transposed graph computes the distances to up to CENTRALIZED_SOURCES boundary nodes of a partition at once. The edges of the
+// shortest path DAGs are identified by comparing distances.
+//
+// The partitions are processed on the given number of workers. Each worker writes the flags of a partition into its own bitset
+// of all edges, which is merged into the edges of the graph after the partition and reused for the next partition.
+func ComputeArcFlagsParallel[N g.Partitioner, E g.IFlaggedHalfEdge[W], W g.Weight](forwardGraph, transposedGraph g.Graph[N, E], partitionCount int, workers int) *g.AdjacencyArrayGraph[N, E] {
 
 	// create a copy of the (forward) graph
 	faag := g.NewAdjacencyArrayFromGraph(forwardGraph)
@@ -53,84 +66,284 @@ func ComputeArcFlags[N g.Partitioner, E g.IFlaggedHalfEdge[W], W g.Weight](forwa
 		}
 	}
 
-	// parallel implementation of arcflag preprocessing following the (multiple) producer - (single) consumer pattern.
-	// n producers: each consumer starts a backward search in the transposed graph from a boundary node and computes the arcflags (heavy workload)
-	// 1 consumer: the consumer synchronizes the results to avoid race conditions and writes the computed arcflags into memory (low workload)
-
-	jobs := make(chan addFlagJob, 1<<16) // buffered synchronization channel between producers and consumers
-	done := make(chan bool)              // indicates that the single consumer is done
-
-	guard := make(chan struct{}, MAX_GOROUTINES) // restrict the number of parallel processes to the number of CPU cores
-	wg := sync.WaitGroup{}                       // synchronizes the producers
-
-	// start consumer
-	go addFlag[N, E, W](jobs, faag, done)
-
-	// start producers in parallel
+	// process the boundary nodes of each partition in a deterministic order
+	tasks := make([]flagTask, partitionCount)
 	for partition, set := range boundaryNodeSets {
-		setSize := len(set)
-		wg.Add(setSize)
-		fmt.Printf("Partition: %d, size=%d\n", partition, setSize)
+		fmt.Printf("Partition: %d, size=%d\n", partition, len(set))
 		for boundaryNodeId := range set {
-			guard <- struct{}{} // reserve 1 producer
-			go backwardSearch[N, E, W](jobs, forwardGraph, transposedGraph, g.PartitionId(partition), boundaryNodeId, &wg, guard)
+			tasks[partition].boundaryNodes = append(tasks[partition].boundaryNodes, boundaryNodeId)
 		}
+		sort.Ints(tasks[partition].boundaryNodes)
 	}
 
-	// revise edges within the same partition
-	for i := 0; i < faag.NodeCount(); i++ {
-		for _, halfEdge := range faag.GetHalfEdgesFrom(i) {
-			if faag.GetNode(i).Partition() == faag.GetNode(halfEdge.To()).Partition() {
-				jobs <- addFlagJob{from: i, to: halfEdge.To(), partition: faag.GetNode(i).Partition()}
+	// flag edges within the same partition
+	for tailNodeId := range faag.Nodes {
+		tailPartition := faag.GetNode(tailNodeId).Partition()
+		for i := faag.Offsets[tailNodeId]; i < faag.Offsets[tailNodeId+1]; i++ {
+			if tailPartition == faag.GetNode(faag.Edges[i].To()).Partition() {
+				faag.Edges[i] = faag.Edges[i].AddFlag(tailPartition).(E)
 			}
 		}
 	}
 
-	wg.Wait()   // await the producers
-	close(jobs) // close the job channel
-	<-done      // wait for the single consumer to terminate
+	flagPartitions[N, E, W](faag, transposedGraph, tasks, workers, func(partition int, bitset []uint64) {
+		forEachFlaggedEdge(bitset, func(i int) {
+			faag.Edges[i] = faag.Edges[i].AddFlag(g.PartitionId(partition)).(E)
+		})
+	})
 
 	return faag
 }
 
-// producer function
-func backwardSearch[N g.Partitioner, E g.IFlaggedHalfEdge[W], W g.Weight](jobs chan<- addFlagJob, forwardGraph, transposedGraph g.Graph[N, E], partition g.PartitionId, boundaryNodeId g.NodeId, wg *sync.WaitGroup, guard <-chan struct{}) {
-	// calculate in reverse graph
-	tree := ShortestPathTree[N, E, W](transposedGraph, boundaryNodeId)
+// flagTask describes the flags of a partition: an edge is flagged iff it is part of a shortest path to one of the boundary nodes of
+// the partition. If the task is restricted to a region, only the edges between nodes of the region are flagged and the search
+// stops once the distances within the region are final.
+type flagTask struct {
+	boundaryNodes []g.NodeId // boundary nodes of the partition
+	region        []g.NodeId // nodes of the region, or nil for the entire graph
+}
+
+// flagPartitions computes the flags of each task on the given number of workers: each worker processes entire tasks and writes
+// the flags of a task into its own bitset of all edges, i.e. bit i is set iff edge i of the forward graph is flagged by the task.
+// After a task has been processed, merge is called with the index of the task and the bitset, which is reused for the next task
+// of the worker afterwards. The calls of merge are serialized, such that merge may write the flags into the edges of the graph.
+// Tasks without boundary nodes are skipped.
+func flagPartitions[N any, E g.IWeightedHalfEdge[W], W g.Weight](faag *g.AdjacencyArrayGraph[N, E], transposedGraph g.Graph[N, E], tasks []flagTask, workers int, merge func(task int, bitset []uint64)) {
+	if workers < 1 {
+		workers = 1
+	}
+	queue := make(chan int, len(tasks))
+	for task := range tasks {
+		if len(tasks[task].boundaryNodes) > 0 {
+			queue <- task
+		}
+	}
+	close(queue)
 
-	stack := make([]*ShortestPathTreeNode, 0)
-	stack = append(stack, &tree)
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			search := newCentralizedSearch[N, E, W](transposedGraph)
+			bitset := make([]uint64, (faag.EdgeCount()+63)/64)
+			for task := range queue {
+				flagPartition[N, E, W](faag, search, tasks[task], bitset)
+				mutex.Lock()
+				merge(task, bitset)
+				mutex.Unlock()
+				for word := range bitset {
+					bitset[word] = 0
+				}
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
 
-	for len(stack) > 0 {
-		// pop
-		node := stack[len(stack)-1]
-		stack = stack[0 : len(stack)-1]
+// flagPartition sets the bit of each edge in the bitset, which is flagged by the task.
+func flagPartition[N any, E g.IWeightedHalfEdge[W], W g.Weight](faag *g.AdjacencyArrayGraph[N, E], search *centralizedSearch[N, E, W], task flagTask, bitset []uint64) {
+	for start := 0; start < len(task.boundaryNodes); start += CENTRALIZED_SOURCES {
+		end := start + CENTRALIZED_SOURCES
+		if end > len(task.boundaryNodes) {
+			end = len(task.boundaryNodes)
+		}
+		search.run(task.boundaryNodes[start:end], task.region)
 
-		for _, child := range node.Children {
-			tailRev := node.Id  // tail node in the reversed graph
-			headRev := child.Id // head node in the reversed graph
-			jobs <- addFlagJob{from: headRev, to: tailRev, partition: partition}
-			if forwardGraph.GetNode(child.Id).Partition() != partition && !child.Visited {
-				stack = append(stack, child)
+		// an edge (u, v) is part of the shortest path DAG of a source iff w(u, v) + dist(v) = dist(u)
+		k := search.sources
+		flag := func(u g.NodeId) {
+			for i := faag.Offsets[u]; i < faag.Offsets[u+1]; i++ {
+				edge := faag.Edges[i]
+				v := edge.To()
+				if !search.contains(v) {
+					continue
+				}
+				common := search.reached[u] & search.reached[v]
+				for common != 0 {
+					source := bits.TrailingZeros64(common)
+					common &= common - 1
+					if search.distances[v*k+source]+edge.Weight() == search.distances[u*k+source] {
+						bitset[i/64] |= 1 << (i % 64)
+						break
+					}
+				}
+			}
+		}
+		if task.region == nil {
+			for u := range faag.Nodes {
+				flag(u)
 			}
-			child.Visited = true
+		} else {
+			for _, u := range task.region {
+				flag(u)
+			}
+		}
+	}
+}
+
+// forEachFlaggedEdge calls f with the index of each edge, whose bit is set in the bitset.
+func forEachFlaggedEdge(bitset []uint64, f func(i int)) {
+	for word, value := range bitset {
+		for ; value != 0; value &= value - 1 {
+			f(word*64 + bits.TrailingZeros64(value))
 		}
 	}
-	<-guard // free resources for next producer
-	wg.Done()
 }
 
-// (single) consumer
-func addFlag[N g.Partitioner, E g.IFlaggedHalfEdge[W], W g.Weight](jobs <-chan addFlagJob, faag *g.AdjacencyArrayGraph[N, E], done chan<- bool) {
-	// loop over jobs channel unti it is closed
-	for job := range jobs {
-		for i := faag.Offsets[job.from]; i < faag.Offsets[job.from+1]; i++ {
-			edge := faag.Edges[i]
-			if edge.To() == job.to {
-				faag.Edges[i] = edge.AddFlag(job.partition).(E)
-				break
+// centralizedSearch computes the distances from several sources on a graph at once, cf. Hilger et al.: "Fast Point-to-Point
+// Shortest Path Computations with Arc-Flags", 2009.
+//
+// Each node has a vector of tentative distances, one per source. A node is queued with the smallest distance of its
+// components that have changed since the node has been settled the last time. Settling a node relaxes all changed
+// components at once, such that the edges of a node are scanned once for nearby sources in most cases.
+// A node may be settled multiple times, since a component may improve after its node has been settled (label-correcting).
+type centralizedSearch[N any, E g.IWeightedHalfEdge[W], W g.Weight] struct {
+	edges     *g.HalfEdgeReader[N, E]
+	sources   int
+	distances []W        // distances[v*sources+i] is the distance from source i to node v
+	reached   []uint64   // bit i of reached[v] is set iff node v has been reached from source i
+	changed   []uint64   // bit i of changed[v] is set iff the distance from source i to node v has changed since v has been settled
+	region    []g.NodeId // nodes of the region of the last search, or nil for the entire graph
+	inRegion  []bool     // inRegion[v] is true iff node v belongs to the region of the last search
+	pq        centralizedPriorityQueue[W]
+}
+
+func newCentralizedSearch[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E]) *centralizedSearch[N, E, W] {
+	return &centralizedSearch[N, E, W]{
+		edges:     g.NewHalfEdgeReader(graph),
+		distances: make([]W, graph.NodeCount()*CENTRALIZED_SOURCES),
+		reached:   make([]uint64, graph.NodeCount()),
+		changed:   make([]uint64, graph.NodeCount()),
+		inRegion:  make([]bool, graph.NodeCount()),
+	}
+}
+
+// contains reports whether the node belongs to the region of the last search.
+func (cs *centralizedSearch[N, E, W]) contains(id g.NodeId) bool {
+	return cs.region == nil || cs.inRegion[id]
+}
+
+// run computes the distances from at most CENTRALIZED_SOURCES sources to all nodes. If a region is given, the search stops once
+// the distances from all sources to the nodes of the region are final, i.e. the distances to other nodes may be missing.
+//
+// Every distance below the priority of the queue's minimum is final, since the changed components of all queued nodes are at
+// least as large. Hence, the search stops as soon as the minimum exceeds the distances to the nodes of the region.
+func (cs *centralizedSearch[N, E, W]) run(sources []g.NodeId, region []g.NodeId) {
+	k := len(sources)
+	cs.sources = k
+	for v := range cs.reached {
+		cs.reached[v] = 0
+		cs.changed[v] = 0
+	}
+	for _, v := range cs.region {
+		cs.inRegion[v] = false
+	}
+	cs.region = region
+	for _, v := range region {
+		cs.inRegion[v] = true
+	}
+	cs.pq = cs.pq[:0]
+
+	// number of nodes of the region, which have been reached from all sources
+	all := uint64(1)<<k - 1
+	complete := 0
+	bounded, bound := false, W(0)
+	reach := func(v g.NodeId, source int) {
+		if cs.reached[v]&(1<<source) == 0 {
+			cs.reached[v] |= 1 << source
+			if cs.reached[v] == all && cs.region != nil && cs.inRegion[v] {
+				complete++
 			}
 		}
 	}
-	done <- true // announce that consumer has terminated
+
+	for i, source := range sources {
+		var zero W
+		cs.distances[source*k+i] = zero
+		reach(source, i)
+		cs.changed[source] |= 1 << i
+		heap.Push(&cs.pq, centralizedPqItem[W]{id: source, priority: zero})
+	}
+
+	for len(cs.pq) > 0 {
+		item := heap.Pop(&cs.pq).(centralizedPqItem[W])
+
+		// pruning / stopping criterion: distances only decrease, such that the bound remains an upper bound within the region
+		if region != nil && !bounded && complete == len(region) {
+			bounded = true
+			for _, v := range region {
+				for source := 0; source < k; source++ {
+					if distance := cs.distances[v*k+source]; distance > bound {
+						bound = distance
+					}
+				}
+			}
+		}
+		if bounded && item.priority >= bound {
+			break
+		}
+
+		changed := cs.changed[item.id]
+		if changed == 0 {
+			// outdated queue item
+			continue
+		}
+		cs.changed[item.id] = 0
+
+		for _, edge := range cs.edges.From(item.id) {
+			successor := edge.To()
+			improved := false
+			var priority W
+			for c := changed; c != 0; c &= c - 1 {
+				source := bits.TrailingZeros64(c)
+				distance := cs.distances[item.id*k+source] + edge.Weight()
+				if cs.reached[successor]&(1<<source) == 0 || distance < cs.distances[successor*k+source] {
+					cs.distances[successor*k+source] = distance
+					reach(successor, source)
+					cs.changed[successor] |= 1 << source
+					if !improved || distance < priority {
+						priority = distance
+					}
+					improved = true
+				}
+			}
+			if improved {
+				heap.Push(&cs.pq, centralizedPqItem[W]{id: successor, priority: priority})
+			}
+		}
+	}
+}
+
+type centralizedPqItem[W g.Weight] struct {
+	id       g.NodeId
+	priority W
+}
+
+// centralizedPriorityQueue is a min-heap of nodes without decrease-key operation: a node is pushed again if its priority decreases.
+type centralizedPriorityQueue[W g.Weight] []centralizedPqItem[W]
+
+func (pq centralizedPriorityQueue[W]) Len() int {
+	return len(pq)
+}
+
+func (pq centralizedPriorityQueue[W]) Less(i, j int) bool {
+	return pq[i].priority < pq[j].priority
+}
+
+func (pq centralizedPriorityQueue[W]) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+}
+
+func (pq *centralizedPriorityQueue[W]) Push(x any) {
+	*pq = append(*pq, x.(centralizedPqItem[W]))
+}
+
+func (pq *centralizedPriorityQueue[W]) Pop() any {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	*pq = old[0 : n-1]
+	return item
 }
@@ -0,0 +1,57 @@
+package shortest_path_test
+
+import (
+	"testing"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Compare the arc flags computed by centralized searches with the arc flags of the test graph, which have been computed by one
+// shortest path tree per boundary node. The result must not depend on the number of workers.
+func TestComputeArcFlags(t *testing.T) {
+	faag := loadAdjacencyArrayFromGob[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](arcflag64) // faag is a undirected graph
+
+	for _, workers := range []int{1, 4} {
+		computed := sp.ComputeArcFlagsParallel[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](faag, faag, 64, workers)
+		if computed.EdgeCount() != faag.EdgeCount() {
+			t.Fatalf("Expected %d edges, got %d", faag.EdgeCount(), computed.EdgeCount())
+		}
+		for i, edge := range computed.Edges {
+			if edge.Flag != faag.Edges[i].Flag {
+				t.Errorf("Expected flags %064b of edge %d, got %064b (%d workers)", faag.Edges[i].Flag, i, edge.Flag, workers)
+				break
+			}
+		}
+	}
+}
+
+// Compare the two-level arc flags computed by centralized searches with the two-level arc flags of the test graph, which have been
+// computed by one shortest path tree per boundary node: the l1 flags of shortest path DAGs contain the l1 flags of the trees, the l2
+// flags are equal. The result must not depend on the number of workers and must yield shortest paths.
+func TestComputeTwoLevelArcFlags(t *testing.T) {
+	faag := loadAdjacencyArrayFromGob[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint32, uint32]](arcflag32_32) // faag is a undirected graph
+
+	var first *g.AdjacencyArrayGraph[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint32, uint32]]
+	for _, workers := range []int{1, 4} {
+		computed := sp.ComputeTwoLevelArcFlagsParallel[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint32, uint32], int](faag, faag, workers)
+		if computed.EdgeCount() != faag.EdgeCount() {
+			t.Fatalf("Expected %d edges, got %d", faag.EdgeCount(), computed.EdgeCount())
+		}
+		for i, edge := range computed.Edges {
+			if edge.L1Flag&faag.Edges[i].L1Flag != faag.Edges[i].L1Flag || edge.L2Flag != faag.Edges[i].L2Flag {
+				t.Errorf("Expected flags %032b/%032b of edge %d, got %032b/%032b (%d workers)", faag.Edges[i].L1Flag, faag.Edges[i].L2Flag, i, edge.L1Flag, edge.L2Flag, workers)
+				break
+			}
+			if first != nil && edge != first.Edges[i] {
+				t.Errorf("Expected flags %032b/%032b of edge %d, got %032b/%032b (%d workers)", first.Edges[i].L1Flag, first.Edges[i].L2Flag, i, edge.L1Flag, edge.L2Flag, workers)
+				break
+			}
+		}
+		first = computed
+	}
+
+	testedRouter := sp.TwoLevelArcFlagRouter[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint32, uint32], int]{Graph: first}
+	baselineRouter := sp.DijkstraRouter[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint32, uint32], int]{Graph: faag}
+	DifferentialTesting(t, testedRouter, baselineRouter, faag.NodeCount())
+}
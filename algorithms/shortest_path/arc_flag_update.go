@@ -0,0 +1,204 @@
+package shortest_path
+
+import (
+	"runtime"
+	"sort"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// WeightChange describes the new weight of an edge of an AdjacencyArrayGraph, which is identified by its index in the edge slice.
+type WeightChange[W g.Weight] struct {
+	Edge   int // index of the edge in AdjacencyArrayGraph.Edges
+	Weight W   // new weight of the edge
+}
+
+// Capability description of a flagged half edge whose weight and head can be replaced, as required by UpdateArcFlags.
+type updatableFlaggedHalfEdge[W g.Weight] interface {
+	g.IFlaggedHalfEdge[W]
+	g.IReweightableHalfEdge[W]
+	g.IRetargetableHalfEdge
+}
+
+// UpdateArcFlags updates arc flags after weight changes on one worker per CPU (see UpdateArcFlagsParallel).
+func UpdateArcFlags[N g.Partitioner, E updatableFlaggedHalfEdge[W], W g.Weight](faag *g.AdjacencyArrayGraph[N, E], changes []WeightChange[W]) int {
+	return UpdateArcFlagsParallel[N, E, W](faag, changes, runtime.NumCPU())
+}
+
+// UpdateArcFlagsParallel applies the weight changes to a graph, whose arc flags have been computed by ComputeArcFlags, and updates
+// the arc flags in place, e.g. after closing a strait. The flags are recomputed only for the partitions of the affected boundary
+// nodes, i.e. the boundary nodes whose shortest path DAG in the transposed graph contains a changed edge before or after the change.
+// The number of affected boundary nodes is returned.
+//
+// A boundary node b is affected by the change of the weight of the edge (u, v) from w to w' iff
+//   - w' > w and the edge is part of a shortest path from u to b, i.e. w + dist(v, b) = dist(u, b), or
+//   - w' < w and the edge becomes part of a shortest path from u to b, i.e. w' + dist(v, b) <= dist(u, b).
+//
+// Otherwise, neither the distances to b nor its shortest path DAG change. The distances of the graph before the change are computed
+// by centralized searches from the tails and heads of the changed edges, the transposed graph is derived from the forward graph.
+//
+// The flags of each partition with an affected boundary node are recomputed from all boundary nodes of the partition and
+// replace the previous flags of the partition, such that the updated flags equal the flags computed by ComputeArcFlags for the
+// changed graph, cf. Berrettini et al.: "Arc-Flags in Dynamic Graphs", 2009. The flags of the other partitions remain unchanged.
+func UpdateArcFlagsParallel[N g.Partitioner, E updatableFlaggedHalfEdge[W], W g.Weight](faag *g.AdjacencyArrayGraph[N, E], changes []WeightChange[W], workers int) int {
+	tails := make([]g.NodeId, faag.EdgeCount())
+	for tailNodeId := range faag.Nodes {
+		for i := faag.Offsets[tailNodeId]; i < faag.Offsets[tailNodeId+1]; i++ {
+			tails[i] = tailNodeId
+		}
+	}
+
+	// only changes of the weight can affect the flags
+	effectiveChanges := make([]WeightChange[W], 0, len(changes))
+	for _, change := range changes {
+		if change.Weight != faag.Edges[change.Edge].Weight() {
+			effectiveChanges = append(effectiveChanges, change)
+		}
+	}
+	if len(effectiveChanges) == 0 {
+		return 0
+	}
+
+	// determine the boundary nodes of all partitions in ascending order
+	boundaryNodeSet := make(map[g.NodeId]struct{})
+	boundaryNodes := make([]g.NodeId, 0)
+	for i, edge := range faag.Edges {
+		head := edge.To()
+		if faag.GetNode(tails[i]).Partition() != faag.GetNode(head).Partition() {
+			if _, ok := boundaryNodeSet[head]; !ok {
+				boundaryNodeSet[head] = struct{}{}
+				boundaryNodes = append(boundaryNodes, head)
+			}
+		}
+	}
+	sort.Ints(boundaryNodes)
+
+	// distances from the tails and heads of the changed edges to all boundary nodes before the change
+	endpointIndex := make(map[g.NodeId]int)
+	endpoints := make([]g.NodeId, 0)
+	for _, change := range effectiveChanges {
+		for _, endpoint := range []g.NodeId{tails[change.Edge], faag.Edges[change.Edge].To()} {
+			if _, ok := endpointIndex[endpoint]; !ok {
+				endpointIndex[endpoint] = len(endpoints)
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	distances := make([][]W, len(endpoints))
+	reached := make([][]bool, len(endpoints))
+	search := newCentralizedSearch[N, E, W](faag)
+	for start := 0; start < len(endpoints); start += CENTRALIZED_SOURCES {
+		end := start + CENTRALIZED_SOURCES
+		if end > len(endpoints) {
+			end = len(endpoints)
+		}
+		search.run(endpoints[start:end], nil)
+		k := search.sources
+		for source := 0; source < k; source++ {
+			distances[start+source] = make([]W, len(boundaryNodes))
+			reached[start+source] = make([]bool, len(boundaryNodes))
+			for index, boundaryNodeId := range boundaryNodes {
+				if search.reached[boundaryNodeId]&(1<<source) != 0 {
+					distances[start+source][index] = search.distances[boundaryNodeId*k+source]
+					reached[start+source][index] = true
+				}
+			}
+		}
+	}
+
+	// determine the affected boundary nodes and apply the changes
+	affected := make([]bool, len(boundaryNodes))
+	for _, change := range effectiveChanges {
+		edge := faag.Edges[change.Edge]
+		u, v := endpointIndex[tails[change.Edge]], endpointIndex[edge.To()]
+		for index := range boundaryNodes {
+			if !reached[v][index] {
+				continue
+			}
+			if change.Weight > edge.Weight() && distances[v][index]+edge.Weight() == distances[u][index] {
+				affected[index] = true
+			} else if change.Weight < edge.Weight() && distances[v][index]+change.Weight <= distances[u][index] {
+				affected[index] = true
+			}
+		}
+	}
+	for _, change := range effectiveChanges {
+		faag.Edges[change.Edge] = faag.Edges[change.Edge].SetWeight(change.Weight).(E)
+	}
+
+	// recompute the flags of each partition with an affected boundary node from all of its boundary nodes
+	flagRange := faag.Edges[0].FlagRange()
+	affectedCount := 0
+	affectedPartitions := make([]bool, flagRange)
+	for index, boundaryNodeId := range boundaryNodes {
+		if affected[index] {
+			affectedPartitions[faag.GetNode(boundaryNodeId).Partition()] = true
+			affectedCount++
+		}
+	}
+	recomputed := make([]g.PartitionId, 0)
+	for partition := g.PartitionId(0); partition < flagRange; partition++ {
+		if affectedPartitions[partition] {
+			recomputed = append(recomputed, partition)
+		}
+	}
+	tasks := make([]flagTask, flagRange)
+	for _, boundaryNodeId := range boundaryNodes {
+		if partition := faag.GetNode(boundaryNodeId).Partition(); affectedPartitions[partition] {
+			tasks[partition].boundaryNodes = append(tasks[partition].boundaryNodes, boundaryNodeId)
+		}
+	}
+
+	// remove the flags of the recomputed partitions, edges within a partition remain flagged for their partition
+	for tailNodeId := range faag.Nodes {
+		tailPartition := faag.GetNode(tailNodeId).Partition()
+		for i := faag.Offsets[tailNodeId]; i < faag.Offsets[tailNodeId+1]; i++ {
+			edge := faag.Edges[i]
+			internal := tailPartition == faag.GetNode(edge.To()).Partition()
+			keep := func(partition g.PartitionId) bool {
+				return !affectedPartitions[partition] || (internal && partition == tailPartition)
+			}
+
+			stale := false
+			for _, partition := range recomputed {
+				stale = stale || (edge.IsFlagged(partition) && !keep(partition))
+			}
+			if stale {
+				// flags can only be removed by resetting all flags of the edge
+				updated := edge.ResetFlag().(E)
+				for partition := g.PartitionId(0); partition < flagRange; partition++ {
+					if edge.IsFlagged(partition) && keep(partition) {
+						updated = updated.AddFlag(partition).(E)
+					}
+				}
+				faag.Edges[i] = updated
+			}
+		}
+	}
+
+	flagPartitions[N, E, W](faag, transpose[N, E](faag, tails), tasks, workers, func(partition int, bitset []uint64) {
+		forEachFlaggedEdge(bitset, func(i int) {
+			faag.Edges[i] = faag.Edges[i].AddFlag(g.PartitionId(partition)).(E)
+		})
+	})
+
+	return affectedCount
+}
+
+// transpose creates the transposed graph of an adjacency array, given the tail of each edge.
+func transpose[N any, E g.IRetargetableHalfEdge](aag *g.AdjacencyArrayGraph[N, E], tails []g.NodeId) *g.AdjacencyArrayGraph[N, E] {
+	offsets := make([]int, aag.NodeCount()+1)
+	for _, edge := range aag.Edges {
+		offsets[edge.To()+1]++
+	}
+	for id := 0; id < aag.NodeCount(); id++ {
+		offsets[id+1] += offsets[id]
+	}
+	positions := append([]int(nil), offsets[:aag.NodeCount()]...)
+	edges := make([]E, aag.EdgeCount())
+	for i, edge := range aag.Edges {
+		edges[positions[edge.To()]] = edge.SetTo(tails[i]).(E)
+		positions[edge.To()]++
+	}
+	return &g.AdjacencyArrayGraph[N, E]{Nodes: aag.Nodes, Edges: edges, Offsets: offsets}
+}
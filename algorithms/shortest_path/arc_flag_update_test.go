@@ -0,0 +1,75 @@
+package shortest_path_test
+
+import (
+	"testing"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Increase and decrease the weights of some edges of the test graph in both directions, update the arc flags incrementally and
+// compare the updated flags with the flags computed from scratch as well as ArcFlagDijkstra with textbook Dijkstra.
+func TestUpdateArcFlags(t *testing.T) {
+	faag := loadAdjacencyArrayFromGob[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](arcflag64) // faag is a undirected graph
+	faag = sp.ComputeArcFlagsParallel[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](faag, faag, 64, 1)
+
+	if affected := sp.UpdateArcFlags[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](faag, []sp.WeightChange[int]{{Edge: 0, Weight: faag.Edges[0].Weight()}}); affected != 0 {
+		t.Errorf("Expected 0 affected boundary nodes for unchanged weights, got %d", affected)
+	}
+
+	// close the first edge of a few nodes and shorten the first edge of a few others
+	weights := make(map[int]int)
+	for k, tailNodeId := range []g.NodeId{3000, 6500, 6000} {
+		for i := faag.Offsets[tailNodeId]; i < faag.Offsets[tailNodeId]+1; i++ {
+			weight := 100 * faag.Edges[i].Weight()
+			if k >= 2 {
+				weight = faag.Edges[i].Weight() / 2
+			}
+			weights[i] = weight
+
+			// change the reverse edge as well
+			headNodeId := faag.Edges[i].To()
+			for j := faag.Offsets[headNodeId]; j < faag.Offsets[headNodeId+1]; j++ {
+				if faag.Edges[j].To() == tailNodeId {
+					weights[j] = weight
+				}
+			}
+		}
+	}
+	changes := make([]sp.WeightChange[int], 0, len(weights))
+	for i, weight := range weights {
+		changes = append(changes, sp.WeightChange[int]{Edge: i, Weight: weight})
+	}
+
+	affected := sp.UpdateArcFlagsParallel[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](faag, changes, 4)
+	boundaryNodes := make(map[g.NodeId]struct{})
+	for tailNodeId := range faag.Nodes {
+		for _, edge := range faag.GetHalfEdgesFrom(tailNodeId) {
+			if faag.GetNode(tailNodeId).Partition() != faag.GetNode(edge.To()).Partition() {
+				boundaryNodes[edge.To()] = struct{}{}
+			}
+		}
+	}
+	t.Logf("Applied %d weight changes, recomputed %d of %d boundary nodes", len(changes), affected, len(boundaryNodes))
+	if affected == 0 || affected >= len(boundaryNodes) {
+		t.Errorf("Expected a proper subset of the %d boundary nodes to be affected, got %d", len(boundaryNodes), affected)
+	}
+	for _, change := range changes {
+		if faag.Edges[change.Edge].Weight() != change.Weight {
+			t.Fatalf("Expected weight %d of edge %d, got %d", change.Weight, change.Edge, faag.Edges[change.Edge].Weight())
+		}
+	}
+
+	// outdated flags are removed, i.e. the updated flags equal the flags of the changed graph
+	computed := sp.ComputeArcFlagsParallel[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](faag, faag, 64, 1)
+	for i, edge := range computed.Edges {
+		if edge.Flag != faag.Edges[i].Flag {
+			t.Fatalf("Expected flags %064b of edge %d, got %064b", edge.Flag, i, faag.Edges[i].Flag)
+		}
+	}
+
+	testedRouter := sp.ArcFlagRouter[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int]{Graph: faag}
+	baselineRouter := sp.DijkstraRouter[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int]{Graph: faag}
+
+	DifferentialTesting(t, testedRouter, baselineRouter, faag.NodeCount())
+}
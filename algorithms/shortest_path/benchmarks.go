@@ -75,12 +75,30 @@ func (b *Benchmarker[W]) Run(n int) BenchmarkSummary {
 	for i := 0; i < n; i++ {
 		source := rand.Intn(b.NodeRange)
 		target := rand.Intn(b.NodeRange)
+		b.measure(source, target)
+	}
+	return b.Result.Summarize()
+}
 
-		start := time.Now()
-		routingResult := b.Router.Route(source, target, false)
-		time := math.Round(float64(time.Since(start))/1000) / 1000 // ms
+// Query is a shortest path query from a source to a target node.
+type Query struct {
+	Source g.NodeId
+	Target g.NodeId
+}
 
-		b.Result.Add(time, routingResult.PqPops)
+// RunQueries benchmarks the router on a fixed set of queries, e.g. the queries of a published benchmark instance, instead of random queries.
+func (b *Benchmarker[W]) RunQueries(queries []Query) BenchmarkSummary {
+	for _, query := range queries {
+		b.measure(query.Source, query.Target)
 	}
 	return b.Result.Summarize()
 }
+
+// measure routes a single query and adds the observation to the benchmark result.
+func (b *Benchmarker[W]) measure(source, target g.NodeId) {
+	start := time.Now()
+	routingResult := b.Router.Route(source, target, false)
+	time := math.Round(float64(time.Since(start))/1000) / 1000 // ms
+
+	b.Result.Add(time, routingResult.PqPops)
+}
@@ -60,6 +60,8 @@ func (r BidirectionalAStarRouter[N, E, W]) Route(source, target g.NodeId, record
 
 	middleNodeId := -1
 
+	forwardEdges := g.NewHalfEdgeReader(r.Graph)
+	backwardEdges := g.NewHalfEdgeReader(r.Transpose)
 	pqPops := 0
 	for len(pqForward) > 0 && len(pqBackward) > 0 {
 		forwardPqItem := heap.Pop(&pqForward).(*AStarPqItem[W])
@@ -81,7 +83,7 @@ func (r BidirectionalAStarRouter[N, E, W]) Route(source, target g.NodeId, record
 		}
 
 		// forward search
-		for _, edge := range r.Graph.GetHalfEdgesFrom(forwardNodeId) {
+		for _, edge := range forwardEdges.From(forwardNodeId) {
 			successor := edge.To()
 			// improvement by Kwa: An admissible bidirectional staged heuristic search algorithm
 			if mu < r.MaxInitializerValue && dijkstraItemsBackward[successor] != nil && backwardSettled[successor] == true {
@@ -141,7 +143,7 @@ func (r BidirectionalAStarRouter[N, E, W]) Route(source, target g.NodeId, record
 		}
 
 		// backward search
-		for _, edge := range r.Transpose.GetHalfEdgesFrom(backwardNodeId) {
+		for _, edge := range backwardEdges.From(backwardNodeId) {
 			successor := edge.To()
 			// improvement by Kwa: An admissible bidirectional staged heuristic search algorithm
 			if mu < r.MaxInitializerValue && dijkstraItemsForward[successor] != nil && forwardSettled[successor] == true {
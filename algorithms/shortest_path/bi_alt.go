@@ -0,0 +1,134 @@
+package shortest_path
+
+import (
+	"container/heap"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// BidirectionalAltRouter implements the Router interface and provides bidirectional ALT search (A*, landmarks and triangular inequalities).
+//
+// In contrast to BidirectionalAStarRouter with two independent heuristics, the forward and the backward search use consistent
+// potentials: the average of the landmark lower bounds to the target and from the source (Ikeda et al., 1994).
+// Hence, the search is equivalent to bidirectional Dijkstra on a graph with nonnegative reduced edge weights, which guarantees
+// shortest paths with the stopping criterion of bidirectional Dijkstra.
+type BidirectionalAltRouter[N any, E g.IWeightedHalfEdge[W], W g.Weight] struct {
+	Graph     g.Graph[N, E]
+	Transpose g.Graph[N, E]
+
+	// Heuristic provides the landmark distances. The active landmarks are selected per query by Init if ActiveLandmarkCount
+	// is set; UpdateInterval is ignored, since changing the potentials during the search would break their consistency.
+	Heuristic *AltHeuristic[W]
+
+	MaxInitializerValue W
+}
+
+// String implements fmt.Stringer
+func (r BidirectionalAltRouter[N, E, W]) String() string {
+	return "Bidirectional ALT"
+}
+
+// Bidirectional ALT with average potentials following Goldberg and Harrelson: "Computing the Shortest Path: A* Search meets Graph Theory", 2005
+//
+// The forward potential of a node v is p(v) = (pi_t(v) - pi_s(v)) / 2, where pi_t(v) is the lower bound for the distance from v to the target
+// and pi_s(v) is the lower bound for the distance from the source to v. The backward potential is -p(v). The priorities of both searches are doubled
+// to avoid the division, such that the search terminates as soon as the sum of the minimum priorities reaches twice the tentative distance.
+func (r BidirectionalAltRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSpace bool) ShortestPathResult[W] {
+	var searchSpace []g.NodeId = nil
+	if recordSearchSpace {
+		searchSpace = make([]g.NodeId, 0)
+	}
+
+	// handle trivial search with source and target being the same node
+	if source == target {
+		return ShortestPathResult[W]{Length: W(0), Path: []g.NodeId{source}, PqPops: 0, SearchSpace: searchSpace}
+	}
+
+	r.Heuristic.Init(source, target)
+	landmarks := r.Heuristic.ActiveLandmarks
+	// doubled forward potential
+	potential := func(id g.NodeId) W {
+		toTarget, fromSource := W(0), W(0)
+		for _, landmark := range landmarks {
+			toTarget = max(toTarget, landmarkBound(landmark, id, target))
+			fromSource = max(fromSource, landmarkBound(landmark, source, id))
+		}
+		return toTarget - fromSource
+	}
+
+	// index 0 refers to the forward search, index 1 to the backward search
+	edges := [2]*g.HalfEdgeReader[N, E]{g.NewHalfEdgeReader(r.Graph), g.NewHalfEdgeReader(r.Transpose)}
+	signs := [2]W{1, -1}
+	dijkstraItems := [2][]*AStarPqItem[W]{make([]*AStarPqItem[W], r.Graph.NodeCount()), make([]*AStarPqItem[W], r.Graph.NodeCount())}
+	settled := [2][]bool{make([]bool, r.Graph.NodeCount()), make([]bool, r.Graph.NodeCount())}
+	pqs := [2]AStarPriorityQueue[W]{make(AStarPriorityQueue[W], 0), make(AStarPriorityQueue[W], 0)}
+	for direction, root := range []g.NodeId{source, target} {
+		dijkstraItems[direction][root] = &AStarPqItem[W]{Id: root, Distance: 0, Priority: signs[direction] * potential(root), Predecessor: -1}
+		heap.Init(&pqs[direction])
+		heap.Push(&pqs[direction], dijkstraItems[direction][root])
+	}
+
+	// Once the algorithm terminates, mu contains the shortest path distance between source and target.
+	mu := r.MaxInitializerValue // initialize with the largest representable number of weight type W
+	middleNodeId := -1
+
+	pqPops := 0
+	for len(pqs[0]) > 0 && len(pqs[1]) > 0 {
+		// stopping criterion of bidirectional Dijkstra on the reduced weights
+		if mu < r.MaxInitializerValue && pqs[0][0].Priority+pqs[1][0].Priority >= 2*mu {
+			break
+		}
+
+		// continue the search with the smaller minimum priority
+		direction := 0
+		if pqs[1][0].Priority < pqs[0][0].Priority {
+			direction = 1
+		}
+		currentPqItem := heap.Pop(&pqs[direction]).(*AStarPqItem[W])
+		currentNodeId := currentPqItem.Id
+		settled[direction][currentNodeId] = true
+		pqPops++
+
+		if recordSearchSpace {
+			searchSpace = append(searchSpace, currentNodeId)
+		}
+
+		for _, edge := range edges[direction].From(currentNodeId) {
+			successor := edge.To()
+			newDistance := currentPqItem.Distance + edge.Weight()
+
+			if dijkstraItems[direction][successor] == nil {
+				pqItem := AStarPqItem[W]{Id: successor, Distance: newDistance, Priority: 2*newDistance + signs[direction]*potential(successor), Predecessor: currentNodeId}
+				dijkstraItems[direction][successor] = &pqItem
+				heap.Push(&pqs[direction], &pqItem)
+			} else if pqItem := dijkstraItems[direction][successor]; newDistance < pqItem.Distance && !settled[direction][successor] {
+				// the potential of the successor remains unchanged
+				pqItem.Priority += 2 * (newDistance - pqItem.Distance)
+				pqItem.Distance = newDistance
+				pqItem.Predecessor = currentNodeId
+				heap.Fix(&pqs[direction], pqItem.index)
+			}
+
+			if x := dijkstraItems[1-direction][successor]; x != nil {
+				if mu_new := dijkstraItems[direction][successor].Distance + x.Distance; mu_new < mu {
+					mu = mu_new
+					middleNodeId = successor
+				}
+			}
+		}
+	}
+
+	res := ShortestPathResult[W]{Length: W(-1), Path: make([]g.NodeId, 0), PqPops: pqPops, SearchSpace: searchSpace}
+
+	// check if path exists
+	if mu < r.MaxInitializerValue {
+		res.Length = mu
+		for nodeId := middleNodeId; nodeId != -1; nodeId = dijkstraItems[0][nodeId].Predecessor {
+			res.Path = append([]int{nodeId}, res.Path...)
+		}
+		for nodeId := dijkstraItems[1][middleNodeId].Predecessor; nodeId != -1; nodeId = dijkstraItems[1][nodeId].Predecessor {
+			res.Path = append(res.Path, nodeId)
+		}
+	}
+	return res
+}
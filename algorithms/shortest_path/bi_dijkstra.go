@@ -58,6 +58,8 @@ func (r BiDijkstraRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSp
 
 	middleNodeId := -1
 
+	forwardEdges := g.NewHalfEdgeReader(r.Graph)
+	backwardEdges := g.NewHalfEdgeReader(r.Transpose)
 	pqPops := 0
 	for len(pqForward) > 0 && len(pqBackward) > 0 {
 		forwardPqItem := heap.Pop(&pqForward).(*DijkstraPqItem[W])
@@ -72,7 +74,7 @@ func (r BiDijkstraRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSp
 		}
 
 		// forward search
-		for _, edge := range r.Graph.GetHalfEdgesFrom(forwardNodeId) {
+		for _, edge := range forwardEdges.From(forwardNodeId) {
 			successor := edge.To()
 
 			if dijkstraItemsForward[successor] == nil {
@@ -96,7 +98,7 @@ func (r BiDijkstraRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSp
 		}
 
 		// backward search
-		for _, edge := range r.Graph.GetHalfEdgesFrom(backwardNodeId) {
+		for _, edge := range backwardEdges.From(backwardNodeId) {
 			successor := edge.To()
 
 			if dijkstraItemsBackward[successor] == nil {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	fmi "github.com/dmholtz/graffiti/examples/io"
 	g "github.com/dmholtz/graffiti/graph"
 )
 
@@ -17,3 +18,27 @@ func TestBidirectionalDijkstra(t *testing.T) {
 
 	DifferentialTesting(t, testedRouter, baselineRouter, aag.NodeCount())
 }
+
+// Differential testing on a directed graph, whose backward search must explore the transpose instead of the forward graph
+func TestBidirectionalDijkstraDirected(t *testing.T) {
+	aag, err := fmi.NewAdjacencyArrayFromDimacs(asymmetricGraphFile, "", g.KEEP_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder := g.NewAdjacencyArrayBuilder[g.GeoPoint, g.WeightedHalfEdge[int], int](g.KEEP_ALL)
+	builder.AddNodes(aag.Nodes)
+	for tail := 0; tail < aag.NodeCount(); tail++ {
+		for _, edge := range aag.GetHalfEdgesFrom(tail) {
+			builder.AddEdge(edge.To(), g.WeightedHalfEdge[int]{To_: tail, Weight_: edge.Weight()})
+		}
+	}
+	transpose, _, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testedRouter := sp.BiDijkstraRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Transpose: transpose, MaxInitializerValue: math.MaxInt}
+	baselineRouter := sp.DijkstraRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag}
+
+	DifferentialTesting(t, testedRouter, baselineRouter, aag.NodeCount())
+}
@@ -0,0 +1,26 @@
+package shortest_path_test
+
+import (
+	"testing"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	"github.com/dmholtz/graffiti/algorithms/statistics"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Differential testing: Compare the output of ArcFlagDijkstra with deduplicated flag vectors with textbook Dijkstra
+func TestCompressedArcFlagDijkstra(t *testing.T) {
+	faag := loadAdjacencyArrayFromGob[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](arcflag64) // faag is a undirected graph
+
+	report := statistics.AnalyzeFlagCompression[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](faag)
+	t.Logf("Flag compression of %s:\n%s", arcflag64, report)
+	if report.UniqueVectors >= report.EdgeCount/2 {
+		t.Errorf("Expected less than %d distinct flag vectors, got %d", report.EdgeCount/2, report.UniqueVectors)
+	}
+
+	caag := g.CompressFlags[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](faag)
+	testedRouter := sp.ArcFlagRouter[g.PartGeoPoint, g.CompressedFlaggedHalfEdge[int], int]{Graph: caag}
+	baselineRouter := sp.DijkstraRouter[g.PartGeoPoint, g.CompressedFlaggedHalfEdge[int], int]{Graph: caag}
+
+	DifferentialTesting(t, testedRouter, baselineRouter, caag.NodeCount())
+}
@@ -30,6 +30,7 @@ func (r DijkstraRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSpac
 	heap.Init(&pq)
 	heap.Push(&pq, dijkstraItems[source])
 
+	edges := g.NewHalfEdgeReader(r.Graph)
 	pqPops := 0
 	for len(pq) > 0 {
 		currentPqItem := heap.Pop(&pq).(*DijkstraPqItem[W])
@@ -40,7 +41,7 @@ func (r DijkstraRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSpac
 			searchSpace = append(searchSpace, currentNodeId)
 		}
 
-		for _, edge := range r.Graph.GetHalfEdgesFrom(currentNodeId) {
+		for _, edge := range edges.From(currentNodeId) {
 			successor := edge.To()
 
 			if dijkstraItems[successor] == nil {
@@ -82,13 +83,14 @@ func DijkstraOneToAll[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph
 	heap.Init(&pq)
 	heap.Push(&pq, dijkstraItems[source])
 
+	edges := g.NewHalfEdgeReader(graph)
 	pqPops := 0
 	for len(pq) > 0 {
 		currentPqItem := heap.Pop(&pq).(*DijkstraPqItem[W])
 		currentNodeId := currentPqItem.Id
 		pqPops++
 
-		for _, edge := range graph.GetHalfEdgesFrom(currentNodeId) {
+		for _, edge := range edges.From(currentNodeId) {
 			successor := edge.To()
 
 			if dijkstraItems[successor] == nil {
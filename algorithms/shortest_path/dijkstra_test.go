@@ -31,3 +31,14 @@ func TestOneToAllDijkstra(t *testing.T) {
 		}
 	}
 }
+
+// Differential testing: Compare Dijkstra on a VarintGraph, whose edges are decoded by a HalfEdgeReader, with Dijkstra on an adjacency array
+func TestDijkstraVarintGraph(t *testing.T) {
+	aag := loadAdjacencyArrayFromGob[g.GeoPoint, g.WeightedHalfEdge[int]](defaultGraphFile)
+	vg := g.NewVarintGraph[g.GeoPoint, g.WeightedHalfEdge[int], int](aag)
+
+	testedRouter := sp.DijkstraRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: vg}
+	baselineRouter := sp.DijkstraRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag}
+
+	DifferentialTesting(t, testedRouter, baselineRouter, aag.NodeCount())
+}
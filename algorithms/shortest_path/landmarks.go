@@ -0,0 +1,394 @@
+package shortest_path
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Landmark selection strategies for ALT following Goldberg and Harrelson (2005) as well as Goldberg and Werneck (2005).
+//
+// All strategies are deterministic for a given seed. Since landmarks in small, disconnected components of the graph
+// do not contribute to the quality of the heuristic, landmarks are only chosen from the strongly connected component
+// of a random start node. The start node is drawn repeatedly until its component contains at least half of the nodes.
+
+// number of landmark candidates per landmark that are generated by MaxCoverLandmarks
+const maxCoverCandidateFactor = 4
+
+// maximum number of local search rounds of MaxCoverLandmarks
+const maxCoverRounds = 8
+
+// maximum number of attempts to draw a start node in a large component
+const maxStartAttempts = 16
+
+// FarthestLandmarks chooses n landmarks iteratively: The next landmark is the node whose round-trip distance to the
+// closest landmark is maximal. The first landmark is the node farthest away from a random start node.
+func FarthestLandmarks[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpose g.Graph[N, E], n int, seed int64) []g.NodeId {
+	s := newLandmarkSelection[N, E, W](graph, transpose, seed)
+	for len(s.landmarks) < n {
+		if !s.add(s.farthest()) {
+			break
+		}
+	}
+	return s.landmarkIds()
+}
+
+// AvoidLandmarks chooses n landmarks with the avoid heuristic: For a random root node, the shortest path tree is
+// weighted by the gap between the distance from the root and its lower bound based on the current landmarks.
+// The next landmark is a leaf in the subtree with the largest total gap that does not contain a landmark yet,
+// i.e. a landmark in the region of the graph that is covered worst by the current landmarks.
+func AvoidLandmarks[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpose g.Graph[N, E], n int, seed int64) []g.NodeId {
+	s := newLandmarkSelection[N, E, W](graph, transpose, seed)
+	for len(s.landmarks) < n {
+		if !s.add(s.avoid()) {
+			break
+		}
+	}
+	return s.landmarkIds()
+}
+
+// PlanarLandmarks chooses n landmarks for a graph embedded on the globe: The graph is divided into n sectors of equal
+// angle around the node next to the geographic center, and the landmark of each sector is the node farthest away from the center.
+// Empty sectors are compensated by additional landmarks chosen as in FarthestLandmarks.
+func PlanarLandmarks[N g.Locator, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpose g.Graph[N, E], n int, seed int64) []g.NodeId {
+	s := newLandmarkSelection[N, E, W](graph, transpose, seed)
+	if n <= 0 {
+		return s.landmarkIds()
+	}
+
+	// center: node next to the mean of the locations of all candidate nodes
+	var cx, cy, cz float64
+	for id, eligible := range s.eligible {
+		if eligible {
+			x, y, z := unitVector(graph.GetNode(id).Location())
+			cx, cy, cz = cx+x, cy+y, cz+z
+		}
+	}
+	center, bestDot := -1, math.Inf(-1)
+	for id, eligible := range s.eligible {
+		if eligible {
+			x, y, z := unitVector(graph.GetNode(id).Location())
+			if dot := x*cx + y*cy + z*cz; dot > bestDot {
+				center, bestDot = id, dot
+			}
+		}
+	}
+	centerDistances := computeLandmarkDistances[N, E, W](graph, transpose, center)
+	centerLocation := graph.GetNode(center).Location()
+
+	// farthest node of each sector
+	sectors := make([]g.NodeId, n)
+	for i := range sectors {
+		sectors[i] = -1
+	}
+	for id, eligible := range s.eligible {
+		if !eligible || id == center {
+			continue
+		}
+		sector := int(bearing(centerLocation, graph.GetNode(id).Location()) / (2 * math.Pi) * float64(n))
+		if sector >= n {
+			sector = n - 1
+		}
+		if sectors[sector] == -1 || roundTrip(centerDistances, id) > roundTrip(centerDistances, sectors[sector]) {
+			sectors[sector] = id
+		}
+	}
+	for _, landmark := range sectors {
+		if landmark != -1 {
+			s.add(landmark)
+		}
+	}
+	for len(s.landmarks) < n {
+		if !s.add(s.farthest()) {
+			break
+		}
+	}
+	return s.landmarkIds()
+}
+
+// MaxCoverLandmarks chooses n landmarks out of a larger set of candidates generated by AvoidLandmarks, such that the number
+// of covered edges is maximized by a local search. An edge (u,v) is covered by a landmark L if it lies on a shortest path
+// from L or to L, i.e. if its reduced cost with respect to the potential of L is zero.
+func MaxCoverLandmarks[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpose g.Graph[N, E], n int, seed int64) []g.NodeId {
+	s := newLandmarkSelection[N, E, W](graph, transpose, seed)
+	for len(s.landmarks) < maxCoverCandidateFactor*n {
+		if !s.add(s.avoid()) {
+			break
+		}
+	}
+	if len(s.landmarks) <= n {
+		return s.landmarkIds()
+	}
+
+	// bitset of the edges covered by each candidate
+	edgeCount := 0
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		edgeCount += len(graph.GetHalfEdgesFrom(tail))
+	}
+	words := (edgeCount + 63) / 64
+	covers := make([][]uint64, len(s.landmarks))
+	for i, landmark := range s.landmarks {
+		covers[i] = make([]uint64, words)
+		k := 0
+		for tail := 0; tail < graph.NodeCount(); tail++ {
+			for _, edge := range graph.GetHalfEdgesFrom(tail) {
+				head := edge.To()
+				if s.eligible[tail] && s.eligible[head] &&
+					(landmark.From[tail]+edge.Weight() == landmark.From[head] || landmark.To[head]+edge.Weight() == landmark.To[tail]) {
+					covers[i][k/64] |= 1 << (k % 64)
+				}
+				k++
+			}
+		}
+	}
+	coverage := func(chosen []int) int {
+		count := 0
+		for w := 0; w < words; w++ {
+			union := uint64(0)
+			for _, c := range chosen {
+				union |= covers[c][w]
+			}
+			count += bits.OnesCount64(union)
+		}
+		return count
+	}
+
+	// greedy initialization
+	chosen := make([]int, 0, n)
+	isChosen := make([]bool, len(s.landmarks))
+	for len(chosen) < n {
+		best, bestCoverage := -1, -1
+		for c := range s.landmarks {
+			if !isChosen[c] {
+				if cov := coverage(append(chosen, c)); cov > bestCoverage {
+					best, bestCoverage = c, cov
+				}
+			}
+		}
+		chosen = append(chosen, best)
+		isChosen[best] = true
+	}
+
+	// local search: replace chosen landmarks by candidates as long as the coverage improves
+	current := coverage(chosen)
+	for round := 0; round < maxCoverRounds; round++ {
+		improved := false
+		for i := range chosen {
+			for c := range s.landmarks {
+				if isChosen[c] {
+					continue
+				}
+				previous := chosen[i]
+				chosen[i] = c
+				if cov := coverage(chosen); cov > current {
+					current, improved = cov, true
+					isChosen[previous], isChosen[c] = false, true
+				} else {
+					chosen[i] = previous
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	landmarks := make([]g.NodeId, len(chosen))
+	for i, c := range chosen {
+		landmarks[i] = s.landmarks[c].Landmark
+	}
+	return landmarks
+}
+
+// landmarkSelection maintains the landmarks that have been selected so far and their distances.
+type landmarkSelection[N any, E g.IWeightedHalfEdge[W], W g.Weight] struct {
+	graph, transpose g.Graph[N, E]
+	rng              *rand.Rand
+	start            LandmarkDistances[W]   // distances from and to the start node
+	eligible         []bool                 // nodes that are strongly connected with the start node
+	landmarks        []LandmarkDistances[W] // distances of the selected landmarks
+	selected         []bool
+	nearest          []W // round-trip distance of each node to the closest landmark
+}
+
+func newLandmarkSelection[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph, transpose g.Graph[N, E], seed int64) *landmarkSelection[N, E, W] {
+	s := landmarkSelection[N, E, W]{
+		graph:     graph,
+		transpose: transpose,
+		rng:       rand.New(rand.NewSource(seed)),
+		landmarks: make([]LandmarkDistances[W], 0),
+		selected:  make([]bool, graph.NodeCount()),
+		nearest:   make([]W, graph.NodeCount()),
+	}
+	if graph.NodeCount() == 0 {
+		return &s
+	}
+
+	// choose a start node in a large strongly connected component
+	bestSize := -1
+	for attempt := 0; attempt < maxStartAttempts && 2*bestSize < graph.NodeCount(); attempt++ {
+		start := computeLandmarkDistances[N, E, W](graph, transpose, s.rng.Intn(graph.NodeCount()))
+		size := 0
+		for id := range start.From {
+			if start.From[id] >= 0 && start.To[id] >= 0 {
+				size++
+			}
+		}
+		if size > bestSize {
+			s.start, bestSize = start, size
+		}
+	}
+	s.eligible = make([]bool, graph.NodeCount())
+	for id := range s.eligible {
+		s.eligible[id] = s.start.From[id] >= 0 && s.start.To[id] >= 0
+	}
+	return &s
+}
+
+// add selects a landmark and returns false iff the landmark is invalid or has already been selected.
+func (s *landmarkSelection[N, E, W]) add(landmark g.NodeId) bool {
+	if landmark < 0 || s.selected[landmark] {
+		return false
+	}
+	distances := computeLandmarkDistances[N, E, W](s.graph, s.transpose, landmark)
+	for id := range s.nearest {
+		if d := roundTrip(distances, id); len(s.landmarks) == 0 || d < s.nearest[id] {
+			s.nearest[id] = d
+		}
+	}
+	s.landmarks = append(s.landmarks, distances)
+	s.selected[landmark] = true
+	return true
+}
+
+func (s *landmarkSelection[N, E, W]) landmarkIds() []g.NodeId {
+	landmarks := make([]g.NodeId, len(s.landmarks))
+	for i, distances := range s.landmarks {
+		landmarks[i] = distances.Landmark
+	}
+	return landmarks
+}
+
+// farthest returns the candidate node with the largest round-trip distance to the closest landmark (or to the start node if
+// there are no landmarks yet) and -1 if all candidates have been selected.
+func (s *landmarkSelection[N, E, W]) farthest() g.NodeId {
+	farthest := -1
+	for id, eligible := range s.eligible {
+		if !eligible || s.selected[id] {
+			continue
+		}
+		if farthest == -1 || s.distance(id) > s.distance(farthest) {
+			farthest = id
+		}
+	}
+	return farthest
+}
+
+func (s *landmarkSelection[N, E, W]) distance(id g.NodeId) W {
+	if len(s.landmarks) == 0 {
+		return roundTrip(s.start, id)
+	}
+	return s.nearest[id]
+}
+
+// avoid returns the next landmark according to the avoid heuristic.
+func (s *landmarkSelection[N, E, W]) avoid() g.NodeId {
+	// random root node
+	candidates := make([]g.NodeId, 0)
+	for id, eligible := range s.eligible {
+		if eligible {
+			candidates = append(candidates, id)
+		}
+	}
+	if len(candidates) == 0 {
+		return -1
+	}
+	root := candidates[s.rng.Intn(len(candidates))]
+
+	// shortest path tree of the root restricted to candidate nodes
+	tree := DijkstraOneToAll[N, E, W](s.graph, root)
+	children := make([][]g.NodeId, s.graph.NodeCount())
+	for id, predecessor := range tree.Predecessors {
+		if predecessor >= 0 && s.eligible[id] {
+			children[predecessor] = append(children[predecessor], id)
+		}
+	}
+	order := make([]g.NodeId, 0, len(candidates)) // preorder
+	for stack := []g.NodeId{root}; len(stack) > 0; {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		order = append(order, id)
+		stack = append(stack, children[id]...)
+	}
+
+	// size of a subtree: sum of the gaps between the distances and the lower bounds, and zero if it contains a landmark
+	sizes := make([]W, s.graph.NodeCount())
+	covered := make([]bool, s.graph.NodeCount())
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
+		sizes[id] = tree.Lengths[id] - s.lowerBound(root, id)
+		covered[id] = s.selected[id]
+		for _, child := range children[id] {
+			sizes[id] += sizes[child]
+			covered[id] = covered[id] || covered[child]
+		}
+		if covered[id] {
+			sizes[id] = 0
+		}
+	}
+
+	best := root
+	for _, id := range order {
+		if sizes[id] > sizes[best] {
+			best = id
+		}
+	}
+	if sizes[best] <= 0 {
+		// the graph is perfectly covered from the root
+		return s.farthest()
+	}
+	// descend to a leaf, following the largest subtree
+	for len(children[best]) > 0 {
+		next := children[best][0]
+		for _, child := range children[best] {
+			if sizes[child] > sizes[next] {
+				next = child
+			}
+		}
+		best = next
+	}
+	return best
+}
+
+// lowerBound returns the ALT lower bound of the distance from source to target based on the selected landmarks.
+func (s *landmarkSelection[N, E, W]) lowerBound(source, target g.NodeId) W {
+	lowerBound := W(0)
+	for _, landmark := range s.landmarks {
+		lowerBound = max(lowerBound, landmarkBound(landmark, source, target))
+	}
+	return lowerBound
+}
+
+// roundTrip returns the sum of the distances from and to a landmark.
+func roundTrip[W g.Weight](distances LandmarkDistances[W], id g.NodeId) W {
+	return distances.From[id] + distances.To[id]
+}
+
+// unitVector converts a location into a point on the unit sphere.
+func unitVector(p g.GeoPoint) (float64, float64, float64) {
+	lat, lon := p.Lat*math.Pi/180, p.Lon*math.Pi/180
+	return math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)
+}
+
+// bearing returns the initial bearing from one location to another in radians within [0, 2*Pi).
+func bearing(from, to g.GeoPoint) float64 {
+	lat1, lat2 := from.Lat*math.Pi/180, to.Lat*math.Pi/180
+	dLon := (to.Lon - from.Lon) * math.Pi / 180
+	theta := math.Atan2(math.Sin(dLon)*math.Cos(lat2), math.Cos(lat1)*math.Sin(lat2)-math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon))
+	if theta < 0 {
+		theta += 2 * math.Pi
+	}
+	return theta
+}
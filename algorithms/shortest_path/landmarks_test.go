@@ -0,0 +1,53 @@
+package shortest_path_test
+
+import (
+	"reflect"
+	"testing"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+type landmarkSelector func(graph, transpose g.Graph[g.GeoPoint, g.WeightedHalfEdge[int]], n int, seed int64) []g.NodeId
+
+var landmarkSelectors = map[string]landmarkSelector{
+	"farthest": sp.FarthestLandmarks[g.GeoPoint, g.WeightedHalfEdge[int], int],
+	"avoid":    sp.AvoidLandmarks[g.GeoPoint, g.WeightedHalfEdge[int], int],
+	"planar":   sp.PlanarLandmarks[g.GeoPoint, g.WeightedHalfEdge[int], int],
+	"maxcover": sp.MaxCoverLandmarks[g.GeoPoint, g.WeightedHalfEdge[int], int],
+}
+
+func TestLandmarkSelection(t *testing.T) {
+	aag := loadAdjacencyArrayFromGob[g.GeoPoint, g.WeightedHalfEdge[int]](defaultGraphFile) // aag is a undirected graph
+
+	for name, selector := range landmarkSelectors {
+		landmarks := selector(aag, aag, 8, 1)
+		if len(landmarks) != 8 {
+			t.Errorf("[%s] Expected 8 landmarks, got %d", name, len(landmarks))
+		}
+		unique := make(map[g.NodeId]bool)
+		for _, landmark := range landmarks {
+			if unique[landmark] {
+				t.Errorf("[%s] Duplicate landmark %d", name, landmark)
+			}
+			unique[landmark] = true
+		}
+		if again := selector(aag, aag, 8, 1); !reflect.DeepEqual(landmarks, again) {
+			t.Errorf("[%s] Expected deterministic landmarks %v, got %v", name, landmarks, again)
+		}
+	}
+}
+
+// Differential testing: Compare the output of ALT with selected landmarks with Dijkstra's algorithm.
+func TestAltLandmarkSelection(t *testing.T) {
+	aag := loadAdjacencyArrayFromGob[g.GeoPoint, g.WeightedHalfEdge[int]](defaultGraphFile) // aag is a undirected graph
+	baselineRouter := sp.DijkstraRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag}
+
+	for name, selector := range landmarkSelectors {
+		t.Logf("Landmark selection: %s", name)
+		landmarks := selector(aag, aag, 8, 1)
+		altHeuristic := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, landmarks)
+		testedRouter := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: altHeuristic}
+		DifferentialTesting(t, testedRouter, baselineRouter, aag.NodeCount())
+	}
+}
@@ -0,0 +1,23 @@
+package shortest_path_test
+
+import (
+	"testing"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	"github.com/dmholtz/graffiti/examples/partitioning"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Differential testing: Compare the output of ArcFlagDijkstra with 512 partitions, whose flags are stored in a flag matrix, with textbook Dijkstra
+func TestMatrixArcFlagDijkstra(t *testing.T) {
+	aag := loadAdjacencyArrayFromGob[g.GeoPoint, g.WeightedHalfEdge[int]](defaultGraphFile) // aag is a undirected graph
+
+	maag := g.NewMatrixFlaggedGraph[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, g.NewFlagMatrix(aag.EdgeCount(), 512))
+	pg := &g.PartitionedGraph[g.GeoPoint, g.MatrixFlaggedHalfEdge[int]]{Graph: maag, Assignment: partitioning.InertialFlowAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](aag, 9)}
+	faag := sp.ComputeArcFlags[g.PartitionedNode[g.GeoPoint], g.MatrixFlaggedHalfEdge[int], int](pg, pg, 512)
+
+	testedRouter := sp.ArcFlagRouter[g.PartitionedNode[g.GeoPoint], g.MatrixFlaggedHalfEdge[int], int]{Graph: faag}
+	baselineRouter := sp.DijkstraRouter[g.PartitionedNode[g.GeoPoint], g.MatrixFlaggedHalfEdge[int], int]{Graph: faag}
+
+	DifferentialTesting(t, testedRouter, baselineRouter, faag.NodeCount())
+}
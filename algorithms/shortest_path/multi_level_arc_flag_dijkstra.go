@@ -0,0 +1,99 @@
+package shortest_path
+
+import (
+	"container/heap"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// MultiLevelArcFlagRouter implements the Router interface and improves Dijkstra's algorithm by incorporating multi-level arc flags.
+type MultiLevelArcFlagRouter[N g.MultiLevelPartitioner, E g.IMultiLevelFlaggedHalfEdge[W], W g.Weight] struct {
+	Graph g.Graph[N, E]
+}
+
+// String implements fmt.Stringer
+func (r MultiLevelArcFlagRouter[N, E, W]) String() string {
+	return "Multi-level ArcFlag Dijkstra"
+}
+
+// Implementation of Dijkstra's Algorithm with multi-level arc flags
+//
+// An edge is relaxed iff its flag is set on the coarsest level, on which the region of its tail or its head differs from the region of the target.
+// If tail and head belong to the same finest region as the target, the edge is always relaxed.
+func (r MultiLevelArcFlagRouter[N, E, W]) Route(source, target g.NodeId, recordSearchSpace bool) ShortestPathResult[W] {
+	var searchSpace []g.NodeId = nil
+	if recordSearchSpace {
+		searchSpace = make([]g.NodeId, 0)
+	}
+
+	dijkstraItems := make([]*DijkstraPqItem[W], r.Graph.NodeCount(), r.Graph.NodeCount())
+	dijkstraItems[source] = &DijkstraPqItem[W]{Id: source, Priority: 0, Predecessor: -1}
+
+	pq := make(DijkstraPriorityQueue[W], 0)
+	heap.Init(&pq)
+	heap.Push(&pq, dijkstraItems[source])
+
+	targetNode := r.Graph.GetNode(target)
+	levelCount := targetNode.LevelCount()
+	// commonLevels returns the number of levels, on which the node belongs to the same region as the target
+	commonLevels := func(id g.NodeId) int {
+		node := r.Graph.GetNode(id)
+		level := 0
+		for level < levelCount && node.LevelPart(level) == targetNode.LevelPart(level) {
+			level++
+		}
+		return level
+	}
+
+	edges := g.NewHalfEdgeReader(r.Graph)
+	pqPops := 0
+	for len(pq) > 0 {
+		currentPqItem := heap.Pop(&pq).(*DijkstraPqItem[W])
+		currentNodeId := currentPqItem.Id
+		pqPops++
+
+		if recordSearchSpace {
+			searchSpace = append(searchSpace, currentNodeId)
+		}
+
+		currentCommonLevels := commonLevels(currentNodeId)
+		for _, edge := range edges.From(currentNodeId) {
+			successor := edge.To()
+
+			// restrict the search space to the edges that are flagged on the coarsest level, on which tail or head differ from the target region
+			level := currentCommonLevels
+			if successorCommonLevels := commonLevels(successor); successorCommonLevels < level {
+				level = successorCommonLevels
+			}
+			if level < levelCount && !edge.IsLevelFlagged(level, targetNode.LevelPart(level)) {
+				continue
+			}
+
+			if dijkstraItems[successor] == nil {
+				newPriority := dijkstraItems[currentNodeId].Priority + edge.Weight()
+				pqItem := DijkstraPqItem[W]{Id: successor, Priority: newPriority, Predecessor: currentNodeId}
+				dijkstraItems[successor] = &pqItem
+				heap.Push(&pq, &pqItem)
+			} else {
+				if updatedDistance := dijkstraItems[currentNodeId].Priority + edge.Weight(); updatedDistance < dijkstraItems[successor].Priority {
+					dijkstraItems[successor].Priority = updatedDistance
+					dijkstraItems[successor].Predecessor = currentNodeId
+					heap.Fix(&pq, dijkstraItems[successor].index)
+				}
+			}
+		}
+
+		if currentNodeId == target {
+			break
+		}
+	}
+
+	res := ShortestPathResult[W]{Length: W(-1), Path: make([]g.NodeId, 0), PqPops: pqPops, SearchSpace: searchSpace}
+	if dijkstraItems[target] != nil {
+		res.Length = dijkstraItems[target].Priority
+		for nodeId := target; nodeId != -1; nodeId = dijkstraItems[nodeId].Predecessor {
+			res.Path = append([]int{nodeId}, res.Path...)
+		}
+	}
+	return res
+}
@@ -0,0 +1,34 @@
+package shortest_path_test
+
+import (
+	"testing"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	"github.com/dmholtz/graffiti/examples/partitioning"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+type threeLevelFlaggedHalfEdge = g.MultiLevelFlaggedHalfEdge[int, [3]uint64]
+
+// Differential testing: Compare the output of MultiLevelArcFlagDijkstra with three levels of 4 regions each with textbook Dijkstra
+func TestMultiLevelArcFlagDijkstra(t *testing.T) {
+	aag := loadAdjacencyArrayFromGob[g.GeoPoint, g.WeightedHalfEdge[int]](defaultGraphFile) // aag is a undirected graph
+
+	alg := &g.AdjacencyListGraph[g.MultiLevelPartGeoPoint, threeLevelFlaggedHalfEdge]{}
+	for i := 0; i < aag.NodeCount(); i++ {
+		alg.AppendNode(g.MultiLevelPartGeoPoint{GeoPoint: aag.GetNode(i)})
+	}
+	for i := 0; i < aag.NodeCount(); i++ {
+		for _, edge := range aag.GetHalfEdgesFrom(i) {
+			alg.InsertHalfEdge(i, threeLevelFlaggedHalfEdge{To_: edge.To(), Weight_: edge.Weight()})
+		}
+	}
+	faag := g.NewAdjacencyArrayFromGraph[g.MultiLevelPartGeoPoint, threeLevelFlaggedHalfEdge](alg)
+	faag = partitioning.MultiLevelInertialFlowPartitioning(faag, 2, 2, 2)
+	faag = sp.ComputeMultiLevelArcFlags[g.MultiLevelPartGeoPoint, threeLevelFlaggedHalfEdge, int](faag, faag)
+
+	testedRouter := sp.MultiLevelArcFlagRouter[g.MultiLevelPartGeoPoint, threeLevelFlaggedHalfEdge, int]{Graph: faag}
+	baselineRouter := sp.DijkstraRouter[g.MultiLevelPartGeoPoint, threeLevelFlaggedHalfEdge, int]{Graph: faag}
+
+	DifferentialTesting(t, testedRouter, baselineRouter, faag.NodeCount())
+}
@@ -0,0 +1,139 @@
+package shortest_path
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// ComputeMultiLevelArcFlags computes multi-level arc flags on one worker per CPU (see ComputeMultiLevelArcFlagsParallel).
+func ComputeMultiLevelArcFlags[N g.MultiLevelPartitioner, E g.IMultiLevelFlaggedHalfEdge[W], W g.Weight](forwardGraph, transposedGraph g.Graph[N, E]) *g.AdjacencyArrayGraph[N, E] {
+	return ComputeMultiLevelArcFlagsParallel[N, E, W](forwardGraph, transposedGraph, runtime.NumCPU())
+}
+
+// Parallel implementation of multi-level arcflag preprocessing, which generalizes the two-level preprocessing to an arbitrary number of levels.
+//
+// A node is a boundary node of level l iff it is the head of an edge, whose tail belongs to a different region of level l. Every boundary node
+// of level l is also a boundary node of all finer levels. For the boundary nodes of each region of level l, a centralized backward search is
+// pruned to the region of level l-1 that contains the boundary nodes (level 0: the entire graph) and sets the level l flag of the boundary
+// nodes' partition for all edges of the shortest path DAGs within this region. Edges within the same region of level l are flagged for this region.
+func ComputeMultiLevelArcFlagsParallel[N g.MultiLevelPartitioner, E g.IMultiLevelFlaggedHalfEdge[W], W g.Weight](forwardGraph, transposedGraph g.Graph[N, E], workers int) *g.AdjacencyArrayGraph[N, E] {
+
+	// create a copy of the (forward) graph
+	faag := g.NewAdjacencyArrayFromGraph(forwardGraph)
+
+	// remove any existing arc flags
+	for i, halfEdge := range faag.Edges {
+		faag.Edges[i] = halfEdge.ResetFlags().(E)
+	}
+
+	// determine the number of levels and the flag range based on the first edge in the graph
+	if faag.EdgeCount() < 1 {
+		panic(fmt.Sprintf("Cannot compute multi-level arc flags - the graph does not contain any edges."))
+	}
+	levelCount := faag.Edges[0].LevelCount()
+	flagRange := faag.Edges[0].LevelFlagRange()
+
+	// check if every node has a partition within the flag range on every level
+	for id, node := range faag.Nodes {
+		if node.LevelCount() != levelCount {
+			panic(fmt.Sprintf("Node %d is partitioned on %d levels, but the arc flags have %d levels", id, node.LevelCount(), levelCount))
+		}
+		for level := 0; level < levelCount; level++ {
+			if node.LevelPart(level) >= flagRange {
+				panic(fmt.Sprintf("Partition of level %d exceeds flag range: %d >= %d", level, node.LevelPart(level), flagRange))
+			}
+		}
+	}
+
+	regions, regionNodes := multiLevelRegions[N, E](faag, levelCount, int(flagRange))
+
+	// determine the boundary nodes of each region of each level: the coarsest level on which tail and head differ and all finer levels
+	boundaryNodes := make([]map[int]map[g.NodeId]struct{}, levelCount)
+	for level := range boundaryNodes {
+		boundaryNodes[level] = make(map[int]map[g.NodeId]struct{})
+	}
+	for tailNodeId := range faag.Nodes {
+		for _, edge := range faag.GetHalfEdgesFrom(tailNodeId) {
+			coarsest := 0
+			for coarsest < levelCount && regions[coarsest][tailNodeId] == regions[coarsest][edge.To()] {
+				coarsest++
+			}
+			for level := coarsest; level < levelCount; level++ {
+				region := regions[level][edge.To()]
+				if boundaryNodes[level][region] == nil {
+					boundaryNodes[level][region] = make(map[g.NodeId]struct{})
+				}
+				boundaryNodes[level][region][edge.To()] = struct{}{}
+			}
+		}
+	}
+
+	// one task per region of each level in a deterministic order, which is pruned to the region of the parent level
+	tasks := make([]flagTask, 0)
+	taskLevels := make([]int, 0)
+	taskPartitions := make([]g.PartitionId, 0)
+	for level, regionSets := range boundaryNodes {
+		boundaryNodeCount := 0
+		levelRegions := make([]int, 0, len(regionSets))
+		for region, set := range regionSets {
+			boundaryNodeCount += len(set)
+			levelRegions = append(levelRegions, region)
+		}
+		sort.Ints(levelRegions)
+		fmt.Printf("Level: %d, boundary nodes=%d\n", level, boundaryNodeCount)
+
+		for _, region := range levelRegions {
+			task := flagTask{}
+			for boundaryNodeId := range regionSets[region] {
+				task.boundaryNodes = append(task.boundaryNodes, boundaryNodeId)
+			}
+			sort.Ints(task.boundaryNodes)
+			if level > 0 {
+				task.region = regionNodes[level-1][regions[level-1][task.boundaryNodes[0]]]
+			}
+			tasks = append(tasks, task)
+			taskLevels = append(taskLevels, level)
+			taskPartitions = append(taskPartitions, faag.GetNode(task.boundaryNodes[0]).LevelPart(level))
+		}
+	}
+
+	// flag edges within the same region
+	for i := 0; i < faag.NodeCount(); i++ {
+		for j := faag.Offsets[i]; j < faag.Offsets[i+1]; j++ {
+			for level := 0; level < levelCount && regions[level][i] == regions[level][faag.Edges[j].To()]; level++ {
+				faag.Edges[j] = faag.Edges[j].AddLevelFlag(level, faag.GetNode(i).LevelPart(level)).(E)
+			}
+		}
+	}
+
+	flagPartitions[N, E, W](faag, transposedGraph, tasks, workers, func(task int, bitset []uint64) {
+		forEachFlaggedEdge(bitset, func(i int) {
+			faag.Edges[i] = faag.Edges[i].AddLevelFlag(taskLevels[task], taskPartitions[task]).(E)
+		})
+	})
+
+	return faag
+}
+
+// multiLevelRegions identifies the region of each node on each level, i.e. regions[l][id] is equal for two nodes iff their partitions
+// of the levels 0 to l are equal. Additionally, the nodes of each region are returned for each level.
+func multiLevelRegions[N g.MultiLevelPartitioner, E g.IHalfEdge](graph g.Graph[N, E], levelCount int, flagRange int) ([][]int, []map[int][]g.NodeId) {
+	regions := make([][]int, levelCount)
+	regionNodes := make([]map[int][]g.NodeId, levelCount)
+	for level := range regions {
+		regions[level] = make([]int, graph.NodeCount())
+		regionNodes[level] = make(map[int][]g.NodeId)
+		for id := range regions[level] {
+			region := int(graph.GetNode(id).LevelPart(level))
+			if level > 0 {
+				region += regions[level-1][id] * flagRange
+			}
+			regions[level][id] = region
+			regionNodes[level][region] = append(regionNodes[level][region], id)
+		}
+	}
+	return regions, regionNodes
+}
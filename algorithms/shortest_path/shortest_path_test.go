@@ -21,6 +21,9 @@ const defaultGraphFile = "testdata/geo_graph_7k.gob"
 const arcflag64 = "testdata/geograph_arcflag_64_7k.gob"
 const arcflag32_32 = "testdata/geograph_arcflag_32_32_7k.gob"
 
+// Path to a directed DIMACS graph file, whose arcs have no reverse arcs of the same weight
+const asymmetricGraphFile = "testdata/asymmetric_300.gr"
+
 // Deserializer
 func loadAdjacencyArrayFromGob[N any, E g.IHalfEdge](filename string) *g.AdjacencyArrayGraph[N, E] {
 	var faag g.AdjacencyArrayGraph[N, E]
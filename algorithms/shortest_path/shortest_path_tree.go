@@ -20,6 +20,7 @@ func ShortestPathTree[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph
 
 	successors := make([]*ShortestPathTreeNode, graph.NodeCount(), graph.NodeCount())
 
+	edges := g.NewHalfEdgeReader(graph)
 	for len(pq) > 0 {
 		currentPqItem := heap.Pop(&pq).(*ShortestPathTreePqItem[W])
 		currentNodeId := currentPqItem.Id
@@ -36,7 +37,7 @@ func ShortestPathTree[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph
 			}
 		}
 
-		for _, edge := range graph.GetHalfEdgesFrom(currentNodeId) {
+		for _, edge := range edges.From(currentNodeId) {
 			successor := edge.To()
 
 			if dijkstraItems[successor] == nil {
@@ -33,6 +33,7 @@ func (r TwoLevelArcFlagRouter[N, E, W]) Route(source, target g.NodeId, recordSea
 	l1TargetPartition := r.Graph.GetNode(target).L1Part()
 	l2TargetPartition := r.Graph.GetNode(target).L2Part()
 
+	edges := g.NewHalfEdgeReader(r.Graph)
 	pqPops := 0
 	for len(pq) > 0 {
 		currentPqItem := heap.Pop(&pq).(*DijkstraPqItem[W])
@@ -44,7 +45,7 @@ func (r TwoLevelArcFlagRouter[N, E, W]) Route(source, target g.NodeId, recordSea
 		}
 
 		currentL1Part := r.Graph.GetNode(currentNodeId).L1Part()
-		for _, edge := range r.Graph.GetHalfEdgesFrom(currentNodeId) {
+		for _, edge := range edges.From(currentNodeId) {
 			// restrict the search space to the edges that are flagged with the l1-target-partition
 			if !edge.IsL1Flagged(l1TargetPartition) {
 				continue
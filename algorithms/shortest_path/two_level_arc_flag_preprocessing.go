@@ -1,35 +1,24 @@
 package shortest_path
 
 import (
-	"container/heap"
 	"fmt"
-	"sync"
+	"runtime"
+	"sort"
 
 	g "github.com/dmholtz/graffiti/graph"
 )
 
-// Type of boundary node
-const (
-	// A l1-boundary node is a node at the boundary of a level 1 region.
-	l1_BOUNDARY_NODE = iota
-	// A l2-boundary node is a node at the boundary of a level 2 region. Every l1-boundary node is also a l2-boundary node.
-	l2_BOUNDARY_NODE = iota
-)
-
-const (
-	l1_JOB = iota
-	l2_JOB = iota
-)
-
-type addTwoLevelFlagJob struct {
-	from      g.NodeId
-	to        g.NodeId
-	partition g.PartitionId
-	kind      uint8
+// ComputeTwoLevelArcFlags computes two-level arc flags on one worker per CPU (see ComputeTwoLevelArcFlagsParallel).
+func ComputeTwoLevelArcFlags[N g.TwoLevelPartitioner, E g.ITwoLevelFlaggedHalfEdge[W], W g.Weight](forwardGraph, transposedGraph g.Graph[N, E]) *g.AdjacencyArrayGraph[N, E] {
+	return ComputeTwoLevelArcFlagsParallel[N, E, W](forwardGraph, transposedGraph, runtime.NumCPU())
 }
 
-// Parallel implementation of two-level arcflag preprocessing
-func ComputeTwoLevelArcFlags[N g.TwoLevelPartitioner, E g.ITwoLevelFlaggedHalfEdge[W], W g.Weight](forwardGraph, transposedGraph g.Graph[N, E]) *g.AdjacencyArrayGraph[N, E] {
+// Parallel implementation of two-level arcflag preprocessing with centralized shortest path DAGs (see ComputeArcFlagsParallel).
+//
+// The l1 flags are computed from the l1-boundary nodes of each l1 partition on the entire graph. The l2 flags are computed from the
+// l2-boundary nodes of each l2 partition within the enclosing l1 partition: the backward search is pruned to the l1 partition and only
+// edges within the l1 partition are flagged. Every l1-boundary node is also a l2-boundary node.
+func ComputeTwoLevelArcFlagsParallel[N g.TwoLevelPartitioner, E g.ITwoLevelFlaggedHalfEdge[W], W g.Weight](forwardGraph, transposedGraph g.Graph[N, E], workers int) *g.AdjacencyArrayGraph[N, E] {
 
 	// create a copy of the (forward) graph
 	faag := g.NewAdjacencyArrayFromGraph(forwardGraph)
@@ -56,266 +45,81 @@ func ComputeTwoLevelArcFlags[N g.TwoLevelPartitioner, E g.ITwoLevelFlaggedHalfEd
 		}
 	}
 
-	// Initialize l1/l2 boundary node maps
-	l1BoundaryNodes := make(map[g.PartitionId]map[g.NodeId]struct{})
-	l2BoundaryNodes := make(map[g.PartitionId]map[g.PartitionId]map[g.NodeId]struct{})
-	for l1Part := g.PartitionId(0); l1Part < l1FlagRange; l1Part++ {
+	// determine l1/l2 boundary nodes
+	l1BoundaryNodes := make([]map[g.NodeId]struct{}, l1FlagRange)
+	l2BoundaryNodes := make([][]map[g.NodeId]struct{}, l1FlagRange)
+	for l1Part := range l1BoundaryNodes {
 		l1BoundaryNodes[l1Part] = make(map[g.NodeId]struct{})
-		l2BoundaryNodes[l1Part] = make(map[g.PartitionId]map[g.NodeId]struct{})
-		for l2Part := g.PartitionId(0); l2Part < l2FlagRange; l2Part++ {
+		l2BoundaryNodes[l1Part] = make([]map[g.NodeId]struct{}, l2FlagRange)
+		for l2Part := range l2BoundaryNodes[l1Part] {
 			l2BoundaryNodes[l1Part][l2Part] = make(map[g.NodeId]struct{})
 		}
 	}
-
-	// determine l1 boundary nodes
+	l1PartNodes := make([][]g.NodeId, l1FlagRange)
 	for tailNodeId, tailNode := range faag.Nodes {
 		tailL1Part := tailNode.L1Part()
 		tailL2Part := tailNode.L2Part()
+		l1PartNodes[tailL1Part] = append(l1PartNodes[tailL1Part], tailNodeId)
 		for _, edge := range faag.GetHalfEdgesFrom(tailNodeId) {
 			headL1Part := faag.GetNode(edge.To()).L1Part()
 			headL2Part := faag.GetNode(edge.To()).L2Part()
 			if tailL1Part != headL1Part {
 				l1BoundaryNodes[headL1Part][edge.To()] = struct{}{}
 				l2BoundaryNodes[headL1Part][headL2Part][edge.To()] = struct{}{}
-			} else {
-				// same l1 partition
-				if tailL2Part != headL2Part {
-					l2BoundaryNodes[headL1Part][headL2Part][edge.To()] = struct{}{}
-				}
+			} else if tailL2Part != headL2Part {
+				l2BoundaryNodes[headL1Part][headL2Part][edge.To()] = struct{}{}
 			}
 		}
 	}
 
-	// parallel implementation following the producer-consumer pattern: cf. arc_flag_preprocessing.go
-	jobs := make(chan addTwoLevelFlagJob, 1<<10)
-	done := make(chan bool)
-	guard := make(chan struct{}, MAX_GOROUTINES)
-	wg := sync.WaitGroup{}
-
-	// start consumer
-	go addTwoLevelFlag[N, E, W](jobs, faag, done)
-
-	// start producers
-	for l1Part, nodeSet := range l1BoundaryNodes {
-		setSize := len(nodeSet)
-		wg.Add(setSize)
-		fmt.Printf("Partition: %d, size=%d\n", l1Part, setSize)
-		for boundaryNodeId := range nodeSet {
-			guard <- struct{}{}
-			go l1BoundaryBackwardSearch[N, E, W](jobs, forwardGraph, transposedGraph, boundaryNodeId, &wg, guard)
-		}
-	}
-
-	//revise edges within the same l1 partition
-	for i := 0; i < faag.NodeCount(); i++ {
-		tailL1Part := faag.GetNode(i).L1Part()
-		for _, halfEdge := range faag.GetHalfEdgesFrom(i) {
-			if tailL1Part == faag.GetNode(halfEdge.To()).L1Part() {
-				jobs <- addTwoLevelFlagJob{from: i, to: halfEdge.To(), partition: tailL1Part, kind: l1_JOB}
-			}
+	// one task per l1 partition on the entire graph and one task per l2 partition within each l1 partition
+	sorted := func(set map[g.NodeId]struct{}) []g.NodeId {
+		nodes := make([]g.NodeId, 0, len(set))
+		for id := range set {
+			nodes = append(nodes, id)
 		}
+		sort.Ints(nodes)
+		return nodes
 	}
-
-	wg.Wait()
-	fmt.Println("Done with L1 boundary nodes.")
-
-	// precompute the l1 partition size for each l1 partition
-	l1PartSizes := make(map[g.PartitionId]int)
-	for _, node := range faag.Nodes {
-		l1Part := node.L1Part()
-		if _, ok := l1PartSizes[l1Part]; !ok {
-			l1PartSizes[l1Part] = 1
-		} else {
-			l1PartSizes[l1Part] += 1
-		}
-	}
-
-	// l2 boundary nodes
-	for l1Part, l2Map := range l2BoundaryNodes {
-		// determine the l1 partition size, which serves as a pruning / early stopping criterion for the backward search
+	l1Tasks := make([]flagTask, l1FlagRange)
+	l2Tasks := make([]flagTask, int(l1FlagRange)*int(l2FlagRange))
+	for l1Part, nodeSet := range l1BoundaryNodes {
 		l2BoundaryNodeSize := 0
-		for _, nodeSet := range l2Map {
-			l2BoundaryNodeSize += len(nodeSet)
-		}
-		fmt.Printf("Partition: %d, size=%d\n", l1Part, l2BoundaryNodeSize)
-
-		for _, nodeSet := range l2Map {
-			for boundaryNodeId := range nodeSet {
-				guard <- struct{}{}
-				wg.Add(1)
-				go l2BoundaryBackwardSearch[N, E, W](jobs, forwardGraph, transposedGraph, boundaryNodeId, l1PartSizes[l1Part], &wg, guard)
-			}
-
+		for l2Part, l2NodeSet := range l2BoundaryNodes[l1Part] {
+			l2BoundaryNodeSize += len(l2NodeSet)
+			l2Tasks[l1Part*int(l2FlagRange)+l2Part] = flagTask{boundaryNodes: sorted(l2NodeSet), region: l1PartNodes[l1Part]}
 		}
+		fmt.Printf("Partition: %d, size=%d, l2 size=%d\n", l1Part, len(nodeSet), l2BoundaryNodeSize)
+		l1Tasks[l1Part] = flagTask{boundaryNodes: sorted(nodeSet)}
 	}
 
-	// revise edges within the same l2 partition
+	// flag edges within the same l1/l2 partition
 	for i := 0; i < faag.NodeCount(); i++ {
 		tailL1Part := faag.GetNode(i).L1Part()
 		tailL2Part := faag.GetNode(i).L2Part()
-		for _, halfEdge := range faag.GetHalfEdgesFrom(i) {
-			if tailL1Part == (faag.GetNode(halfEdge.To()).L1Part()) {
-				if tailL2Part == (faag.GetNode(halfEdge.To()).L2Part()) {
-					jobs <- addTwoLevelFlagJob{from: i, to: halfEdge.To(), partition: tailL2Part, kind: l2_JOB}
+		for j := faag.Offsets[i]; j < faag.Offsets[i+1]; j++ {
+			head := faag.GetNode(faag.Edges[j].To())
+			if tailL1Part == head.L1Part() {
+				faag.Edges[j] = faag.Edges[j].AddL1Flag(tailL1Part).(E)
+				if tailL2Part == head.L2Part() {
+					faag.Edges[j] = faag.Edges[j].AddL2Flag(tailL2Part).(E)
 				}
 			}
 		}
 	}
 
-	wg.Wait()
-	close(jobs)
-	<-done
+	flagPartitions[N, E, W](faag, transposedGraph, l1Tasks, workers, func(l1Part int, bitset []uint64) {
+		forEachFlaggedEdge(bitset, func(i int) {
+			faag.Edges[i] = faag.Edges[i].AddL1Flag(g.PartitionId(l1Part)).(E)
+		})
+	})
+	fmt.Println("Done with L1 boundary nodes.")
+	flagPartitions[N, E, W](faag, transposedGraph, l2Tasks, workers, func(task int, bitset []uint64) {
+		l2Part := g.PartitionId(task % int(l2FlagRange))
+		forEachFlaggedEdge(bitset, func(i int) {
+			faag.Edges[i] = faag.Edges[i].AddL2Flag(l2Part).(E)
+		})
+	})
 
 	return faag
 }
-
-// (single) consumer
-func addTwoLevelFlag[N g.TwoLevelPartitioner, E g.ITwoLevelFlaggedHalfEdge[W], W g.Weight](jobs <-chan addTwoLevelFlagJob, faag *g.AdjacencyArrayGraph[N, E], done chan<- bool) {
-	for job := range jobs {
-		for i := faag.Offsets[job.from]; i < faag.Offsets[job.from+1]; i++ {
-			edge := faag.Edges[i]
-			if edge.To() == job.to {
-				if job.kind == l1_JOB {
-					faag.Edges[i] = edge.AddL1Flag(job.partition).(E)
-					break
-				} else if job.kind == l2_JOB {
-					faag.Edges[i] = edge.AddL2Flag(job.partition).(E)
-					break
-				} else {
-					panic("Invalid job kind")
-				}
-			}
-		}
-	}
-	done <- true
-}
-
-// producer function of level 1 arcflags
-func l1BoundaryBackwardSearch[N g.TwoLevelPartitioner, E g.IWeightedHalfEdge[W], W g.Weight](jobs chan<- addTwoLevelFlagJob, forwardGraph, transposedGraph g.Graph[N, E], boundaryNodeId g.NodeId, wg *sync.WaitGroup, guard <-chan struct{}) {
-	// calculate in reverse graph
-	tree := ShortestPathTree[N, E, W](transposedGraph, boundaryNodeId)
-	l1Part := forwardGraph.GetNode(boundaryNodeId).L1Part()
-
-	stack := make([]*ShortestPathTreeNode, 0)
-	stack = append(stack, &tree)
-
-	for len(stack) > 0 {
-		// pop
-		treeNode := stack[len(stack)-1]
-		stack = stack[0 : len(stack)-1]
-
-		for _, child := range treeNode.Children {
-			if child.Visited {
-				continue
-			}
-			child.Visited = true
-
-			tailRev := treeNode.Id
-			headRev := child.Id
-			jobs <- addTwoLevelFlagJob{from: headRev, to: tailRev, partition: l1Part, kind: l1_JOB}
-
-			if forwardGraph.GetNode(child.Id).L1Part() != l1Part {
-				stack = append(stack, child)
-			}
-		}
-	}
-
-	<-guard
-	wg.Done()
-}
-
-// producer function of level 2 arcflags
-func l2BoundaryBackwardSearch[N g.TwoLevelPartitioner, E g.IWeightedHalfEdge[W], W g.Weight](jobs chan<- addTwoLevelFlagJob, forwardGraph, transposedGraph g.Graph[N, E], boundaryNodeId g.NodeId, l1PartSize int, wg *sync.WaitGroup, guard <-chan struct{}) {
-	l1Part := forwardGraph.GetNode(boundaryNodeId).L1Part()
-	l2Part := forwardGraph.GetNode(boundaryNodeId).L2Part()
-
-	// calculate in reverse graph
-	tree := prundedShortestPathTree[N, E, W](transposedGraph, boundaryNodeId, l1Part, l1PartSize)
-
-	stack := make([]*ShortestPathTreeNode, 0)
-	stack = append(stack, &tree)
-
-	for len(stack) > 0 {
-		// pop
-		treeNode := stack[len(stack)-1]
-		stack = stack[0 : len(stack)-1]
-
-		for _, child := range treeNode.Children {
-			if !child.Visited {
-				stack = append(stack, child)
-			}
-			child.Visited = true
-
-			tailRev := treeNode.Id
-			headRev := child.Id
-			if forwardGraph.GetNode(headRev).L1Part() == l1Part && forwardGraph.GetNode(tailRev).L1Part() == l1Part {
-				jobs <- addTwoLevelFlagJob{from: headRev, to: tailRev, partition: l2Part, kind: l2_JOB}
-			}
-		}
-	}
-
-	<-guard
-	wg.Done()
-}
-
-// Pruned variant of ShortestPathTree method: Search stops once every node of the specified l1-partition has been settled.
-func prundedShortestPathTree[N g.TwoLevelPartitioner, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], source g.NodeId, l1Part g.PartitionId, l1PartSize int) ShortestPathTreeNode {
-	dijkstraItems := make([]*ShortestPathTreePqItem[W], graph.NodeCount(), graph.NodeCount())
-	dijkstraItems[source] = &ShortestPathTreePqItem[W]{Id: source, Priority: 0, Predecessors: make([]int, 0)}
-
-	pq := make(ShortestPathTreePriorityQueue[W], 0)
-	heap.Init(&pq)
-	heap.Push(&pq, dijkstraItems[source])
-
-	successors := make([]*ShortestPathTreeNode, graph.NodeCount(), graph.NodeCount())
-
-	l1SettledCount := 0
-	for len(pq) > 0 {
-		currentPqItem := heap.Pop(&pq).(*ShortestPathTreePqItem[W])
-		currentNodeId := currentPqItem.Id
-
-		if graph.GetNode(currentNodeId).L1Part() == l1Part {
-			l1SettledCount += 1
-		}
-
-		if currentNodeId != source {
-			if successors[currentNodeId] == nil {
-				successors[currentNodeId] = &ShortestPathTreeNode{Id: currentNodeId, Children: make([]*ShortestPathTreeNode, 0)}
-			}
-			for _, pred := range currentPqItem.Predecessors {
-				if successors[pred] == nil {
-					successors[pred] = &ShortestPathTreeNode{Id: pred, Children: make([]*ShortestPathTreeNode, 0)}
-				}
-				successors[pred].Children = append(successors[pred].Children, successors[currentNodeId])
-			}
-		}
-
-		for _, edge := range graph.GetHalfEdgesFrom(currentNodeId) {
-			successor := edge.To()
-
-			if dijkstraItems[successor] == nil {
-				newPriority := dijkstraItems[currentNodeId].Priority + edge.Weight()
-				pqItem := ShortestPathTreePqItem[W]{Id: successor, Priority: newPriority, Predecessors: []int{currentNodeId}}
-				dijkstraItems[successor] = &pqItem
-				heap.Push(&pq, &pqItem)
-			} else {
-				if updatedDistance := dijkstraItems[currentNodeId].Priority + edge.Weight(); updatedDistance < dijkstraItems[successor].Priority {
-					dijkstraItems[successor].Priority = updatedDistance
-					heap.Fix(&pq, dijkstraItems[successor].index)
-					// reset predecessors
-					dijkstraItems[successor].Predecessors = []int{currentNodeId}
-				} else if updatedDistance == dijkstraItems[successor].Priority {
-					// add another predecessor
-					dijkstraItems[successor].Predecessors = append(dijkstraItems[successor].Predecessors, currentNodeId)
-				}
-			}
-		}
-
-		// Pruning / stopping criterion
-		if l1SettledCount >= l1PartSize {
-			break
-		}
-	}
-
-	return *successors[source]
-}
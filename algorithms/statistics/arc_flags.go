@@ -0,0 +1,73 @@
+package statistics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// FlagCompressionReport describes how well the arc flags of a graph are compressed by deduplicating flag vectors (see graph.CompressFlags).
+type FlagCompressionReport struct {
+	EdgeCount     int
+	Width         int // number of partitions, i.e. bits of each flag vector
+	UniqueVectors int // number of distinct flag vectors
+
+	EmptyEdges int // number of edges without any flag
+	FullEdges  int // number of edges, whose flags are all set
+	Top10Edges int // number of edges that refer to one of the ten most frequent flag vectors
+
+	UncompressedBytes int     // size of the flags with one bit per partition and edge, rounded up to whole bytes per edge
+	CompressedBytes   int     // size of the flags with one 32-bit index per edge and a table of distinct vectors
+	Ratio             float64 // UncompressedBytes / CompressedBytes
+}
+
+// AnalyzeFlagCompression computes the compression report of the arc flags of a graph.
+func AnalyzeFlagCompression[N any, E g.IFlaggedHalfEdge[W], W g.Weight](graph g.Graph[N, E]) FlagCompressionReport {
+	compressed := g.CompressFlags[N, E, W](graph)
+	report := FlagCompressionReport{EdgeCount: compressed.EdgeCount()}
+	if report.EdgeCount == 0 {
+		return report
+	}
+	table := compressed.Edges[0].Table
+	report.Width = table.Width
+	report.UniqueVectors = table.Len()
+
+	frequencies := make([]int, table.Len())
+	for _, edge := range compressed.Edges {
+		frequencies[edge.Index]++
+	}
+	for index, frequency := range frequencies {
+		flags := 0
+		for p := 0; p < table.Width; p++ {
+			if table.IsSet(uint32(index), g.PartitionId(p)) {
+				flags++
+			}
+		}
+		if flags == 0 {
+			report.EmptyEdges += frequency
+		} else if flags == table.Width {
+			report.FullEdges += frequency
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(frequencies)))
+	for i := 0; i < len(frequencies) && i < 10; i++ {
+		report.Top10Edges += frequencies[i]
+	}
+
+	report.UncompressedBytes = report.EdgeCount * ((table.Width + 7) / 8)
+	report.CompressedBytes = report.EdgeCount*4 + len(table.Vectors)*8
+	report.Ratio = float64(report.UncompressedBytes) / float64(report.CompressedBytes)
+	return report
+}
+
+// String implements fmt.Stringer
+func (r FlagCompressionReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Edges: %d, partitions: %d, distinct flag vectors: %d\n", r.EdgeCount, r.Width, r.UniqueVectors)
+	fmt.Fprintf(&sb, "Edges without flags: %d (%.2f%%), with all flags: %d (%.2f%%), with the ten most frequent vectors: %d (%.2f%%)\n",
+		r.EmptyEdges, percentage(r.EmptyEdges, r.EdgeCount), r.FullEdges, percentage(r.FullEdges, r.EdgeCount), r.Top10Edges, percentage(r.Top10Edges, r.EdgeCount))
+	fmt.Fprintf(&sb, "Flag size: %d bytes uncompressed, %d bytes compressed, ratio %.2f\n", r.UncompressedBytes, r.CompressedBytes, r.Ratio)
+	return sb.String()
+}
@@ -0,0 +1,34 @@
+package statistics
+
+import (
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestAnalyzeFlagCompression(t *testing.T) {
+	t.Parallel()
+
+	alg := &g.AdjacencyListGraph[g.GeoPoint, g.FlaggedHalfEdge[int, uint8]]{Edges: make([][]g.FlaggedHalfEdge[int, uint8], 6), EdgeCount_: 8}
+	for i, edges := range pathEdges() {
+		alg.Nodes = append(alg.Nodes, g.GeoPoint{})
+		for j, edge := range edges {
+			// flag vectors 0x01, 0xff and 0x00
+			flags := []uint8{0x01, 0xff, 0x00}[(i+j)%3]
+			alg.Edges[i] = append(alg.Edges[i], g.FlaggedHalfEdge[int, uint8]{To_: edge.To(), Weight_: edge.Weight(), Flag: flags})
+		}
+	}
+
+	report := AnalyzeFlagCompression[g.GeoPoint, g.FlaggedHalfEdge[int, uint8], int](alg)
+
+	if report.EdgeCount != 8 || report.Width != 8 || report.UniqueVectors != 3 {
+		t.Fatalf("Expected 8 edges with 3 distinct vectors of width 8, got %d edges with %d vectors of width %d", report.EdgeCount, report.UniqueVectors, report.Width)
+	}
+	if report.EmptyEdges+report.FullEdges != 5 || report.Top10Edges != 8 {
+		t.Errorf("Expected 5 empty or full edges and 8 edges with the most frequent vectors, got %d and %d", report.EmptyEdges+report.FullEdges, report.Top10Edges)
+	}
+	// 8 edges * 1 byte (8 partitions) vs. 8 edges * 4 bytes + 3 vectors * 8 bytes
+	if report.UncompressedBytes != 8 || report.CompressedBytes != 56 {
+		t.Errorf("Expected 8 uncompressed and 56 compressed bytes, got %d and %d", report.UncompressedBytes, report.CompressedBytes)
+	}
+}
@@ -0,0 +1,69 @@
+package statistics
+
+import (
+	"fmt"
+	"strings"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// GeoReport summarizes properties of graphs whose nodes are located in the geographic coordinate system.
+type GeoReport[W g.Weight] struct {
+	// bounding box of all nodes
+	MinLat float64
+	MaxLat float64
+	MinLon float64
+	MaxLon float64
+
+	// ShortEdges lists edges whose weight is below the distance between their endpoints.
+	// Such edges make distance-based heuristics (e.g. haversine) inadmissible for A* search.
+	ShortEdges []ShortEdge[W]
+}
+
+// ShortEdge describes an edge whose weight is below the distance between its endpoints.
+type ShortEdge[W g.Weight] struct {
+	Tail     g.NodeId
+	Head     g.NodeId
+	Weight   W
+	Distance W
+}
+
+// AnalyzeGeo computes the geographic report of a graph.
+// The distance function (e.g. heuristics.Haversine) computes a lower bound of the weight of an edge between two points.
+func AnalyzeGeo[N g.Locator, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], distance func(first, second g.GeoPoint) W) GeoReport[W] {
+	report := GeoReport[W]{ShortEdges: make([]ShortEdge[W], 0)}
+
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		point := graph.GetNode(tail).Location()
+		if tail == 0 {
+			report.MinLat, report.MaxLat, report.MinLon, report.MaxLon = point.Lat, point.Lat, point.Lon, point.Lon
+		}
+		report.MinLat = min(report.MinLat, point.Lat)
+		report.MaxLat = max(report.MaxLat, point.Lat)
+		report.MinLon = min(report.MinLon, point.Lon)
+		report.MaxLon = max(report.MaxLon, point.Lon)
+
+		for _, edge := range graph.GetHalfEdgesFrom(tail) {
+			d := distance(point, graph.GetNode(edge.To()).Location())
+			if edge.Weight() < d {
+				report.ShortEdges = append(report.ShortEdges, ShortEdge[W]{Tail: tail, Head: edge.To(), Weight: edge.Weight(), Distance: d})
+			}
+		}
+	}
+	return report
+}
+
+// String implements fmt.Stringer
+func (r GeoReport[W]) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Bounding box: lat [%f, %f], lon [%f, %f]\n", r.MinLat, r.MaxLat, r.MinLon, r.MaxLon)
+	fmt.Fprintf(&sb, "Edges shorter than the distance between their endpoints: %d\n", len(r.ShortEdges))
+	for i, edge := range r.ShortEdges {
+		if i == 10 {
+			fmt.Fprintf(&sb, "  ...\n")
+			break
+		}
+		fmt.Fprintf(&sb, "  %d->%d: weight %v < distance %v\n", edge.Tail, edge.Head, edge.Weight, edge.Distance)
+	}
+	return sb.String()
+}
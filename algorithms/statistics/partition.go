@@ -0,0 +1,184 @@
+package statistics
+
+import (
+	"fmt"
+	"strings"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// PartitionReport summarizes the quality of a partitioning with respect to arc flag preprocessing:
+// the preprocessing starts a backward search from every boundary node, and flags of regions
+// that are not connected or imbalanced are less selective.
+type PartitionReport struct {
+	NodeCount int
+	EdgeCount int
+
+	// Regions describes each region, indexed by the partition ID.
+	Regions []RegionReport
+
+	CutEdges            int     // number of edges whose tail and head are in different regions
+	BoundaryNodes       int     // total number of boundary nodes of all regions
+	MinSize             int     // number of nodes of the smallest region
+	MaxSize             int     // number of nodes of the largest region
+	Imbalance           float64 // size of the largest region relative to the average size minus one
+	EmptyRegions        int     // number of regions without any node
+	DisconnectedRegions int     // number of regions whose nodes are not weakly connected within the region
+
+	// EstimatedCost estimates the number of nodes settled by the arc flag preprocessing,
+	// i.e. one backward search of the entire graph per boundary node.
+	EstimatedCost float64
+}
+
+// RegionReport describes a single region of a partitioning.
+type RegionReport struct {
+	Partition     g.PartitionId
+	Size          int // number of nodes
+	BoundaryNodes int // number of nodes with an entering edge from another region
+	CutEdges      int // number of edges entering the region from another region
+	Components    int // number of weakly connected components of the subgraph induced by the region
+}
+
+// TwoLevelPartitionReport summarizes the quality of a two-level partitioning.
+type TwoLevelPartitionReport struct {
+	// L1 describes the level 1 regions.
+	L1 PartitionReport
+	// L2 describes the level 2 regions, where the region of a node is identified by L1Part * L2Count + L2Part.
+	L2      PartitionReport
+	L2Count int // number of level 2 regions per level 1 region
+
+	// EstimatedCost estimates the number of nodes settled by the two-level arc flag preprocessing, i.e. one backward search of
+	// the entire graph per level 1 boundary node and one backward search of the level 1 region per level 2 boundary node.
+	EstimatedCost float64
+}
+
+// AnalyzePartition computes the report of the partitioning of a graph.
+// The number of regions is determined by the largest partition ID.
+func AnalyzePartition[N g.Partitioner, E g.IHalfEdge](graph g.Graph[N, E]) PartitionReport {
+	regions := make([]int, graph.NodeCount())
+	for id := range regions {
+		regions[id] = int(graph.GetNode(id).Partition())
+	}
+	report := analyzeRegions[N, E](graph, regions)
+	report.EstimatedCost = float64(report.BoundaryNodes) * float64(report.NodeCount)
+	return report
+}
+
+// AnalyzeTwoLevelPartition computes the report of the two-level partitioning of a graph.
+// The numbers of level 1 and level 2 regions are determined by the largest partition IDs.
+func AnalyzeTwoLevelPartition[N g.TwoLevelPartitioner, E g.IHalfEdge](graph g.Graph[N, E]) TwoLevelPartitionReport {
+	l1Regions := make([]int, graph.NodeCount())
+	report := TwoLevelPartitionReport{}
+	for id := range l1Regions {
+		l1Regions[id] = int(graph.GetNode(id).L1Part())
+		report.L2Count = max(report.L2Count, int(graph.GetNode(id).L2Part())+1)
+	}
+	l2Regions := make([]int, graph.NodeCount())
+	for id := range l2Regions {
+		l2Regions[id] = l1Regions[id]*report.L2Count + int(graph.GetNode(id).L2Part())
+	}
+
+	report.L1 = analyzeRegions[N, E](graph, l1Regions)
+	report.L2 = analyzeRegions[N, E](graph, l2Regions)
+	report.L1.EstimatedCost = float64(report.L1.BoundaryNodes) * float64(report.L1.NodeCount)
+	for region, l2Report := range report.L2.Regions {
+		report.L2.EstimatedCost += float64(l2Report.BoundaryNodes) * float64(report.L1.Regions[region/report.L2Count].Size)
+	}
+	report.EstimatedCost = report.L1.EstimatedCost + report.L2.EstimatedCost
+	return report
+}
+
+// analyzeRegions computes the report of a partitioning, where regions[id] is the region of the node with ID=id.
+func analyzeRegions[N any, E g.IHalfEdge](graph g.Graph[N, E], regions []int) PartitionReport {
+	report := PartitionReport{NodeCount: graph.NodeCount(), EdgeCount: graph.EdgeCount(), Regions: make([]RegionReport, 0)}
+	for _, region := range regions {
+		for len(report.Regions) <= region {
+			report.Regions = append(report.Regions, RegionReport{Partition: g.PartitionId(len(report.Regions))})
+		}
+		report.Regions[region].Size++
+	}
+
+	// union-find with path halving for the components of each region
+	parents := make([]int, graph.NodeCount())
+	for i := range parents {
+		parents[i] = i
+	}
+	find := func(x int) int {
+		for parents[x] != x {
+			parents[x] = parents[parents[x]]
+			x = parents[x]
+		}
+		return x
+	}
+
+	boundary := make([]bool, graph.NodeCount())
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		for _, edge := range graph.GetHalfEdgesFrom(tail) {
+			head := edge.To()
+			if regions[tail] == regions[head] {
+				if a, b := find(tail), find(head); a != b {
+					parents[a] = b
+				}
+				continue
+			}
+			report.CutEdges++
+			report.Regions[regions[head]].CutEdges++
+			if !boundary[head] {
+				boundary[head] = true
+				report.BoundaryNodes++
+				report.Regions[regions[head]].BoundaryNodes++
+			}
+		}
+	}
+	for id := range regions {
+		if find(id) == id {
+			report.Regions[regions[id]].Components++
+		}
+	}
+
+	for i, region := range report.Regions {
+		if i == 0 || region.Size < report.MinSize {
+			report.MinSize = region.Size
+		}
+		report.MaxSize = max(report.MaxSize, region.Size)
+		if region.Size == 0 {
+			report.EmptyRegions++
+		}
+		if region.Components > 1 {
+			report.DisconnectedRegions++
+		}
+	}
+	if len(report.Regions) > 0 && report.NodeCount > 0 {
+		report.Imbalance = float64(report.MaxSize)/(float64(report.NodeCount)/float64(len(report.Regions))) - 1
+	}
+	return report
+}
+
+// String implements fmt.Stringer
+func (r PartitionReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Regions: %d, empty: %d, disconnected: %d\n", len(r.Regions), r.EmptyRegions, r.DisconnectedRegions)
+	fmt.Fprintf(&sb, "Region sizes: min %d, max %d, imbalance %.3f\n", r.MinSize, r.MaxSize, r.Imbalance)
+	fmt.Fprintf(&sb, "Cut edges: %d (%.2f%%), boundary nodes: %d (%.2f%%)\n", r.CutEdges, percentage(r.CutEdges, r.EdgeCount), r.BoundaryNodes, percentage(r.BoundaryNodes, r.NodeCount))
+	fmt.Fprintf(&sb, "Estimated preprocessing cost: %.3g settled nodes\n", r.EstimatedCost)
+	for _, region := range r.Regions {
+		fmt.Fprintf(&sb, "  %d: %d nodes, %d boundary nodes, %d entering cut edges, %d components\n", region.Partition, region.Size, region.BoundaryNodes, region.CutEdges, region.Components)
+	}
+	return sb.String()
+}
+
+// String implements fmt.Stringer
+func (r TwoLevelPartitionReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Level 1:\n%s", r.L1)
+	fmt.Fprintf(&sb, "Level 2 (region = L1Part * %d + L2Part):\n%s", r.L2Count, r.L2)
+	fmt.Fprintf(&sb, "Estimated preprocessing cost: %.3g settled nodes\n", r.EstimatedCost)
+	return sb.String()
+}
+
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(part) / float64(total)
+}
@@ -0,0 +1,83 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// pathEdges returns the undirected path 0-1-2-3-4 as adjacency lists of six nodes, such that node 5 is isolated.
+func pathEdges() [][]g.WeightedHalfEdge[int] {
+	edges := make([][]g.WeightedHalfEdge[int], 6)
+	for u := 0; u < 4; u++ {
+		edges[u] = append(edges[u], g.WeightedHalfEdge[int]{To_: u + 1, Weight_: 1})
+		edges[u+1] = append(edges[u+1], g.WeightedHalfEdge[int]{To_: u, Weight_: 1})
+	}
+	return edges
+}
+
+func TestAnalyzePartition(t *testing.T) {
+	t.Parallel()
+
+	alg := &g.AdjacencyListGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]]{Edges: pathEdges(), EdgeCount_: 8}
+	for _, partition := range []g.PartitionId{0, 0, 0, 1, 1, 0} {
+		alg.Nodes = append(alg.Nodes, g.PartGeoPoint{Partition_: partition})
+	}
+
+	report := AnalyzePartition[g.PartGeoPoint, g.WeightedHalfEdge[int]](alg)
+
+	if len(report.Regions) != 2 {
+		t.Fatalf("Expected 2 regions, got %d", len(report.Regions))
+	}
+	// edges 2->3 and 3->2 are cut, nodes 2 and 3 are boundary nodes
+	if report.CutEdges != 2 || report.BoundaryNodes != 2 {
+		t.Errorf("Expected 2 cut edges and 2 boundary nodes, got %d and %d", report.CutEdges, report.BoundaryNodes)
+	}
+	expected := []RegionReport{
+		{Partition: 0, Size: 4, BoundaryNodes: 1, CutEdges: 1, Components: 2},
+		{Partition: 1, Size: 2, BoundaryNodes: 1, CutEdges: 1, Components: 1},
+	}
+	for i, region := range report.Regions {
+		if region != expected[i] {
+			t.Errorf("Expected region %v, got %v", expected[i], region)
+		}
+	}
+	if report.MinSize != 2 || report.MaxSize != 4 || math.Abs(report.Imbalance-1.0/3) > 1e-9 {
+		t.Errorf("Expected sizes in [2, 4] with imbalance 0.333, got [%d, %d] with imbalance %f", report.MinSize, report.MaxSize, report.Imbalance)
+	}
+	if report.DisconnectedRegions != 1 || report.EmptyRegions != 0 {
+		t.Errorf("Expected 1 disconnected and 0 empty regions, got %d and %d", report.DisconnectedRegions, report.EmptyRegions)
+	}
+	if report.EstimatedCost != 12 {
+		t.Errorf("Expected estimated cost 12, got %f", report.EstimatedCost)
+	}
+}
+
+func TestAnalyzeTwoLevelPartition(t *testing.T) {
+	t.Parallel()
+
+	alg := &g.AdjacencyListGraph[g.TwoLevelPartGeoPoint, g.WeightedHalfEdge[int]]{Edges: pathEdges(), EdgeCount_: 8}
+	l1Parts := []g.PartitionId{0, 0, 0, 1, 1, 0}
+	l2Parts := []g.PartitionId{0, 0, 1, 0, 1, 1}
+	for i := range l1Parts {
+		alg.Nodes = append(alg.Nodes, g.TwoLevelPartGeoPoint{L1Part_: l1Parts[i], L2Part_: l2Parts[i]})
+	}
+
+	report := AnalyzeTwoLevelPartition[g.TwoLevelPartGeoPoint, g.WeightedHalfEdge[int]](alg)
+
+	if report.L2Count != 2 || len(report.L1.Regions) != 2 || len(report.L2.Regions) != 4 {
+		t.Fatalf("Expected 2 level 1 and 4 level 2 regions, got %d and %d", len(report.L1.Regions), len(report.L2.Regions))
+	}
+	if report.L1.CutEdges != 2 || report.L1.BoundaryNodes != 2 {
+		t.Errorf("Expected 2 level 1 cut edges and boundary nodes, got %d and %d", report.L1.CutEdges, report.L1.BoundaryNodes)
+	}
+	// level 2 regions {0, 1}, {2, 5}, {3} and {4}
+	if report.L2.CutEdges != 6 || report.L2.BoundaryNodes != 4 || report.L2.DisconnectedRegions != 1 {
+		t.Errorf("Expected 6 level 2 cut edges, 4 boundary nodes and 1 disconnected region, got %d, %d and %d", report.L2.CutEdges, report.L2.BoundaryNodes, report.L2.DisconnectedRegions)
+	}
+	// level 1: 2 boundary nodes * 6 nodes, level 2: 2 boundary nodes * 4 nodes + 2 boundary nodes * 2 nodes
+	if report.L1.EstimatedCost != 12 || report.L2.EstimatedCost != 12 || report.EstimatedCost != 24 {
+		t.Errorf("Expected estimated costs 12 + 12 = 24, got %f + %f = %f", report.L1.EstimatedCost, report.L2.EstimatedCost, report.EstimatedCost)
+	}
+}
@@ -0,0 +1,313 @@
+package statistics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Report summarizes structural properties of a weighted graph, which are relevant before running preprocessing steps.
+type Report[W g.Weight] struct {
+	NodeCount int
+	EdgeCount int
+
+	// OutDegrees and InDegrees map a degree to the number of nodes with this degree.
+	OutDegrees map[int]int
+	InDegrees  map[int]int
+	MaxDegree  int     // maximum out-degree
+	AvgDegree  float64 // average out-degree
+
+	SelfLoops       int // number of edges whose head equals their tail
+	ParallelEdges   int // number of edges that have the same tail and head as a previous edge
+	AsymmetricEdges int // number of edges (u, v) without a reverse edge (v, u)
+	IsolatedNodes   int // number of nodes without any leaving or entering edge
+
+	MinWeight       W
+	MaxWeight       W
+	ZeroWeights     int // number of edges with weight 0
+	NegativeWeights int // number of edges with negative weight
+	WeightHistogram []HistogramBin[W]
+
+	// ComponentSizes lists the sizes of weakly connected components in descending order.
+	ComponentSizes []int
+	// StrongComponentSizes lists the sizes of strongly connected components in descending order.
+	StrongComponentSizes []int
+}
+
+// HistogramBin counts the edges whose weight is in the interval [From, To).
+// The last bin of a histogram is closed, i.e. it includes edges with weight To.
+type HistogramBin[W g.Weight] struct {
+	From  W
+	To    W
+	Count int
+}
+
+// Analyze computes the report of a graph. The weight histogram consists of (at most) the given number of bins.
+func Analyze[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], bins int) Report[W] {
+	report := Report[W]{
+		NodeCount:  graph.NodeCount(),
+		EdgeCount:  graph.EdgeCount(),
+		OutDegrees: make(map[int]int),
+		InDegrees:  make(map[int]int),
+	}
+
+	// sorted heads of each node allow to detect parallel and asymmetric edges
+	offsets := make([]int, graph.NodeCount()+1)
+	heads := make([]g.NodeId, 0, graph.EdgeCount())
+	inDegrees := make([]int, graph.NodeCount())
+	first := true
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		edges := graph.GetHalfEdgesFrom(tail)
+		report.OutDegrees[len(edges)]++
+		report.MaxDegree = max(report.MaxDegree, len(edges))
+
+		for _, edge := range edges {
+			heads = append(heads, edge.To())
+			inDegrees[edge.To()]++
+			if edge.To() == tail {
+				report.SelfLoops++
+			}
+
+			weight := edge.Weight()
+			if first || weight < report.MinWeight {
+				report.MinWeight = weight
+			}
+			if first || weight > report.MaxWeight {
+				report.MaxWeight = weight
+			}
+			first = false
+			if weight == 0 {
+				report.ZeroWeights++
+			} else if weight < 0 {
+				report.NegativeWeights++
+			}
+		}
+		offsets[tail+1] = len(heads)
+		segment := heads[offsets[tail]:]
+		sort.Ints(segment)
+		for i := 1; i < len(segment); i++ {
+			if segment[i] == segment[i-1] {
+				report.ParallelEdges++
+			}
+		}
+	}
+	if graph.NodeCount() > 0 {
+		report.AvgDegree = float64(len(heads)) / float64(graph.NodeCount())
+	}
+
+	for node := 0; node < graph.NodeCount(); node++ {
+		report.InDegrees[inDegrees[node]]++
+		if inDegrees[node] == 0 && offsets[node+1] == offsets[node] {
+			report.IsolatedNodes++
+		}
+		for _, head := range heads[offsets[node]:offsets[node+1]] {
+			reverse := heads[offsets[head]:offsets[head+1]]
+			if i := sort.SearchInts(reverse, node); i == len(reverse) || reverse[i] != node {
+				report.AsymmetricEdges++
+			}
+		}
+	}
+
+	report.WeightHistogram = weightHistogram(graph, report.MinWeight, report.MaxWeight, bins)
+	report.ComponentSizes = weakComponentSizes(offsets, heads)
+	report.StrongComponentSizes = strongComponentSizes(offsets, heads)
+	return report
+}
+
+// weightHistogram counts the edge weights in equally sized bins between minWeight and maxWeight.
+func weightHistogram[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], minWeight, maxWeight W, bins int) []HistogramBin[W] {
+	if graph.EdgeCount() == 0 || bins < 1 {
+		return []HistogramBin[W]{}
+	}
+	width := float64(maxWeight-minWeight) / float64(bins)
+	if width == 0 {
+		return []HistogramBin[W]{{From: minWeight, To: maxWeight, Count: graph.EdgeCount()}}
+	}
+
+	histogram := make([]HistogramBin[W], bins)
+	for i := range histogram {
+		histogram[i].From = minWeight + W(float64(i)*width)
+		histogram[i].To = minWeight + W(float64(i+1)*width)
+	}
+	histogram[bins-1].To = maxWeight
+
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		for _, edge := range graph.GetHalfEdgesFrom(tail) {
+			bin := int(float64(edge.Weight()-minWeight) / width)
+			if bin >= bins {
+				bin = bins - 1
+			}
+			histogram[bin].Count++
+		}
+	}
+	return histogram
+}
+
+// weakComponentSizes computes the sizes of the weakly connected components of a graph given as sorted adjacency array.
+func weakComponentSizes(offsets []int, heads []g.NodeId) []int {
+	nodeCount := len(offsets) - 1
+
+	// union-find with path halving
+	parents := make([]int, nodeCount)
+	for i := range parents {
+		parents[i] = i
+	}
+	find := func(x int) int {
+		for parents[x] != x {
+			parents[x] = parents[parents[x]]
+			x = parents[x]
+		}
+		return x
+	}
+	for tail := 0; tail < nodeCount; tail++ {
+		for _, head := range heads[offsets[tail]:offsets[tail+1]] {
+			if a, b := find(tail), find(head); a != b {
+				parents[a] = b
+			}
+		}
+	}
+
+	sizes := make(map[int]int)
+	for node := 0; node < nodeCount; node++ {
+		sizes[find(node)]++
+	}
+	return sortedSizes(sizes)
+}
+
+// strongComponentSizes computes the sizes of the strongly connected components of a graph given as adjacency array.
+// Iterative implementation of Tarjan's algorithm.
+func strongComponentSizes(offsets []int, heads []g.NodeId) []int {
+	nodeCount := len(offsets) - 1
+
+	index := make([]int, nodeCount)
+	lowLink := make([]int, nodeCount)
+	onStack := make([]bool, nodeCount)
+	for i := range index {
+		index[i] = -1
+	}
+
+	sizes := make(map[int]int)
+	stack := make([]int, 0)
+	counter := 0
+
+	// call stack of the depth-first search: node and position of the next edge to process
+	type frame struct {
+		node int
+		edge int
+	}
+	for root := 0; root < nodeCount; root++ {
+		if index[root] != -1 {
+			continue
+		}
+		callStack := []frame{{node: root, edge: offsets[root]}}
+		index[root], lowLink[root] = counter, counter
+		counter++
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(callStack) > 0 {
+			top := &callStack[len(callStack)-1]
+			if top.edge < offsets[top.node+1] {
+				head := heads[top.edge]
+				top.edge++
+				if index[head] == -1 {
+					index[head], lowLink[head] = counter, counter
+					counter++
+					stack = append(stack, head)
+					onStack[head] = true
+					callStack = append(callStack, frame{node: head, edge: offsets[head]})
+				} else if onStack[head] {
+					lowLink[top.node] = min(lowLink[top.node], index[head])
+				}
+				continue
+			}
+
+			// all edges of top.node have been processed
+			node := top.node
+			callStack = callStack[:len(callStack)-1]
+			if len(callStack) > 0 {
+				parent := callStack[len(callStack)-1].node
+				lowLink[parent] = min(lowLink[parent], lowLink[node])
+			}
+			if lowLink[node] == index[node] {
+				// node is the root of a strongly connected component
+				for {
+					member := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[member] = false
+					sizes[node]++
+					if member == node {
+						break
+					}
+				}
+			}
+		}
+	}
+	return sortedSizes(sizes)
+}
+
+func sortedSizes(sizes map[int]int) []int {
+	sorted := make([]int, 0, len(sizes))
+	for _, size := range sizes {
+		sorted = append(sorted, size)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	return sorted
+}
+
+// String implements fmt.Stringer
+func (r Report[W]) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Nodes: %d, edges: %d\n", r.NodeCount, r.EdgeCount)
+	fmt.Fprintf(&sb, "Out-degree: max %d, avg %.2f\n", r.MaxDegree, r.AvgDegree)
+	fmt.Fprintf(&sb, "Out-degree distribution: %s\n", formatDistribution(r.OutDegrees))
+	fmt.Fprintf(&sb, "In-degree distribution: %s\n", formatDistribution(r.InDegrees))
+	fmt.Fprintf(&sb, "Self-loops: %d, parallel edges: %d, asymmetric edges: %d, isolated nodes: %d\n", r.SelfLoops, r.ParallelEdges, r.AsymmetricEdges, r.IsolatedNodes)
+	fmt.Fprintf(&sb, "Weights: min %v, max %v, zero %d, negative %d\n", r.MinWeight, r.MaxWeight, r.ZeroWeights, r.NegativeWeights)
+	for _, bin := range r.WeightHistogram {
+		fmt.Fprintf(&sb, "  [%v, %v): %d\n", bin.From, bin.To, bin.Count)
+	}
+	fmt.Fprintf(&sb, "Weakly connected components: %d, largest: %s\n", len(r.ComponentSizes), formatSizes(r.ComponentSizes))
+	fmt.Fprintf(&sb, "Strongly connected components: %d, largest: %s\n", len(r.StrongComponentSizes), formatSizes(r.StrongComponentSizes))
+	return sb.String()
+}
+
+// formatDistribution lists a degree distribution in ascending order of degrees.
+func formatDistribution(distribution map[int]int) string {
+	degrees := make([]int, 0, len(distribution))
+	for degree := range distribution {
+		degrees = append(degrees, degree)
+	}
+	sort.Ints(degrees)
+	entries := make([]string, 0, len(degrees))
+	for _, degree := range degrees {
+		entries = append(entries, fmt.Sprintf("%d:%d", degree, distribution[degree]))
+	}
+	return strings.Join(entries, " ")
+}
+
+// formatSizes lists the ten largest component sizes.
+func formatSizes(sizes []int) string {
+	if len(sizes) > 10 {
+		return fmt.Sprint(sizes[:10]) + " ..."
+	}
+	return fmt.Sprint(sizes)
+}
+
+// Maximum Implementation for generic number types
+func max[T int | float64](a, b T) T {
+	if a >= b {
+		return a
+	}
+	return b
+}
+
+// Minimum Implementation for generic number types
+func min[T int | float64](a, b T) T {
+	if a <= b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,63 @@
+package statistics
+
+import (
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	// nodes 0, 1 and 2 form a cycle, node 3 is reachable from node 2 only and node 4 is isolated
+	aag := &g.AdjacencyArrayGraph[g.GeoPoint, g.WeightedHalfEdge[int]]{
+		Nodes: []g.GeoPoint{{Lat: 0, Lon: 0}, {Lat: 0, Lon: 1}, {Lat: 1, Lon: 1}, {Lat: 2, Lon: 2}, {Lat: -5, Lon: 3}},
+		Edges: []g.WeightedHalfEdge[int]{
+			{To_: 1, Weight_: 10}, {To_: 1, Weight_: 12}, // parallel edges
+			{To_: 2, Weight_: 0}, {To_: 0, Weight_: 10}, {To_: 1, Weight_: 1}, // self-loop
+			{To_: 0, Weight_: 20}, {To_: 3, Weight_: -1},
+		},
+		Offsets: []int{0, 2, 5, 7, 7, 7},
+	}
+
+	report := Analyze[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, 4)
+
+	if report.SelfLoops != 1 || report.ParallelEdges != 1 || report.IsolatedNodes != 1 {
+		t.Errorf("Unexpected counts: %d self-loops, %d parallel edges, %d isolated nodes", report.SelfLoops, report.ParallelEdges, report.IsolatedNodes)
+	}
+	// asymmetric edges: 1->2, 2->0 and 2->3 (0->1 twice has reverse edge 1->0)
+	if report.AsymmetricEdges != 3 {
+		t.Errorf("Unexpected number of asymmetric edges: %d", report.AsymmetricEdges)
+	}
+	if report.MinWeight != -1 || report.MaxWeight != 20 || report.ZeroWeights != 1 || report.NegativeWeights != 1 {
+		t.Errorf("Unexpected weights: min %d, max %d, %d zero, %d negative", report.MinWeight, report.MaxWeight, report.ZeroWeights, report.NegativeWeights)
+	}
+	count := 0
+	for _, bin := range report.WeightHistogram {
+		count += bin.Count
+	}
+	if len(report.WeightHistogram) != 4 || count != aag.EdgeCount() {
+		t.Errorf("Unexpected weight histogram: %v", report.WeightHistogram)
+	}
+	if len(report.ComponentSizes) != 2 || report.ComponentSizes[0] != 4 || report.ComponentSizes[1] != 1 {
+		t.Errorf("Unexpected weakly connected components: %v", report.ComponentSizes)
+	}
+	if len(report.StrongComponentSizes) != 3 || report.StrongComponentSizes[0] != 3 {
+		t.Errorf("Unexpected strongly connected components: %v", report.StrongComponentSizes)
+	}
+	if report.OutDegrees[0] != 2 || report.OutDegrees[2] != 2 || report.OutDegrees[3] != 1 || report.InDegrees[0] != 1 {
+		t.Errorf("Unexpected degree distributions: out %v, in %v", report.OutDegrees, report.InDegrees)
+	}
+
+	// every edge except for the self-loop is shorter than 100 times the squared euclidean distance
+	geoReport := AnalyzeGeo[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, func(first, second g.GeoPoint) int {
+		dLat, dLon := first.Lat-second.Lat, first.Lon-second.Lon
+		return int(100 * (dLat*dLat + dLon*dLon))
+	})
+	if geoReport.MinLat != -5 || geoReport.MaxLat != 2 || geoReport.MinLon != 0 || geoReport.MaxLon != 3 {
+		t.Errorf("Unexpected bounding box: %v", geoReport)
+	}
+	if len(geoReport.ShortEdges) != 6 {
+		t.Errorf("Unexpected short edges: %v", geoReport.ShortEdges)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"math/rand"
 	"time"
 
@@ -19,7 +20,13 @@ const outputGraphFile = "out.fmi"
 func main() {
 
 	start := time.Now()
-	falg := fmi.NewAdjacencyListFromFmi(inputGraphFile, fmi.Parse2LPartGeoPoint, fmi.Parse2LFlaggedHalfEdge)
+	// read the base graph, whose partitions and arc flags are computed below
+	nodeSchema := fmi.MustSchema[g.TwoLevelPartGeoPoint](fmi.ID_COLUMN, "Lat", "Lon").Lenient()
+	edgeSchema := fmi.MustSchema[g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]](fmi.FROM_COLUMN, "To_", "Weight_").Lenient()
+	falg, err := fmi.ReadFmiFile(inputGraphFile, nodeSchema, edgeSchema)
+	if err != nil {
+		log.Fatal(err)
+	}
 	faag := g.NewAdjacencyArrayFromGraph[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]](falg)
 	elapsed := time.Since(start)
 	fmt.Printf("[TIME-FileReader] = %s\n", elapsed)
@@ -28,6 +35,7 @@ func main() {
 	faag = partitioning.TwoLevelGridPartitioning(faag, 4, 8, 4, 8) // 32x32 partitions
 	//faag = partitioning.TwoLevelGridPartitioning(faag, 4, 4, 4, 4) // 16x16 partitions
 	//faag = partitioning.TwoLevelGridPartitioning(faag, 2, 4, 2, 4) // 8x8 partitions
+	//faag = partitioning.TwoLevelInertialFlowPartitioning(faag, 5, 5) // 32x32 partitions
 	elapsed = time.Since(start)
 	fmt.Printf("[TIME-Partitioning] = %s\n", elapsed)
 
@@ -36,9 +44,19 @@ func main() {
 	elapsed = time.Since(start)
 	fmt.Printf("[TIME-ArcFlagComputation] = %s\n", elapsed)
 
-	fmi.WriteFmi[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]](faag, outputGraphFile, fmi.TwoLevelPartGeoPoint2FmiLine, fmi.TwoLevelFlaggedHalfEdge2FmiLine)
+	// record the provenance of the arc flags: the topology and weights of the base graph are not changed by the preprocessing
+	metadata := fmi.NewMetadata(fmi.ARTIFACT_TWO_LEVEL_ARC_FLAGS, g.Fingerprint[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64], int](faag))
+	metadata.Partitioner = "two-level grid"
+	metadata.Parameters["input"] = inputGraphFile
+	metadata.Parameters["l1"], metadata.Parameters["l2"] = "4x8", "4x8"
+	if err := fmi.WriteFmiArtifactFile[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]](faag, outputGraphFile, fmi.TwoLevelPartGeoPointSchema, fmi.TwoLevelFlaggedHalfEdgeSchema, metadata); err != nil {
+		log.Fatal(err)
+	}
 
-	falg = fmi.NewAdjacencyListFromFmi(outputGraphFile, fmi.Parse2LPartGeoPoint, fmi.Parse2LFlaggedHalfEdge)
+	falg, _, err = fmi.ReadFmiArtifactFile(outputGraphFile, fmi.TwoLevelPartGeoPointSchema, fmi.TwoLevelFlaggedHalfEdgeSchema, metadata.BaseGraph)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	testedRouter := sp.TwoLevelArcFlagRouter[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64], int]{Graph: falg}
 	baselineRouter := sp.DijkstraRouter[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64], int]{Graph: falg}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"time"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	"github.com/dmholtz/graffiti/algorithms/statistics"
+	fmi "github.com/dmholtz/graffiti/examples/io"
+	"github.com/dmholtz/graffiti/examples/partitioning"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+const defaultGraph = "graphs/ocean_equi_4.fmi"
+
+// maxDepth is the maximum bisection depth, since the width of a flag matrix is limited to math.MaxUint16 partitions
+const maxDepth = 15
+
+type matrixGraph = g.PartitionedGraph[g.GeoPoint, g.MatrixFlaggedHalfEdge[int]]
+
+// arcflag_matrix_preprocessor computes arc flags of a graph of GeoPoints in the .fmi format for 2^depth inertial flow partitions,
+// e.g. 512 or 1024, reports the compression ratio of deduplicated flag vectors and stores the flags in a flag matrix file.
+// The flags are read back and the arc flag router is compared against textbook Dijkstra.
+// Additional node and edge columns (e.g. partitions and arc flags) are ignored.
+func main() {
+	graphFile := flag.String("graph", defaultGraph, "path to the .fmi file")
+	depth := flag.Int("depth", 9, "bisection depth of the inertial flow partitioning, i.e. 2^depth partitions (at most 15)")
+	outputFile := flag.String("o", "out.flags", "path to the flag matrix file")
+	n := flag.Int("n", 100, "number of random tests")
+	flag.Parse()
+	if *depth < 0 || *depth > maxDepth {
+		log.Fatalf("invalid depth %d: must be in [0, %d]", *depth, maxDepth)
+	}
+
+	start := time.Now()
+	alg, err := fmi.ReadFmiFile(*graphFile, fmi.GeoPointSchema.Lenient(), fmi.WeightedHalfEdgeSchema.Lenient())
+	if err != nil {
+		log.Fatal(err)
+	}
+	aag := g.NewAdjacencyArrayFromGraph[g.GeoPoint, g.WeightedHalfEdge[int]](alg)
+	fmt.Printf("[TIME-FileReader] = %s\n", time.Since(start))
+
+	start = time.Now()
+	assignment := partitioning.InertialFlowAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](aag, *depth)
+	fmt.Printf("[TIME-Partitioning] = %s\n", time.Since(start))
+
+	start = time.Now()
+	partitionCount := 1 << *depth
+	matrix := g.NewFlagMatrix(aag.EdgeCount(), partitionCount)
+	pg := &matrixGraph{Graph: g.NewMatrixFlaggedGraph[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, matrix), Assignment: assignment}
+	sp.ComputeArcFlags[g.PartitionedNode[g.GeoPoint], g.MatrixFlaggedHalfEdge[int], int](pg, pg, partitionCount)
+	fmt.Printf("[TIME-ArcFlagComputation] = %s\n", time.Since(start))
+	fmt.Print(statistics.AnalyzeFlagCompression[g.PartitionedNode[g.GeoPoint], g.MatrixFlaggedHalfEdge[int], int](pg))
+
+	// the flags are attached to the rows of the matrix, i.e. they refer to the edge order of the weighted graph
+	metadata := fmi.NewMetadata(fmi.ARTIFACT_FLAG_MATRIX, 0)
+	metadata.Partitioner = "inertial-flow"
+	metadata.Parameters["input"] = *graphFile
+	metadata.Parameters["depth"], metadata.Parameters["partitions"] = strconv.Itoa(*depth), strconv.Itoa(partitionCount)
+	if err := fmi.WriteFlagMatrixFile[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, assignment, matrix, metadata, *outputFile); err != nil {
+		log.Fatal(err)
+	}
+
+	readMatrix, _, err := fmi.ReadFlagMatrixFile[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, assignment, *outputFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	faag := &matrixGraph{Graph: g.NewMatrixFlaggedGraph[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, readMatrix), Assignment: assignment}
+
+	testedRouter := sp.ArcFlagRouter[g.PartitionedNode[g.GeoPoint], g.MatrixFlaggedHalfEdge[int], int]{Graph: faag}
+	baselineRouter := sp.DijkstraRouter[g.PartitionedNode[g.GeoPoint], g.MatrixFlaggedHalfEdge[int], int]{Graph: faag}
+
+	fmt.Printf("Compare %d random searches of arc flag Dijkstra with %d partitions against textbook Dijkstra.\n", *n, partitionCount)
+	dijkstraPqPops, arcFlagDijkstraPqPops := 0, 0
+	for i := 0; i < *n; i++ {
+		source := rand.Intn(aag.NodeCount())
+		target := rand.Intn(aag.NodeCount())
+
+		dijkstraResult := baselineRouter.Route(source, target, false)
+		arcFlagDijkstraResult := testedRouter.Route(source, target, false)
+
+		if dijkstraResult.Length != arcFlagDijkstraResult.Length {
+			fmt.Printf("[Path(source=%d, target=%d)]: Different lengths found: Dijkstra=%d, ArcFlagDijkstra=%d\n", source, target, dijkstraResult.Length, arcFlagDijkstraResult.Length)
+		}
+
+		// maintain performance indicators
+		dijkstraPqPops += dijkstraResult.PqPops
+		arcFlagDijkstraPqPops += arcFlagDijkstraResult.PqPops
+	}
+	if *n > 0 {
+		fmt.Printf("Average number of Pop() operations on priority queue: %d (Dijkstra), %d (ArcFlag Dijkstra)\n", dijkstraPqPops/(*n), arcFlagDijkstraPqPops/(*n))
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	fmi "github.com/dmholtz/graffiti/examples/io"
+	"github.com/dmholtz/graffiti/examples/partitioning"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+const defaultGraph = "graphs/ocean_equi_4.fmi"
+
+// arcflag_multilevel_preprocessor computes multi-level arc flags of a graph of GeoPoints in the .fmi format with a hierarchical
+// inertial flow partitioning and compares the arc flag router against textbook Dijkstra, e.g. to explore three- or four-level schemes.
+// Additional node and edge columns (e.g. partitions and arc flags) are ignored.
+func main() {
+	graphFile := flag.String("graph", defaultGraph, "path to the .fmi file")
+	depths := flag.String("depths", "2,2,2", "comma-separated bisection depth of each level (2 to 4 levels, at most 6 per level)")
+	n := flag.Int("n", 100, "number of random tests")
+	flag.Parse()
+
+	levelDepths := make([]int, 0)
+	for _, field := range strings.Split(*depths, ",") {
+		depth, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			log.Fatalf("invalid depth %q", field)
+		}
+		levelDepths = append(levelDepths, depth)
+	}
+
+	start := time.Now()
+	alg, err := fmi.ReadFmiFile(*graphFile, fmi.GeoPointSchema.Lenient(), fmi.WeightedHalfEdgeSchema.Lenient())
+	if err != nil {
+		log.Fatal(err)
+	}
+	aag := g.NewAdjacencyArrayFromGraph[g.GeoPoint, g.WeightedHalfEdge[int]](alg)
+	fmt.Printf("[TIME-FileReader] = %s\n", time.Since(start))
+
+	// the number of levels is part of the edge type
+	switch len(levelDepths) {
+	case 2:
+		run[[2]uint64](aag, levelDepths, *n)
+	case 3:
+		run[[3]uint64](aag, levelDepths, *n)
+	case 4:
+		run[[4]uint64](aag, levelDepths, *n)
+	default:
+		log.Fatalf("unsupported number of levels: %d", len(levelDepths))
+	}
+}
+
+func run[L g.FlagLevels](aag *g.AdjacencyArrayGraph[g.GeoPoint, g.WeightedHalfEdge[int]], depths []int, n int) {
+	nodes := make([]g.MultiLevelPartGeoPoint, aag.NodeCount())
+	for i, node := range aag.Nodes {
+		nodes[i] = g.MultiLevelPartGeoPoint{GeoPoint: node}
+	}
+	edges := make([]g.MultiLevelFlaggedHalfEdge[int, L], aag.EdgeCount())
+	for i, edge := range aag.Edges {
+		edges[i] = g.MultiLevelFlaggedHalfEdge[int, L]{To_: edge.To(), Weight_: edge.Weight()}
+	}
+	faag := &g.AdjacencyArrayGraph[g.MultiLevelPartGeoPoint, g.MultiLevelFlaggedHalfEdge[int, L]]{Nodes: nodes, Edges: edges, Offsets: aag.Offsets}
+
+	start := time.Now()
+	faag = partitioning.MultiLevelInertialFlowPartitioning(faag, depths...)
+	fmt.Printf("[TIME-Partitioning] = %s\n", time.Since(start))
+
+	start = time.Now()
+	faag = sp.ComputeMultiLevelArcFlags[g.MultiLevelPartGeoPoint, g.MultiLevelFlaggedHalfEdge[int, L], int](faag, faag)
+	fmt.Printf("[TIME-ArcFlagComputation] = %s\n", time.Since(start))
+
+	testedRouter := sp.MultiLevelArcFlagRouter[g.MultiLevelPartGeoPoint, g.MultiLevelFlaggedHalfEdge[int, L], int]{Graph: faag}
+	baselineRouter := sp.DijkstraRouter[g.MultiLevelPartGeoPoint, g.MultiLevelFlaggedHalfEdge[int, L], int]{Graph: faag}
+
+	fmt.Printf("Compare %d random searches of multi-level arc flag Dijkstra against textbook Dijkstra.\n", n)
+	dijkstraPqPops, arcFlagDijkstraPqPops := 0, 0
+	for i := 0; i < n; i++ {
+		source := rand.Intn(faag.NodeCount())
+		target := rand.Intn(faag.NodeCount())
+
+		dijkstraResult := baselineRouter.Route(source, target, false)
+		arcFlagDijkstraResult := testedRouter.Route(source, target, false)
+
+		if dijkstraResult.Length != arcFlagDijkstraResult.Length {
+			fmt.Printf("[Path(source=%d, target=%d)]: Different lengths found: Dijkstra=%d, ArcFlagDijkstra=%d\n", source, target, dijkstraResult.Length, arcFlagDijkstraResult.Length)
+		}
+
+		// maintain performance indicators
+		dijkstraPqPops += dijkstraResult.PqPops
+		arcFlagDijkstraPqPops += arcFlagDijkstraResult.PqPops
+	}
+	if n > 0 {
+		fmt.Printf("Average number of Pop() operations on priority queue: %d (Dijkstra), %d (multi-level ArcFlag Dijkstra)\n", dijkstraPqPops/n, arcFlagDijkstraPqPops/n)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"math/rand"
 	"time"
 
@@ -19,7 +20,13 @@ const outputGraphFile = "out.fmi"
 func main() {
 
 	start := time.Now()
-	falg := fmi.NewAdjacencyListFromFmi(inputGraphFile, fmi.ParsePartGeoPoint, fmi.ParseFlaggedHalfEdge)
+	// read the base graph, whose partitions and arc flags are computed below
+	nodeSchema := fmi.MustSchema[g.PartGeoPoint](fmi.ID_COLUMN, "Lat", "Lon").Lenient()
+	edgeSchema := fmi.MustSchema[g.FlaggedHalfEdge[int, uint64]](fmi.FROM_COLUMN, "To_", "Weight_").Lenient()
+	falg, err := fmi.ReadFmiFile(inputGraphFile, nodeSchema, edgeSchema)
+	if err != nil {
+		log.Fatal(err)
+	}
 	faag := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](falg)
 	elapsed := time.Since(start)
 	fmt.Printf("[TIME-FileReader] = %s\n", elapsed)
@@ -27,6 +34,8 @@ func main() {
 	start = time.Now()
 	faag = partitioning.GridPartitioning(faag, 8, 8) // 64 partitions
 	//faag = partitioning.KdPartitioning(faag, 6)      // 64 partitions
+	//faag = partitioning.MultilevelPartitioning(faag, 64, 0.03, 1) // 64 partitions
+	//faag = partitioning.InertialFlowPartitioning(faag, 6) // 64 partitions
 	elapsed = time.Since(start)
 	fmt.Printf("[TIME-Partitioning] = %s\n", elapsed)
 
@@ -35,9 +44,19 @@ func main() {
 	elapsed = time.Since(start)
 	fmt.Printf("[TIME-ArcFlagComputation] = %s\n", elapsed)
 
-	fmi.WriteFmi[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](faag, outputGraphFile, fmi.PartGeoPoint2FmiLine, fmi.FlaggedHalfEdge2FmiLine)
+	// record the provenance of the arc flags: the topology and weights of the base graph are not changed by the preprocessing
+	metadata := fmi.NewMetadata(fmi.ARTIFACT_ARC_FLAGS, g.Fingerprint[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](faag))
+	metadata.Partitioner = "grid"
+	metadata.Parameters["input"] = inputGraphFile
+	metadata.Parameters["lat"], metadata.Parameters["lon"], metadata.Parameters["partitions"] = "8", "8", "64"
+	if err := fmi.WriteFmiArtifactFile[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](faag, outputGraphFile, fmi.PartGeoPointSchema, fmi.FlaggedHalfEdgeSchema, metadata); err != nil {
+		log.Fatal(err)
+	}
 
-	falg = fmi.NewAdjacencyListFromFmi(outputGraphFile, fmi.ParsePartGeoPoint, fmi.ParseFlaggedHalfEdge)
+	falg, _, err = fmi.ReadFmiArtifactFile(outputGraphFile, fmi.PartGeoPointSchema, fmi.FlaggedHalfEdgeSchema, metadata.BaseGraph)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	testedRouter := sp.ArcFlagRouter[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int]{Graph: falg}
 	baselineRouter := sp.DijkstraRouter[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int]{Graph: falg}
@@ -2,10 +2,15 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"math"
 	"os"
+	"strings"
+	"time"
 
 	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
 	"github.com/dmholtz/graffiti/examples/heuristics"
@@ -31,6 +36,16 @@ type BenchmarkTask struct {
 }
 
 func main() {
+	dimacsGraph := flag.String("dimacs-graph", "", "benchmark on a DIMACS graph file (.gr) instead of the default benchmarks")
+	dimacsCoordinates := flag.String("dimacs-coordinates", "", "DIMACS coordinate file (.co) of the DIMACS graph")
+	dimacsQueries := flag.String("dimacs-queries", "", "DIMACS query file (.p2p or .ss) of the DIMACS graph")
+	flag.Parse()
+
+	if *dimacsGraph != "" {
+		DimacsBenchmark(*dimacsGraph, *dimacsCoordinates, *dimacsQueries)
+		return
+	}
+
 	Baseline(false)
 	CompareArcFlagSize(false)
 	CompareGridType(false)
@@ -111,6 +126,9 @@ func CompareLandmarkCount(export bool) {
 	alt16 := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, landmarks[:16])
 	alt32 := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, landmarks[:32])
 	alt64 := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, landmarks[:64])
+	alt64Active8 := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, landmarks[:64])
+	alt64Active8.ActiveLandmarkCount = 8
+	alt64Active8.UpdateInterval = 256
 
 	alt2Router := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: alt2}
 	alt2Benchmark := BenchmarkTask{Name: "ALT-2", Benchmark: sp.NewBenchmarker[int](alt2Router, n), ResultFile: "benchmarks/alt-2.json"}
@@ -124,19 +142,27 @@ func CompareLandmarkCount(export bool) {
 	alt16Router := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: alt16}
 	alt16Benchmark := BenchmarkTask{Name: "ALT-16", Benchmark: sp.NewBenchmarker[int](alt16Router, n), ResultFile: "benchmarks/alt-16.json"}
 
+	biAlt16Router := sp.BidirectionalAltRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Transpose: aag, Heuristic: alt16, MaxInitializerValue: math.MaxInt}
+	biAlt16Benchmark := BenchmarkTask{Name: "Bidirectional ALT-16", Benchmark: sp.NewBenchmarker[int](biAlt16Router, n), ResultFile: "benchmarks/bi-alt-16.json"}
+
 	alt32Router := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: alt32}
 	alt32Benchmark := BenchmarkTask{Name: "ALT-32", Benchmark: sp.NewBenchmarker[int](alt32Router, n), ResultFile: "benchmarks/alt-32.json"}
 
 	alt64Router := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: alt64}
 	alt64Benchmark := BenchmarkTask{Name: "ALT-64", Benchmark: sp.NewBenchmarker[int](alt64Router, n), ResultFile: "benchmarks/alt-64.json"}
 
+	alt64Active8Router := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: alt64Active8}
+	alt64Active8Benchmark := BenchmarkTask{Name: "ALT-64 (8 active)", Benchmark: sp.NewBenchmarker[int](alt64Active8Router, n), ResultFile: "benchmarks/alt-64-active-8.json"}
+
 	RunBenchmarks([]BenchmarkTask{
 		alt2Benchmark,
 		alt4Benchmark,
 		alt8Benchmark,
 		alt16Benchmark,
+		biAlt16Benchmark,
 		alt32Benchmark,
-		alt64Benchmark},
+		alt64Benchmark,
+		alt64Active8Benchmark},
 		NUMBER_OF_RUNS,
 		export)
 }
@@ -153,11 +179,15 @@ func CompareLandmarkSelection(export bool) {
 	randomLandmarks := sp.UniformLandmarks[g.GeoPoint, g.WeightedHalfEdge[int]](aag, 8)
 	oceanLandmarks := LoadLandmarkFile("graphs/landmarks/landmarks_ocean8.json")
 	coastLandmarks := LoadLandmarkFile("graphs/landmarks/landmarks_coast8.json")
+	avoidLandmarks := sp.AvoidLandmarks[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, 8, 1)
+	maxCoverLandmarks := sp.MaxCoverLandmarks[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, 8, 1)
 
 	// Build routers
 	altRand := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, randomLandmarks)
-	altOcean := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, oceanLandmarks)
-	altCoast := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, coastLandmarks)
+	altOcean := LoadOrComputeAlt(aag, oceanLandmarks, "graphs/landmarks/landmarks_ocean8.json", "graphs/landmarks/landmarks_ocean8.alt")
+	altCoast := LoadOrComputeAlt(aag, coastLandmarks, "graphs/landmarks/landmarks_coast8.json", "graphs/landmarks/landmarks_coast8.alt")
+	altAvoid := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, avoidLandmarks)
+	altMaxCover := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, maxCoverLandmarks)
 
 	altRandRouter := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: altRand}
 	altRandBenchmark := BenchmarkTask{Name: "ALT (random landmarks)", Benchmark: sp.NewBenchmarker[int](altRandRouter, n), ResultFile: "benchmarks/alt-8-random.json"} // identical test is run in CompareLandmarkCount
@@ -168,10 +198,18 @@ func CompareLandmarkSelection(export bool) {
 	altCoastRouter := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: altCoast}
 	altCoastBenchmark := BenchmarkTask{Name: "ALT (coast landmarks)", Benchmark: sp.NewBenchmarker[int](altCoastRouter, n), ResultFile: "benchmarks/alt-8-coast.json"}
 
+	altAvoidRouter := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: altAvoid}
+	altAvoidBenchmark := BenchmarkTask{Name: "ALT (avoid landmarks)", Benchmark: sp.NewBenchmarker[int](altAvoidRouter, n), ResultFile: "benchmarks/alt-8-avoid.json"}
+
+	altMaxCoverRouter := sp.AStarRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Heuristic: altMaxCover}
+	altMaxCoverBenchmark := BenchmarkTask{Name: "ALT (maxcover landmarks)", Benchmark: sp.NewBenchmarker[int](altMaxCoverRouter, n), ResultFile: "benchmarks/alt-8-maxcover.json"}
+
 	RunBenchmarks([]BenchmarkTask{
 		altRandBenchmark,
 		altOceanBenchmark,
-		altCoastBenchmark},
+		altCoastBenchmark,
+		altAvoidBenchmark,
+		altMaxCoverBenchmark},
 		NUMBER_OF_RUNS,
 		export)
 }
@@ -179,24 +217,13 @@ func CompareLandmarkSelection(export bool) {
 func CompareArcFlagSize(export bool) {
 
 	// Load graphs
-
-	falg8 := fmi.NewAdjacencyListFromFmi(arcflag8, fmi.ParsePartGeoPoint, fmi.ParseFlaggedHalfEdge)
-	faag8 := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](falg8)
-
-	falg16 := fmi.NewAdjacencyListFromFmi(arcflag16, fmi.ParsePartGeoPoint, fmi.ParseFlaggedHalfEdge)
-	faag16 := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](falg16)
-
-	falg32 := fmi.NewAdjacencyListFromFmi(arcflag32, fmi.ParsePartGeoPoint, fmi.ParseFlaggedHalfEdge)
-	faag32 := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](falg32)
-
-	falg64 := fmi.NewAdjacencyListFromFmi(arcflag64, fmi.ParsePartGeoPoint, fmi.ParseFlaggedHalfEdge)
-	faag64 := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](falg64)
-
-	falg128 := fmi.NewAdjacencyListFromFmi(arcflag128, fmi.ParsePartGeoPoint, fmi.ParseLargeFlaggedHalfEdge)
-	faag128 := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.LargeFlaggedHalfEdge[int]](falg128)
-
-	falg256 := fmi.NewAdjacencyListFromFmi(arcflag256, fmi.ParsePartGeoPoint, fmi.Parse256BitFlaggedHalfEdge)
-	faag256 := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.B256FlaggedHalfEdge[int]](falg256)
+	baseGraph := defaultGraphFingerprint()
+	faag8 := readArcFlagArtifact(arcflag8, baseGraph, fmi.PartGeoPointSchema, fmi.FlaggedHalfEdgeSchema)
+	faag16 := readArcFlagArtifact(arcflag16, baseGraph, fmi.PartGeoPointSchema, fmi.FlaggedHalfEdgeSchema)
+	faag32 := readArcFlagArtifact(arcflag32, baseGraph, fmi.PartGeoPointSchema, fmi.FlaggedHalfEdgeSchema)
+	faag64 := readArcFlagArtifact(arcflag64, baseGraph, fmi.PartGeoPointSchema, fmi.FlaggedHalfEdgeSchema)
+	faag128 := readArcFlagArtifact(arcflag128, baseGraph, fmi.PartGeoPointSchema, fmi.LargeFlaggedHalfEdgeSchema)
+	faag256 := readArcFlagArtifact(arcflag256, baseGraph, fmi.PartGeoPointSchema, fmi.B256FlaggedHalfEdgeSchema)
 
 	n := faag8.NodeCount()
 
@@ -258,15 +285,10 @@ func CompareArcFlagSize(export bool) {
 func CompareTwoLevelArcFlagSize(export bool) {
 
 	// Load graphs
-
-	falg8 := fmi.NewAdjacencyListFromFmi(arcflag8, fmi.Parse2LPartGeoPoint, fmi.Parse2LFlaggedHalfEdge)
-	faag8 := g.NewAdjacencyArrayFromGraph[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]](falg8)
-
-	falg16 := fmi.NewAdjacencyListFromFmi(arcflag16, fmi.Parse2LPartGeoPoint, fmi.Parse2LFlaggedHalfEdge)
-	faag16 := g.NewAdjacencyArrayFromGraph[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]](falg16)
-
-	falg32 := fmi.NewAdjacencyListFromFmi(arcflag32, fmi.Parse2LPartGeoPoint, fmi.Parse2LFlaggedHalfEdge)
-	faag32 := g.NewAdjacencyArrayFromGraph[g.TwoLevelPartGeoPoint, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]](falg32)
+	baseGraph := defaultGraphFingerprint()
+	faag8 := readArcFlagArtifact(arcflag8, baseGraph, fmi.TwoLevelPartGeoPointSchema, fmi.TwoLevelFlaggedHalfEdgeSchema)
+	faag16 := readArcFlagArtifact(arcflag16, baseGraph, fmi.TwoLevelPartGeoPointSchema, fmi.TwoLevelFlaggedHalfEdgeSchema)
+	faag32 := readArcFlagArtifact(arcflag32, baseGraph, fmi.TwoLevelPartGeoPointSchema, fmi.TwoLevelFlaggedHalfEdgeSchema)
 
 	n := faag8.NodeCount()
 
@@ -292,12 +314,10 @@ func CompareTwoLevelArcFlagSize(export bool) {
 func CompareGridType(export bool) {
 
 	// Load graphs
+	baseGraph := defaultGraphFingerprint()
 
-	gridAlg64 := fmi.NewAdjacencyListFromFmi(arcflag64, fmi.ParsePartGeoPoint, fmi.ParseFlaggedHalfEdge)
-	gridAag64 := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](gridAlg64)
-
-	kdAlg64 := fmi.NewAdjacencyListFromFmi(arcflag64_kd, fmi.ParsePartGeoPoint, fmi.ParseFlaggedHalfEdge)
-	kdAag64 := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](kdAlg64)
+	gridAag64 := readArcFlagArtifact(arcflag64, baseGraph, fmi.PartGeoPointSchema, fmi.FlaggedHalfEdgeSchema)
+	kdAag64 := readArcFlagArtifact(arcflag64_kd, baseGraph, fmi.PartGeoPointSchema, fmi.FlaggedHalfEdgeSchema)
 
 	n := kdAag64.NodeCount()
 
@@ -319,8 +339,8 @@ func CompareGridType(export bool) {
 func EvaluateArcflagAlt(export bool) {
 
 	// Load graphs
-	falg256 := fmi.NewAdjacencyListFromFmi("graphs/ocean_equi_4_grid_arcflags256.fmi", fmi.ParsePartGeoPoint, fmi.Parse256BitFlaggedHalfEdge)
-	faag256 := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.B256FlaggedHalfEdge[int]](falg256)
+	baseGraph := defaultGraphFingerprint()
+	faag256 := readArcFlagArtifact(arcflag256, baseGraph, fmi.PartGeoPointSchema, fmi.B256FlaggedHalfEdgeSchema)
 
 	n := faag256.NodeCount()
 
@@ -340,6 +360,70 @@ func EvaluateArcflagAlt(export bool) {
 		export)
 }
 
+// DimacsBenchmark runs the queries of a DIMACS benchmark instance.
+// Point-to-point queries (.p2p) are answered by Dijkstra's algorithm and its bidirectional variant;
+// single-source queries (.ss) are answered by one-to-all Dijkstra searches.
+func DimacsBenchmark(grFile, coFile, queryFile string) {
+	aag, err := fmi.NewAdjacencyArrayFromDimacs(grFile, coFile, g.KEEP_MIN_WEIGHT)
+	if err != nil {
+		log.Fatal(err)
+	}
+	n := aag.NodeCount()
+	fmt.Printf("Loaded DIMACS graph with %d nodes and %d edges\n", n, aag.EdgeCount())
+
+	file, err := fmi.OpenFile(queryFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	if strings.HasSuffix(queryFile, ".ss") {
+		sources, err := fmi.ReadDimacsSources(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		result := sp.NewBenchmarkResult()
+		for _, source := range sources {
+			if source >= n {
+				log.Fatalf("Source %d refers to a node outside of the graph", source)
+			}
+			start := time.Now()
+			oneToAll := sp.DijkstraOneToAll[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, source)
+			result.Add(math.Round(float64(time.Since(start))/1000)/1000, oneToAll.PqPops)
+		}
+		fmt.Println("Run benchmark 'Dijkstra's Algorithm (one-to-all)'")
+		fmt.Println(result.Summarize())
+		return
+	}
+
+	queries, err := fmi.ReadDimacsQueries(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, query := range queries {
+		if query.Source >= n || query.Target >= n {
+			log.Fatalf("Query %v refers to a node outside of the graph", query)
+		}
+	}
+	// DIMACS graphs are not necessarily symmetric
+	builder := g.NewAdjacencyArrayBuilder[g.GeoPoint, g.WeightedHalfEdge[int], int](g.KEEP_ALL)
+	builder.AddNodes(aag.Nodes)
+	for tail := 0; tail < n; tail++ {
+		for _, edge := range aag.GetHalfEdgesFrom(tail) {
+			builder.AddEdge(edge.To(), g.WeightedHalfEdge[int]{To_: tail, Weight_: edge.Weight()})
+		}
+	}
+	transpose, _, _ := builder.Build()
+	tasks := []BenchmarkTask{
+		{Name: "Dijkstra's Algorithm", Benchmark: sp.NewBenchmarker[int](sp.DijkstraRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag}, n)},
+		{Name: "bidirectional Dijkstra's Algorithm", Benchmark: sp.NewBenchmarker[int](sp.BiDijkstraRouter[g.GeoPoint, g.WeightedHalfEdge[int], int]{Graph: aag, Transpose: transpose, MaxInitializerValue: math.MaxInt}, n)},
+	}
+	for _, task := range tasks {
+		fmt.Printf("Run benchmark '%s'\n", task.Name)
+		fmt.Println(task.Benchmark.RunQueries(queries))
+	}
+}
+
 func RunBenchmarks(tasks []BenchmarkTask, n int, export bool) {
 	for _, task := range tasks {
 		fmt.Printf("Run benchmark '%s'\n", task.Name)
@@ -363,3 +447,45 @@ func LoadLandmarkFile(filename string) []g.NodeId {
 	_ = json.Unmarshal([]byte(file), &landmarks)
 	return landmarks
 }
+
+// LoadOrComputeAlt loads the landmark distances from altFile if the file matches the graph and the landmarks.
+// Otherwise, the landmark distances are computed and saved to altFile for subsequent runs.
+func LoadOrComputeAlt(aag *g.AdjacencyArrayGraph[g.GeoPoint, g.WeightedHalfEdge[int]], landmarks []g.NodeId, landmarkFile string, altFile string) *sp.AltHeuristic[int] {
+	ah, _, err := fmi.ReadAltHeuristicFile[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, altFile)
+	if err == nil && len(ah.LandmarkDistancesCollection) == len(landmarks) {
+		complete := true
+		for _, landmark := range landmarks {
+			_, ok := ah.LandmarkDistancesCollection[landmark]
+			complete = complete && ok
+		}
+		if complete {
+			return ah
+		}
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("Recomputing landmark distances: %v", err)
+	}
+
+	ah = sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, aag, landmarks)
+	metadata := fmi.NewMetadata(fmi.ARTIFACT_LANDMARKS, 0)
+	metadata.Parameters["landmarks"] = landmarkFile
+	if err := fmi.WriteAltHeuristicFile[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, ah, metadata, altFile); err != nil {
+		log.Printf("Cannot save landmark distances: %v", err)
+	}
+	return ah
+}
+
+// defaultGraphFingerprint returns the fingerprint of the default graph, which is the base graph of all arc flag artifacts.
+func defaultGraphFingerprint() uint64 {
+	alg := fmi.NewAdjacencyListFromFmi(defaultGraph, fmi.ParseGeoPoint, fmi.ParseWeightedHalfEdge)
+	return g.Fingerprint[g.GeoPoint, g.WeightedHalfEdge[int], int](alg)
+}
+
+// readArcFlagArtifact reads an arc flag graph written by WriteFmiArtifactFile and terminates the program
+// if the file is invalid or the arc flags have not been computed for the base graph.
+func readArcFlagArtifact[N any, E g.IHalfEdge](filename string, baseGraph uint64, nodeSchema *fmi.Schema[N], edgeSchema *fmi.Schema[E]) *g.AdjacencyArrayGraph[N, E] {
+	alg, _, err := fmi.ReadFmiArtifactFile(filename, nodeSchema, edgeSchema, baseGraph)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return g.NewAdjacencyArrayFromGraph[N, E](alg)
+}
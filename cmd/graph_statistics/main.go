@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/dmholtz/graffiti/algorithms/statistics"
+	"github.com/dmholtz/graffiti/examples/heuristics"
+	fmi "github.com/dmholtz/graffiti/examples/io"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+const defaultGraph = "graphs/ocean_equi_4.fmi"
+
+// graph_statistics prints a statistics and health report of a graph of GeoPoints in the .fmi format.
+// Additional node and edge columns (e.g. partitions and arc flags) are ignored.
+func main() {
+	graphFile := flag.String("graph", defaultGraph, "path to the .fmi file")
+	bins := flag.Int("bins", 10, "number of bins of the weight histogram")
+	flag.Parse()
+
+	start := time.Now()
+	aag, err := readGraph(*graphFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("[TIME-FileReader] = %s\n", time.Since(start))
+
+	start = time.Now()
+	report := statistics.Analyze[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, *bins)
+	geoReport := statistics.AnalyzeGeo[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, heuristics.Haversine)
+	fmt.Printf("[TIME-Analysis] = %s\n", time.Since(start))
+
+	fmt.Print(report)
+	fmt.Print(geoReport)
+}
+
+// readGraph reads an .fmi file into an adjacency array, which keeps parallel edges, such that they are covered by the report.
+// Unlike ReadFmiFile, which inserts the edges into an adjacency list, no edge of the file is dropped.
+func readGraph(filename string) (*g.AdjacencyArrayGraph[g.GeoPoint, g.WeightedHalfEdge[int]], error) {
+	file, err := fmi.OpenFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := fmi.NewFmiReader(file, fmi.GeoPointSchema.Lenient(), fmi.WeightedHalfEdgeSchema.Lenient())
+	if err := reader.ReadHeader(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	builder := g.NewAdjacencyArrayBuilder[g.GeoPoint, g.WeightedHalfEdge[int], int](g.KEEP_ALL)
+	for {
+		_, node, err := reader.ReadNode()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		builder.AddNode(node)
+	}
+	for {
+		from, edge, err := reader.ReadEdge()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		builder.AddEdge(from, edge)
+	}
+	aag, _, err := builder.Build()
+	return aag, err
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dmholtz/graffiti/algorithms/statistics"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestReadGraphKeepsParallelEdges(t *testing.T) {
+	t.Parallel()
+
+	// the edge from node 0 to node 1 is contained twice, additional columns are ignored
+	filename := filepath.Join(t.TempDir(), "graph.fmi")
+	content := "3\n4\n0 47.5 11.25 7\n1 -20 170 7\n2 0 0 7\n0 1 10\n0 1 12\n1 2 42\n2 0 5\n"
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	aag, err := readGraph(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aag.NodeCount() != 3 || aag.EdgeCount() != 4 {
+		t.Fatalf("Expected 3 nodes and 4 edges, got %d nodes and %d edges", aag.NodeCount(), aag.EdgeCount())
+	}
+	report := statistics.Analyze[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, 10)
+	if report.ParallelEdges != 1 {
+		t.Errorf("Expected 1 parallel edge, got %d", report.ParallelEdges)
+	}
+}
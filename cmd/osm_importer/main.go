@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	fmi "github.com/dmholtz/graffiti/examples/io"
+	"github.com/dmholtz/graffiti/examples/osm"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// osm_importer builds a road and/or ferry network from an OpenStreetMap extract (.osm.pbf or .osm)
+// and writes it in the .fmi format. The OSM ID of each node is written to a separate file, one ID per line in the order of the node IDs.
+func main() {
+	input := flag.String("input", "", "path to the .osm.pbf or .osm file")
+	output := flag.String("output", "out.fmi", "path to the .fmi output file")
+	ids := flag.String("ids", "out.osmids", "path to the OSM ID mapping output file")
+	networks := flag.String("networks", "road,ferry", "comma-separated list of networks to import (road, ferry)")
+	ignoreOneway := flag.Bool("ignore-oneway", false, "import all ways in both directions")
+	flag.Parse()
+
+	if *input == "" {
+		log.Fatal("Missing input file, use -input <file>")
+	}
+
+	filters := make([]osm.Filter, 0)
+	for _, network := range strings.Split(*networks, ",") {
+		var filter osm.Filter
+		switch strings.TrimSpace(network) {
+		case "road":
+			filter = osm.RoadFilter()
+		case "ferry":
+			filter = osm.FerryFilter()
+		default:
+			log.Fatalf("Unknown network '%s'", network)
+		}
+		filter.IgnoreOneway = *ignoreOneway
+		filters = append(filters, filter)
+	}
+
+	start := time.Now()
+	network, err := osm.Import(*input, filters...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("[TIME-Import] = %s\n", time.Since(start))
+	fmt.Printf("Imported %d nodes and %d edges\n", network.Graph.NodeCount(), network.Graph.EdgeCount())
+
+	fmi.WriteFmi[g.GeoPoint, g.WeightedHalfEdge[int]](network.Graph, *output, fmi.GeoPoint2FmiLine, fmi.WeightedHalfEdge2FmiLine)
+
+	file, err := os.Create(*ids)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	writer := bufio.NewWriter(file)
+	for _, osmId := range network.OsmIds {
+		fmt.Fprintf(writer, "%d\n", osmId)
+	}
+	if err := writer.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
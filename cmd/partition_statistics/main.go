@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dmholtz/graffiti/algorithms/statistics"
+	fmi "github.com/dmholtz/graffiti/examples/io"
+	"github.com/dmholtz/graffiti/examples/partitioning"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+const defaultGraph = "graphs/ocean_equi_4.fmi"
+
+type geoGraph = g.AdjacencyArrayGraph[g.GeoPoint, g.WeightedHalfEdge[int]]
+
+// partition_statistics compares the partitionings of a graph of GeoPoints in the .fmi format with respect to
+// the quality measures that drive the arc flag preprocessing, i.e. cut edges, boundary nodes, balance and connectivity.
+// Additional node and edge columns (e.g. partitions and arc flags) are ignored.
+func main() {
+	graphFile := flag.String("graph", defaultGraph, "path to the .fmi file")
+	verbose := flag.Bool("v", false, "print the report of each region")
+	flag.Parse()
+
+	start := time.Now()
+	alg, err := fmi.ReadFmiFile(*graphFile, fmi.GeoPointSchema.Lenient(), fmi.WeightedHalfEdgeSchema.Lenient())
+	if err != nil {
+		log.Fatal(err)
+	}
+	aag := g.NewAdjacencyArrayFromGraph[g.GeoPoint, g.WeightedHalfEdge[int]](alg)
+	fmt.Printf("[TIME-FileReader] = %s\n", time.Since(start))
+
+	// 64 partitions
+	partitioners := []struct {
+		name      string
+		partition func(*geoGraph) g.PartitionAssignment
+	}{
+		{"Grid 8x8", func(gg *geoGraph) g.PartitionAssignment {
+			return partitioning.GridAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](gg, 8, 8)
+		}},
+		{"Kd depth 6", func(gg *geoGraph) g.PartitionAssignment {
+			return partitioning.KdAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](gg, 6)
+		}},
+		{"Multilevel k=64", func(gg *geoGraph) g.PartitionAssignment {
+			return partitioning.MultilevelAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](gg, 64, 0.03, 1)
+		}},
+		{"Natural cut k=64", func(gg *geoGraph) g.PartitionAssignment {
+			return partitioning.NaturalCutAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](gg, 64, 0.03, 1)
+		}},
+		{"Inertial flow depth 6", func(gg *geoGraph) g.PartitionAssignment {
+			return partitioning.InertialFlowAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](gg, 6)
+		}},
+	}
+	reports := make([]statistics.PartitionReport, len(partitioners))
+	for i, partitioner := range partitioners {
+		start = time.Now()
+		pg := &g.PartitionedGraph[g.GeoPoint, g.WeightedHalfEdge[int]]{Graph: aag, Assignment: partitioner.partition(aag)}
+		fmt.Printf("[TIME-Partitioning] %s = %s\n", partitioner.name, time.Since(start))
+		reports[i] = statistics.AnalyzePartition[g.PartitionedNode[g.GeoPoint], g.WeightedHalfEdge[int]](pg)
+		if *verbose {
+			fmt.Printf("%s:\n%s", partitioner.name, reports[i])
+		}
+	}
+
+	// 32x32 partitions
+	twoLevelPartitioners := []struct {
+		name      string
+		partition func(*geoGraph) g.TwoLevelPartitionAssignment
+	}{
+		{"Two-level grid 4x8 / 4x8", func(gg *geoGraph) g.TwoLevelPartitionAssignment {
+			return partitioning.TwoLevelGridAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](gg, 4, 8, 4, 8)
+		}},
+		{"Two-level inertial flow depth 5 / 5", func(gg *geoGraph) g.TwoLevelPartitionAssignment {
+			return partitioning.TwoLevelInertialFlowAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](gg, 5, 5)
+		}},
+	}
+	twoLevelReports := make([]statistics.TwoLevelPartitionReport, len(twoLevelPartitioners))
+	for i, partitioner := range twoLevelPartitioners {
+		start = time.Now()
+		pg := &g.TwoLevelPartitionedGraph[g.GeoPoint, g.WeightedHalfEdge[int]]{Graph: aag, Assignment: partitioner.partition(aag)}
+		fmt.Printf("[TIME-Partitioning] %s = %s\n", partitioner.name, time.Since(start))
+		twoLevelReports[i] = statistics.AnalyzeTwoLevelPartition[g.TwoLevelPartitionedNode[g.GeoPoint], g.WeightedHalfEdge[int]](pg)
+		if *verbose {
+			fmt.Printf("%s:\n%s", partitioner.name, twoLevelReports[i])
+		}
+	}
+
+	fmt.Printf("%-36s %10s %10s %10s %10s %12s\n", "Partitioning", "Cut edges", "Boundary", "Imbalance", "Disconn.", "Est. cost")
+	for i, report := range reports {
+		fmt.Printf("%-36s %10d %10d %10.3f %10d %12.3g\n", partitioners[i].name, report.CutEdges, report.BoundaryNodes, report.Imbalance, report.DisconnectedRegions, report.EstimatedCost)
+	}
+	for i, report := range twoLevelReports {
+		fmt.Printf("%-36s %10d %10d %10.3f %10d %12.3g\n", twoLevelPartitioners[i].name, report.L2.CutEdges, report.L2.BoundaryNodes, report.L2.Imbalance, report.L2.DisconnectedRegions, report.EstimatedCost)
+	}
+}
@@ -0,0 +1,280 @@
+package io
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc64"
+	"io"
+	"math"
+	"sort"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Binary ALT format
+//
+// The landmark distances of an AltHeuristic are stored as follows (all integers little-endian):
+//
+//	header     fixed-size description of the landmark data (see altHeader)
+//	metadata   length (32-bit integer) and JSON encoding of the Metadata
+//	landmarks  node IDs of the landmarks in ascending order (64-bit integers)
+//	distances  for each landmark: From and To distance arrays of NodeCount values each
+//	checksum   CRC-64 (ECMA) of all preceding bytes
+//
+// Integer distances are zigzag-encoded varints, which need 3 to 4 bytes for typical road and ocean graphs, whereas
+// floating point distances are stored as 64-bit IEEE 754 values. The header contains the fingerprint of the graph
+// (see graph.Fingerprint), such that landmark distances of a modified graph are rejected.
+
+// magic bytes at the beginning of every ALT file
+var altMagic = [8]byte{'G', 'R', 'A', 'F', 'F', 'A', 'L', 'T'}
+
+// current version of the ALT format
+const altVersion = 1
+
+// weight types of the distance arrays
+const (
+	ALT_INT_WEIGHTS   = 1
+	ALT_FLOAT_WEIGHTS = 2
+)
+
+// ErrStaleLandmarks is returned if the landmark distances have been computed for a different graph.
+// It wraps ErrIncompatibleArtifact.
+var ErrStaleLandmarks = fmt.Errorf("alt: landmark distances: %w", ErrIncompatibleArtifact)
+
+// altHeader is the fixed-size header of an ALT file.
+type altHeader struct {
+	Magic         [8]byte
+	Version       uint32
+	WeightType    uint32
+	NodeCount     uint64
+	LandmarkCount uint64
+	Fingerprint   uint64 // graph.Fingerprint of the graph
+}
+
+// WriteAltHeuristicFile writes the landmark distances of an ALT heuristic for the given graph into a file.
+// The metadata describes the landmark selection; its base graph is set to the fingerprint of the graph.
+func WriteAltHeuristicFile[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], ah *sp.AltHeuristic[W], metadata Metadata, filename string) error {
+	metadata.BaseGraph = g.Fingerprint[N, E, W](graph)
+	return writeFile(filename, func(w io.Writer) error {
+		return EncodeAltHeuristic(ah, graph.NodeCount(), metadata, w)
+	})
+}
+
+// EncodeAltHeuristic writes the landmark distances of an ALT heuristic in the binary ALT format to w.
+// nodeCount and the base graph of the metadata describe the graph the distances have been computed for.
+func EncodeAltHeuristic[W g.Weight](ah *sp.AltHeuristic[W], nodeCount int, metadata Metadata, w io.Writer) error {
+	landmarks := make([]int, 0, len(ah.LandmarkDistancesCollection))
+	for landmark, distances := range ah.LandmarkDistancesCollection {
+		if len(distances.From) != nodeCount || len(distances.To) != nodeCount {
+			return fmt.Errorf("alt: distance arrays of landmark %d do not match the node count %d", landmark, nodeCount)
+		}
+		landmarks = append(landmarks, landmark)
+	}
+	sort.Ints(landmarks)
+	metadata.Artifact = ARTIFACT_LANDMARKS
+	encodedMetadata := []byte(metadata.String())
+
+	checksum := crc64.New(crc64Table)
+	writer := bufio.NewWriter(io.MultiWriter(w, checksum))
+	header := altHeader{
+		Magic:         altMagic,
+		Version:       altVersion,
+		WeightType:    altWeightType[W](),
+		NodeCount:     uint64(nodeCount),
+		LandmarkCount: uint64(len(landmarks)),
+		Fingerprint:   metadata.BaseGraph,
+	}
+	binary.Write(writer, binary.LittleEndian, header)
+	binary.Write(writer, binary.LittleEndian, uint32(len(encodedMetadata)))
+	writer.Write(encodedMetadata)
+	buffer := make([]byte, binary.MaxVarintLen64)
+	for _, landmark := range landmarks {
+		binary.LittleEndian.PutUint64(buffer, uint64(landmark))
+		writer.Write(buffer[:8])
+	}
+	for _, landmark := range landmarks {
+		distances := ah.LandmarkDistancesCollection[landmark]
+		for _, array := range [][]W{distances.From, distances.To} {
+			for _, distance := range array {
+				writer.Write(appendDistance(buffer[:0], distance))
+			}
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint64(buffer, checksum.Sum64())
+	_, err := w.Write(buffer[:8])
+	return err
+}
+
+// ReadAltHeuristicFile reads the landmark distances of an ALT heuristic for the given graph from a file.
+// ErrStaleLandmarks is returned if the file has been written for a different graph.
+func ReadAltHeuristicFile[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], filename string) (*sp.AltHeuristic[W], *Metadata, error) {
+	file, err := OpenFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	return DecodeAltHeuristic[W](file, graph.NodeCount(), g.Fingerprint[N, E, W](graph))
+}
+
+// DecodeAltHeuristic reads landmark distances in the binary ALT format and creates an ALT heuristic with all landmarks active.
+// ErrStaleLandmarks is returned if nodeCount or fingerprint do not match the graph the distances have been computed for.
+func DecodeAltHeuristic[W g.Weight](r io.Reader, nodeCount int, fingerprint uint64) (*sp.AltHeuristic[W], *Metadata, error) {
+	reader := &checksumReader{reader: bufio.NewReader(r)}
+
+	var header altHeader
+	if err := binary.Read(reader, binary.LittleEndian, &header); err != nil {
+		return nil, nil, fmt.Errorf("alt: %w", err)
+	}
+	if header.Magic != altMagic {
+		return nil, nil, errors.New("alt: not an ALT file")
+	}
+	if header.Version != altVersion {
+		return nil, nil, fmt.Errorf("alt: unsupported version %d", header.Version)
+	}
+	if header.WeightType != altWeightType[W]() {
+		return nil, nil, fmt.Errorf("alt: weight type %d does not match the requested weight type %d", header.WeightType, altWeightType[W]())
+	}
+	if header.NodeCount != uint64(nodeCount) || header.Fingerprint != fingerprint {
+		return nil, nil, ErrStaleLandmarks
+	}
+	var length uint32
+	if err := binary.Read(reader, binary.LittleEndian, &length); err != nil {
+		return nil, nil, fmt.Errorf("alt: %w", err)
+	}
+	if length > 1<<20 {
+		return nil, nil, fmt.Errorf("alt: invalid metadata length %d", length)
+	}
+	encodedMetadata := make([]byte, length)
+	if _, err := io.ReadFull(reader, encodedMetadata); err != nil {
+		return nil, nil, fmt.Errorf("alt: %w", err)
+	}
+	metadata := &Metadata{}
+	if err := json.Unmarshal(encodedMetadata, metadata); err != nil {
+		return nil, nil, fmt.Errorf("alt: invalid metadata: %w", err)
+	}
+
+	if header.LandmarkCount > header.NodeCount {
+		return nil, nil, fmt.Errorf("alt: invalid landmark count %d", header.LandmarkCount)
+	}
+	landmarks := make([]int, header.LandmarkCount)
+	buffer := make([]byte, 8)
+	for i := range landmarks {
+		if _, err := io.ReadFull(reader, buffer); err != nil {
+			return nil, nil, fmt.Errorf("alt: %w", err)
+		}
+		landmarks[i] = int(binary.LittleEndian.Uint64(buffer))
+		if landmarks[i] < 0 || landmarks[i] >= nodeCount {
+			return nil, nil, fmt.Errorf("alt: landmark %d out of range [0, %d)", landmarks[i], nodeCount)
+		}
+	}
+	collection := make(map[g.NodeId]sp.LandmarkDistances[W], len(landmarks))
+	for _, landmark := range landmarks {
+		distances := sp.LandmarkDistances[W]{Landmark: landmark, From: make([]W, nodeCount), To: make([]W, nodeCount)}
+		for _, array := range [][]W{distances.From, distances.To} {
+			for i := range array {
+				distance, err := readDistance[W](reader)
+				if err != nil {
+					return nil, nil, fmt.Errorf("alt: %w", err)
+				}
+				array[i] = distance
+			}
+		}
+		collection[landmark] = distances
+	}
+
+	checksum := reader.Sum64()
+	if _, err := io.ReadFull(reader.reader, buffer); err != nil {
+		return nil, nil, fmt.Errorf("alt: %w", err)
+	}
+	if binary.LittleEndian.Uint64(buffer) != checksum {
+		return nil, nil, errors.New("alt: checksum mismatch")
+	}
+	return sp.NewAltHeuristicFromDistances(collection), metadata, nil
+}
+
+func altWeightType[W g.Weight]() uint32 {
+	var w W
+	if _, ok := any(w).(float64); ok {
+		return ALT_FLOAT_WEIGHTS
+	}
+	return ALT_INT_WEIGHTS
+}
+
+// appendDistance appends the encoding of a distance to b.
+func appendDistance[W g.Weight](b []byte, distance W) []byte {
+	var buffer [binary.MaxVarintLen64]byte
+	switch d := any(distance).(type) {
+	case float64:
+		binary.LittleEndian.PutUint64(buffer[:], math.Float64bits(d))
+		return append(b, buffer[:8]...)
+	case int:
+		return append(b, buffer[:binary.PutVarint(buffer[:], int64(d))]...)
+	}
+	return b
+}
+
+// readDistance reads a distance encoded by appendDistance.
+func readDistance[W g.Weight](r *checksumReader) (W, error) {
+	var distance W
+	switch d := any(&distance).(type) {
+	case *float64:
+		var buffer [8]byte
+		if _, err := io.ReadFull(r, buffer[:]); err != nil {
+			return distance, err
+		}
+		*d = math.Float64frombits(binary.LittleEndian.Uint64(buffer[:]))
+	case *int:
+		value, err := binary.ReadVarint(r)
+		if err != nil {
+			return distance, err
+		}
+		*d = int(value)
+	}
+	return distance, nil
+}
+
+// checksumReader computes the CRC-64 of the bytes that have been read.
+type checksumReader struct {
+	reader   *bufio.Reader
+	pending  []byte // bytes that have been read but not yet added to the checksum
+	checksum uint64
+}
+
+func (cr *checksumReader) Read(p []byte) (int, error) {
+	n, err := cr.reader.Read(p)
+	cr.update(p[:n])
+	return n, err
+}
+
+func (cr *checksumReader) ReadByte() (byte, error) {
+	b, err := cr.reader.ReadByte()
+	if err == nil {
+		// single bytes are collected to avoid updating the checksum byte by byte
+		cr.pending = append(cr.pending, b)
+		if len(cr.pending) >= 4096 {
+			cr.update(nil)
+		}
+	}
+	return b, err
+}
+
+func (cr *checksumReader) update(p []byte) {
+	if len(cr.pending) > 0 {
+		cr.checksum = crc64.Update(cr.checksum, crc64Table, cr.pending)
+		cr.pending = cr.pending[:0]
+	}
+	cr.checksum = crc64.Update(cr.checksum, crc64Table, p)
+}
+
+// Sum64 returns the CRC-64 of all bytes read so far.
+func (cr *checksumReader) Sum64() uint64 {
+	cr.update(nil)
+	return cr.checksum
+}
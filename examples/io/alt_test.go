@@ -0,0 +1,74 @@
+package io
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestAltRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	alg, err := ReadFmi(strings.NewReader(fmiGraph), GeoPointSchema, WeightedHalfEdgeSchema.Lenient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ah := sp.NewAltHeurisitc[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, alg, []g.NodeId{0, 2})
+	filename := filepath.Join(t.TempDir(), "landmarks.alt")
+	metadata := NewMetadata("", 0)
+	metadata.Parameters["selection"] = "manual"
+	if err := WriteAltHeuristicFile[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, ah, metadata, filename); err != nil {
+		t.Fatal(err)
+	}
+	read, readMetadata, err := ReadAltHeuristicFile[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readMetadata.Artifact != ARTIFACT_LANDMARKS || readMetadata.Parameters["selection"] != "manual" || readMetadata.CheckBaseGraph(g.Fingerprint[g.GeoPoint, g.WeightedHalfEdge[int], int](alg)) != nil {
+		t.Errorf("Unexpected metadata %v", readMetadata)
+	}
+	if !reflect.DeepEqual(read.LandmarkDistancesCollection, ah.LandmarkDistancesCollection) || len(read.ActiveLandmarks) != 2 {
+		t.Errorf("Expected landmark distances %v, got %v", ah.LandmarkDistancesCollection, read.LandmarkDistancesCollection)
+	}
+
+	// landmark distances of a modified graph are stale
+	alg.Edges[1][1].Weight_++
+	if _, _, err := ReadAltHeuristicFile[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, filename); !errors.Is(err, ErrIncompatibleArtifact) {
+		t.Errorf("Expected stale landmarks, got %v", err)
+	}
+}
+
+func TestAltFloatWeights(t *testing.T) {
+	t.Parallel()
+
+	ah := sp.NewAltHeuristicFromDistances(map[g.NodeId]sp.LandmarkDistances[float64]{
+		1: {Landmark: 1, From: []float64{0.5, 0, -1}, To: []float64{2.25, 0, 1e300}},
+	})
+	var buffer bytes.Buffer
+	if err := EncodeAltHeuristic(ah, 3, Metadata{BaseGraph: 42}, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	data := buffer.Bytes()
+	read, _, err := DecodeAltHeuristic[float64](bytes.NewReader(data), 3, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.LandmarkDistancesCollection, ah.LandmarkDistancesCollection) {
+		t.Errorf("Expected landmark distances %v, got %v", ah.LandmarkDistancesCollection, read.LandmarkDistancesCollection)
+	}
+
+	if _, _, err := DecodeAltHeuristic[int](bytes.NewReader(data), 3, 42); err == nil || errors.Is(err, ErrStaleLandmarks) {
+		t.Errorf("Expected weight type mismatch, got %v", err)
+	}
+	corrupted := append([]byte{}, data...)
+	corrupted[len(corrupted)-12] ^= 1
+	if _, _, err := DecodeAltHeuristic[float64](bytes.NewReader(corrupted), 3, 42); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Expected checksum mismatch, got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package io
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Attribute maps one or more fields of a node or edge type to a named attribute of a self-describing format such as CSV or GraphML.
+type Attribute struct {
+	Name   string
+	Fields []string // names of the fields, see Schema
+	// Bitset encodes unsigned integer fields as a string of '0' and '1', in which the i-th character corresponds to the i-th bit.
+	// The bits of multiple fields (or array elements) are concatenated, e.g. LsbFlag and MsbFlag of a LargeFlaggedHalfEdge.
+	Bitset bool
+}
+
+// AttributeMap maps the fields of a node or edge type to named attributes.
+// Node IDs as well as the tail and the head of an edge are structural elements of the formats and therefore not part of the map.
+type AttributeMap[T any] struct {
+	Attributes []Attribute
+
+	schema *Schema[T]
+	spans  [][]schemaColumn // schema columns of each attribute
+}
+
+// NewAttributeMap creates an attribute map. An error is returned iff an attribute refers to unsupported fields.
+func NewAttributeMap[T any](attributes ...Attribute) (*AttributeMap[T], error) {
+	columns := make([]string, 0, len(attributes))
+	for _, attribute := range attributes {
+		if len(attribute.Fields) == 0 {
+			return nil, fmt.Errorf("attribute '%s' without fields", attribute.Name)
+		}
+		columns = append(columns, attribute.Fields...)
+	}
+	schema, err := NewSchema[T](columns...)
+	if err != nil {
+		return nil, err
+	}
+	if schema.HasId() {
+		return nil, errors.New("attributes must not contain the ID column")
+	}
+
+	m := AttributeMap[T]{Attributes: attributes, schema: schema, spans: make([][]schemaColumn, len(attributes))}
+	spec := 0
+	for i, attribute := range attributes {
+		first, last := spec, spec+len(attribute.Fields)
+		for _, column := range schema.columns {
+			if column.spec >= first && column.spec < last {
+				m.spans[i] = append(m.spans[i], column)
+			}
+		}
+		spec = last
+
+		if attribute.Bitset {
+			for _, column := range m.spans[i] {
+				if !isUnsignedKind(column.kind) {
+					return nil, fmt.Errorf("bitset attribute '%s' requires unsigned integer fields", attribute.Name)
+				}
+			}
+		} else if len(m.spans[i]) != 1 {
+			return nil, fmt.Errorf("attribute '%s' spans %d values, only bitset attributes may span multiple values", attribute.Name, len(m.spans[i]))
+		}
+	}
+	return &m, nil
+}
+
+// MustAttributeMap is like NewAttributeMap but panics if the attributes are invalid.
+func MustAttributeMap[T any](attributes ...Attribute) *AttributeMap[T] {
+	m, err := NewAttributeMap[T](attributes...)
+	if err != nil {
+		panic(err.Error() + "\n")
+	}
+	return m
+}
+
+// Names returns the names of the attributes.
+func (m *AttributeMap[T]) Names() []string {
+	names := make([]string, len(m.Attributes))
+	for i, attribute := range m.Attributes {
+		names[i] = attribute.Name
+	}
+	return names
+}
+
+// Values returns the formatted value of each attribute.
+func (m *AttributeMap[T]) Values(t T) []string {
+	fields := m.schema.formatFields(-1, t)
+	values := make([]string, len(m.Attributes))
+	position := 0
+	for i, attribute := range m.Attributes {
+		span := m.spans[i]
+		if !attribute.Bitset {
+			values[i] = fields[position]
+			position++
+			continue
+		}
+		var sb strings.Builder
+		for _, column := range span {
+			value, _ := strconv.ParseUint(fields[position], 10, 64)
+			for bit := 0; bit < column.bits; bit++ {
+				sb.WriteByte('0' + byte(value>>bit&1))
+			}
+			position++
+		}
+		values[i] = sb.String()
+	}
+	return values
+}
+
+// Parse parses the formatted value of each attribute. Bitsets may be shorter than the fields; missing bits are 0.
+func (m *AttributeMap[T]) Parse(values []string) (T, error) {
+	var t T
+	if len(values) != len(m.Attributes) {
+		return t, fmt.Errorf("expected %d attributes, got %d", len(m.Attributes), len(values))
+	}
+	fields := make([]string, 0, m.schema.Width())
+	for i, attribute := range m.Attributes {
+		if !attribute.Bitset {
+			fields = append(fields, values[i])
+			continue
+		}
+		bits := values[i]
+		for _, column := range m.spans[i] {
+			var value uint64
+			for bit := 0; bit < column.bits && len(bits) > 0; bit++ {
+				switch bits[0] {
+				case '1':
+					value |= 1 << bit
+				case '0':
+				default:
+					return t, fmt.Errorf("attribute '%s': invalid bitset '%s'", attribute.Name, values[i])
+				}
+				bits = bits[1:]
+			}
+			fields = append(fields, strconv.FormatUint(value, 10))
+		}
+		if len(bits) > 0 {
+			return t, fmt.Errorf("attribute '%s': bitset '%s' exceeds the size of the fields", attribute.Name, values[i])
+		}
+	}
+	_, t, err := m.schema.Parse(fields)
+	return t, err
+}
+
+// graphMLTypes returns the GraphML type of each attribute.
+// Unsigned 64-bit integers and bitsets are strings, since GraphML's 'long' is a signed 64-bit integer.
+func (m *AttributeMap[T]) graphMLTypes() []string {
+	types := make([]string, len(m.Attributes))
+	for i, attribute := range m.Attributes {
+		column := m.spans[i][0]
+		switch {
+		case attribute.Bitset || column.kind == reflect.String:
+			types[i] = "string"
+		case column.kind == reflect.Bool:
+			types[i] = "boolean"
+		case column.kind == reflect.Float32 || column.kind == reflect.Float64:
+			types[i] = "double"
+		case isUnsignedKind(column.kind) && column.bits == 64:
+			types[i] = "string"
+		default:
+			types[i] = "long"
+		}
+	}
+	return types
+}
+
+func isUnsignedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// Attribute maps of graffiti's node and edge types, named according to common conventions of networkx and Gephi
+var (
+	GeoPointAttributes = MustAttributeMap[g.GeoPoint](
+		Attribute{Name: "lat", Fields: []string{"Lat"}}, Attribute{Name: "lon", Fields: []string{"Lon"}})
+	PartGeoPointAttributes = MustAttributeMap[g.PartGeoPoint](
+		Attribute{Name: "lat", Fields: []string{"Lat"}}, Attribute{Name: "lon", Fields: []string{"Lon"}},
+		Attribute{Name: "partition", Fields: []string{"Partition_"}})
+	TwoLevelPartGeoPointAttributes = MustAttributeMap[g.TwoLevelPartGeoPoint](
+		Attribute{Name: "lat", Fields: []string{"Lat"}}, Attribute{Name: "lon", Fields: []string{"Lon"}},
+		Attribute{Name: "l1_partition", Fields: []string{"L1Part_"}}, Attribute{Name: "l2_partition", Fields: []string{"L2Part_"}})
+	WeightedHalfEdgeAttributes = MustAttributeMap[g.WeightedHalfEdge[int]](
+		Attribute{Name: "weight", Fields: []string{"Weight_"}})
+	FlaggedHalfEdgeAttributes = MustAttributeMap[g.FlaggedHalfEdge[int, uint64]](
+		Attribute{Name: "weight", Fields: []string{"Weight_"}}, Attribute{Name: "arc_flags", Fields: []string{"Flag"}, Bitset: true})
+	LargeFlaggedHalfEdgeAttributes = MustAttributeMap[g.LargeFlaggedHalfEdge[int]](
+		Attribute{Name: "weight", Fields: []string{"Weight_"}}, Attribute{Name: "arc_flags", Fields: []string{"LsbFlag", "MsbFlag"}, Bitset: true})
+	B256FlaggedHalfEdgeAttributes = MustAttributeMap[g.B256FlaggedHalfEdge[int]](
+		Attribute{Name: "weight", Fields: []string{"Weight_"}}, Attribute{Name: "arc_flags", Fields: []string{"Flag_"}, Bitset: true})
+	TwoLevelFlaggedHalfEdgeAttributes = MustAttributeMap[g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]](
+		Attribute{Name: "weight", Fields: []string{"Weight_"}},
+		Attribute{Name: "l1_arc_flags", Fields: []string{"L1Flag"}, Bitset: true}, Attribute{Name: "l2_arc_flags", Fields: []string{"L2Flag"}, Bitset: true})
+)
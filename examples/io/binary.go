@@ -0,0 +1,390 @@
+package io
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Binary graph format
+//
+// The binary format stores an AdjacencyArrayGraph such that its slices can be used directly from a (memory-mapped) file:
+//
+//	header   fixed-size, little-endian description of the graph (see binaryHeader)
+//	nodes    raw memory of the Nodes slice
+//	offsets  raw memory of the Offsets slice (64-bit integers)
+//	edges    raw memory of the Edges slice
+//
+// Every section starts at a multiple of binaryAlignment bytes. Node and edge types are identified by a hash of their memory layout,
+// such that a file can only be loaded with the types it has been written with.
+// Each section is protected by a CRC-64 checksum and the header by a CRC-32 checksum.
+//
+// The raw memory layout restricts node and edge types to plain data, i.e. types without pointers, slices, strings, maps or interfaces.
+// Moreover, files are only portable between little-endian platforms with 64-bit integers.
+
+// magic bytes at the beginning of every binary graph file
+var binaryMagic = [8]byte{'G', 'R', 'A', 'F', 'F', 'I', 'T', 'I'}
+
+// current version of the binary graph format
+const binaryVersion = 1
+
+// byte order mark of the arrays: the format currently supports little-endian arrays only
+const binaryByteOrderMark = 0x01020304
+
+// alignment of each section in bytes
+const binaryAlignment = 64
+
+// section indices
+const (
+	nodeSection   = iota
+	offsetSection = iota
+	edgeSection   = iota
+)
+
+// binarySection locates a section within the file.
+type binarySection struct {
+	Offset   uint64 // position of the section's first byte in the file
+	Length   uint64 // length of the section in bytes
+	Checksum uint64 // CRC-64 (ECMA) of the section
+}
+
+// binaryHeader is the fixed-size header of a binary graph file.
+type binaryHeader struct {
+	Magic     [8]byte
+	Version   uint32
+	ByteOrder uint32
+	NodeCount uint64
+	EdgeCount uint64
+	NodeSize  uint64 // size of a single node in bytes
+	EdgeSize  uint64 // size of a single edge in bytes
+	NodeType  uint64 // hash of the node type's memory layout
+	EdgeType  uint64 // hash of the edge type's memory layout
+	Sections  [3]binarySection
+	Checksum  uint32 // CRC-32 (IEEE) of the header with Checksum set to 0
+}
+
+var crc64Table = crc64.MakeTable(crc64.ECMA)
+
+// WriteBinaryGraph serializes an AdjacencyArrayGraph into the binary graph format.
+func WriteBinaryGraph[N any, E g.IHalfEdge](aag *g.AdjacencyArrayGraph[N, E], filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := EncodeBinaryGraph(aag, file); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// EncodeBinaryGraph writes an AdjacencyArrayGraph in the binary graph format to w.
+func EncodeBinaryGraph[N any, E g.IHalfEdge](aag *g.AdjacencyArrayGraph[N, E], w io.Writer) error {
+	if err := checkBinaryPlatform(); err != nil {
+		return err
+	}
+	nodeType, err := layoutHash[N]()
+	if err != nil {
+		return err
+	}
+	edgeType, err := layoutHash[E]()
+	if err != nil {
+		return err
+	}
+	if len(aag.Offsets) != aag.NodeCount()+1 {
+		return fmt.Errorf("invalid graph: %d offsets for %d nodes", len(aag.Offsets), aag.NodeCount())
+	}
+
+	sections := [][]byte{sliceBytes(aag.Nodes), sliceBytes(aag.Offsets), sliceBytes(aag.Edges)}
+
+	header := binaryHeader{
+		Magic:     binaryMagic,
+		Version:   binaryVersion,
+		ByteOrder: binaryByteOrderMark,
+		NodeCount: uint64(aag.NodeCount()),
+		EdgeCount: uint64(aag.EdgeCount()),
+		NodeSize:  uint64(sizeOf[N]()),
+		EdgeSize:  uint64(sizeOf[E]()),
+		NodeType:  nodeType,
+		EdgeType:  edgeType,
+	}
+	position := align(uint64(binary.Size(header)))
+	for i, section := range sections {
+		header.Sections[i] = binarySection{Offset: position, Length: uint64(len(section)), Checksum: crc64.Checksum(section, crc64Table)}
+		position = align(position + uint64(len(section)))
+	}
+	header.Checksum = headerChecksum(header)
+
+	headerBuffer := new(bytes.Buffer)
+	binary.Write(headerBuffer, binary.LittleEndian, header)
+	if _, err := w.Write(headerBuffer.Bytes()); err != nil {
+		return err
+	}
+	written := uint64(headerBuffer.Len())
+	for i, section := range sections {
+		// padding up to the aligned start of the section
+		if _, err := w.Write(make([]byte, header.Sections[i].Offset-written)); err != nil {
+			return err
+		}
+		if _, err := w.Write(section); err != nil {
+			return err
+		}
+		written = header.Sections[i].Offset + uint64(len(section))
+	}
+	return nil
+}
+
+// NewAdjacencyArrayFromBinary reads a binary graph file into memory.
+// Checksums of the arrays are verified iff verify is true.
+func NewAdjacencyArrayFromBinary[N any, E g.IHalfEdge](filename string, verify bool) (*g.AdjacencyArrayGraph[N, E], error) {
+	data, err := readAligned(filename)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBinaryGraph[N, E](data, verify)
+}
+
+// MappedGraph is an AdjacencyArrayGraph whose slices refer to a read-only memory-mapped binary graph file.
+// The graph is usable immediately after mapping. Pages are loaded lazily and shared with other processes mapping the same file.
+//
+// The slices of the graph must not be modified. Close unmaps the file, after which the graph must not be used anymore.
+type MappedGraph[N any, E g.IHalfEdge] struct {
+	*g.AdjacencyArrayGraph[N, E]
+	data []byte
+}
+
+// MapAdjacencyArrayFromBinary memory-maps a binary graph file read-only.
+// Checksums of the arrays are verified iff verify is true. Note that verification reads the entire file.
+func MapAdjacencyArrayFromBinary[N any, E g.IHalfEdge](filename string, verify bool) (*MappedGraph[N, E], error) {
+	data, err := mapFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	aag, err := decodeBinaryGraph[N, E](data, verify)
+	if err != nil {
+		unmapFile(data)
+		return nil, err
+	}
+	return &MappedGraph[N, E]{AdjacencyArrayGraph: aag, data: data}, nil
+}
+
+// Close releases the memory mapping.
+func (mg *MappedGraph[N, E]) Close() error {
+	if mg.data == nil {
+		return nil
+	}
+	err := unmapFile(mg.data)
+	mg.data = nil
+	mg.AdjacencyArrayGraph = nil
+	return err
+}
+
+// decodeBinaryGraph validates the header of a binary graph file and creates a graph whose slices refer to data.
+func decodeBinaryGraph[N any, E g.IHalfEdge](data []byte, verify bool) (*g.AdjacencyArrayGraph[N, E], error) {
+	if err := checkBinaryPlatform(); err != nil {
+		return nil, err
+	}
+
+	var header binaryHeader
+	headerSize := binary.Size(header)
+	if len(data) < headerSize {
+		return nil, errors.New("binary graph: file is too short")
+	}
+	binary.Read(bytes.NewReader(data[:headerSize]), binary.LittleEndian, &header)
+
+	if header.Magic != binaryMagic {
+		return nil, errors.New("binary graph: not a binary graph file")
+	}
+	if header.Version != binaryVersion {
+		return nil, fmt.Errorf("binary graph: unsupported version %d", header.Version)
+	}
+	if header.Checksum != headerChecksum(header) {
+		return nil, errors.New("binary graph: header checksum mismatch")
+	}
+	if header.ByteOrder != binaryByteOrderMark {
+		return nil, errors.New("binary graph: file has been written on a platform with different byte order")
+	}
+
+	nodeType, err := layoutHash[N]()
+	if err != nil {
+		return nil, err
+	}
+	edgeType, err := layoutHash[E]()
+	if err != nil {
+		return nil, err
+	}
+	if header.NodeType != nodeType || header.NodeSize != uint64(sizeOf[N]()) {
+		return nil, fmt.Errorf("binary graph: node type %s does not match the node type of the file", reflect.TypeOf((*N)(nil)).Elem())
+	}
+	if header.EdgeType != edgeType || header.EdgeSize != uint64(sizeOf[E]()) {
+		return nil, fmt.Errorf("binary graph: edge type %s does not match the edge type of the file", reflect.TypeOf((*E)(nil)).Elem())
+	}
+
+	// each section must fit into the file, which bounds the counts before their lengths are computed without overflow
+	fileLength := uint64(len(data))
+	if header.NodeCount >= fileLength/8 {
+		return nil, fmt.Errorf("binary graph: %d nodes exceed the file size", header.NodeCount)
+	}
+	counts := [3]uint64{header.NodeCount, header.NodeCount + 1, header.EdgeCount}
+	sizes := [3]uint64{header.NodeSize, 8, header.EdgeSize}
+	var expectedLengths [3]uint64
+	for i := range counts {
+		maxCount := fileLength // zero-sized elements
+		if sizes[i] > 0 {
+			maxCount = fileLength / sizes[i]
+		}
+		if counts[i] > maxCount {
+			return nil, fmt.Errorf("binary graph: %d elements of section %d exceed the file size", counts[i], i)
+		}
+		expectedLengths[i] = counts[i] * sizes[i]
+	}
+
+	sections := make([][]byte, 3)
+	for i, section := range header.Sections {
+		if section.Length != expectedLengths[i] {
+			return nil, fmt.Errorf("binary graph: section %d has length %d, expected %d", i, section.Length, expectedLengths[i])
+		}
+		if section.Offset%binaryAlignment != 0 || section.Offset > fileLength || section.Length > fileLength-section.Offset {
+			return nil, fmt.Errorf("binary graph: section %d is out of bounds or misaligned", i)
+		}
+		sections[i] = data[section.Offset : section.Offset+section.Length]
+		if verify && crc64.Checksum(sections[i], crc64Table) != section.Checksum {
+			return nil, fmt.Errorf("binary graph: checksum mismatch in section %d", i)
+		}
+	}
+
+	aag := g.AdjacencyArrayGraph[N, E]{
+		Nodes:   bytesSlice[N](sections[nodeSection], int(header.NodeCount)),
+		Offsets: bytesSlice[int](sections[offsetSection], int(header.NodeCount)+1),
+		Edges:   bytesSlice[E](sections[edgeSection], int(header.EdgeCount)),
+	}
+	if aag.Offsets[0] != 0 || aag.Offsets[aag.NodeCount()] != aag.EdgeCount() {
+		return nil, errors.New("binary graph: invalid offsets")
+	}
+	if verify {
+		// the offsets are only read entirely if the file is verified, which keeps mapping lazy otherwise
+		for i := 0; i < aag.NodeCount(); i++ {
+			if aag.Offsets[i] > aag.Offsets[i+1] {
+				return nil, fmt.Errorf("binary graph: offsets decrease at node %d", i)
+			}
+		}
+	}
+	return &aag, nil
+}
+
+// checkBinaryPlatform returns an error iff the platform cannot read or write the binary graph format.
+func checkBinaryPlatform() error {
+	if strconv.IntSize != 64 {
+		return errors.New("binary graph: format requires 64-bit integers")
+	}
+	if mark := uint32(binaryByteOrderMark); *(*byte)(unsafe.Pointer(&mark)) != 0x04 {
+		return errors.New("binary graph: format requires a little-endian platform")
+	}
+	return nil
+}
+
+func headerChecksum(header binaryHeader) uint32 {
+	header.Checksum = 0
+	buffer := new(bytes.Buffer)
+	binary.Write(buffer, binary.LittleEndian, header)
+	return crc32.ChecksumIEEE(buffer.Bytes())
+}
+
+// align rounds position up to the next multiple of binaryAlignment.
+func align(position uint64) uint64 {
+	return (position + binaryAlignment - 1) / binaryAlignment * binaryAlignment
+}
+
+// readAligned reads a file into a buffer whose first byte is aligned to 8 bytes.
+func readAligned(filename string) ([]byte, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	buffer := make([]uint64, (info.Size()+7)/8)
+	data := sliceBytes(buffer)[:info.Size()]
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func sizeOf[T any]() uintptr {
+	var t T
+	return unsafe.Sizeof(t)
+}
+
+// sliceBytes returns the raw memory of a slice.
+func sliceBytes[T any](s []T) []byte {
+	if len(s) == 0 {
+		return []byte{}
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(&s[0])), len(s)*int(sizeOf[T]()))
+}
+
+// bytesSlice interprets raw memory as a slice of n elements of type T.
+func bytesSlice[T any](b []byte, n int) []T {
+	if len(b) == 0 {
+		// zero elements or zero-sized elements
+		return make([]T, n)
+	}
+	return unsafe.Slice((*T)(unsafe.Pointer(&b[0])), n)
+}
+
+// layoutHash computes a hash of the memory layout of type T.
+// An error is returned iff T is not plain data.
+func layoutHash[T any]() (uint64, error) {
+	layout, err := typeLayout(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return 0, err
+	}
+	hash := fnv.New64a()
+	hash.Write([]byte(layout))
+	return hash.Sum64(), nil
+}
+
+// typeLayout describes the memory layout of a plain data type, i.e. field names, kinds and offsets.
+func typeLayout(t reflect.Type) (string, error) {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return t.Kind().String(), nil
+	case reflect.Array:
+		elem, err := typeLayout(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("[%d]%s", t.Len(), elem), nil
+	case reflect.Struct:
+		layout := "struct{"
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldLayout, err := typeLayout(field.Type)
+			if err != nil {
+				return "", err
+			}
+			layout += fmt.Sprintf("%s@%d:%s;", field.Name, field.Offset, fieldLayout)
+		}
+		return layout + "}", nil
+	default:
+		return "", fmt.Errorf("binary graph: type %s is not plain data (kind %s)", t, t.Kind())
+	}
+}
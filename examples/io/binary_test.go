@@ -0,0 +1,120 @@
+package io
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc64"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestBinaryGraphRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	alg := &g.AdjacencyListGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]]{}
+	alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: 1.5, Lon: -3}, Partition_: 2})
+	alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: 47, Lon: 11}, Partition_: 5})
+	alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: -20, Lon: 170}, Partition_: 0})
+	alg.InsertHalfEdge(0, g.FlaggedHalfEdge[int, uint64]{To_: 1, Weight_: 10, Flag: 0b100100})
+	alg.InsertHalfEdge(1, g.FlaggedHalfEdge[int, uint64]{To_: 0, Weight_: 10, Flag: 0b100})
+	alg.InsertHalfEdge(1, g.FlaggedHalfEdge[int, uint64]{To_: 2, Weight_: 42, Flag: 0b1})
+	aag := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](alg)
+
+	filename := filepath.Join(t.TempDir(), "graph.bin")
+	if err := WriteBinaryGraph(aag, filename); err != nil {
+		t.Fatal(err)
+	}
+
+	mapped, err := MapAdjacencyArrayFromBinary[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](filename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mapped.Close()
+	read, err := NewAdjacencyArrayFromBinary[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](filename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, loaded := range []g.Graph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]]{mapped, read} {
+		if loaded.NodeCount() != aag.NodeCount() || loaded.EdgeCount() != aag.EdgeCount() {
+			t.Fatalf("Loaded graph has %d nodes and %d edges, expected %d and %d", loaded.NodeCount(), loaded.EdgeCount(), aag.NodeCount(), aag.EdgeCount())
+		}
+		for i := 0; i < aag.NodeCount(); i++ {
+			if loaded.GetNode(i) != aag.GetNode(i) {
+				t.Errorf("Different node with ID=%d: got %v, expected %v", i, loaded.GetNode(i), aag.GetNode(i))
+			}
+			for j, edge := range aag.GetHalfEdgesFrom(i) {
+				if loaded.GetHalfEdgesFrom(i)[j] != edge {
+					t.Errorf("Different edge: got %v, expected %v", loaded.GetHalfEdgesFrom(i)[j], edge)
+				}
+			}
+		}
+	}
+
+	// loading with a different edge type must fail
+	if _, err := NewAdjacencyArrayFromBinary[g.PartGeoPoint, g.WeightedHalfEdge[int]](filename, false); err == nil {
+		t.Error("Loading a graph with a different edge type did not fail")
+	}
+
+	// corrupting an array must be detected by the checksum
+	data, _ := os.ReadFile(filename)
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(filename, data, 0644)
+	if _, err := NewAdjacencyArrayFromBinary[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](filename, true); err == nil {
+		t.Error("Corrupted file has not been detected")
+	}
+}
+
+func TestBinaryGraphInvalidHeader(t *testing.T) {
+	t.Parallel()
+
+	alg := &g.AdjacencyListGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]]{}
+	for i := 0; i < 3; i++ {
+		alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: float64(i), Lon: float64(i)}})
+	}
+	alg.InsertHalfEdge(0, g.FlaggedHalfEdge[int, uint64]{To_: 1, Weight_: 10})
+	alg.InsertHalfEdge(1, g.FlaggedHalfEdge[int, uint64]{To_: 2, Weight_: 20})
+	alg.InsertHalfEdge(2, g.FlaggedHalfEdge[int, uint64]{To_: 0, Weight_: 30})
+	aag := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](alg)
+
+	buffer := new(bytes.Buffer)
+	if err := EncodeBinaryGraph(aag, buffer); err != nil {
+		t.Fatal(err)
+	}
+	var header binaryHeader
+	binary.Read(bytes.NewReader(buffer.Bytes()), binary.LittleEndian, &header)
+
+	tests := map[string]func(header *binaryHeader, data []byte){
+		// the lengths of all sections wrap around to their original values, since the node size is a multiple of 8
+		"overflowing node count": func(header *binaryHeader, data []byte) {
+			header.NodeCount += 1 << 61
+		},
+		"overflowing section bounds": func(header *binaryHeader, data []byte) {
+			header.Sections[edgeSection].Offset = math.MaxUint64 - binaryAlignment + 1
+		},
+		"decreasing offsets": func(header *binaryHeader, data []byte) {
+			offsets := data[header.Sections[offsetSection].Offset:][:header.Sections[offsetSection].Length]
+			binary.LittleEndian.PutUint64(offsets[8:], 3)
+			header.Sections[offsetSection].Checksum = crc64.Checksum(offsets, crc64Table)
+		},
+	}
+	for name, corrupt := range tests {
+		data := append([]byte(nil), buffer.Bytes()...)
+		corrupted := header
+		corrupt(&corrupted, data)
+		corrupted.Checksum = headerChecksum(corrupted)
+		headerBuffer := new(bytes.Buffer)
+		binary.Write(headerBuffer, binary.LittleEndian, corrupted)
+		copy(data, headerBuffer.Bytes())
+
+		filename := filepath.Join(t.TempDir(), "graph.bin")
+		os.WriteFile(filename, data, 0644)
+		if _, err := NewAdjacencyArrayFromBinary[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](filename, true); err == nil {
+			t.Errorf("[%s] Expected an error, got none", name)
+		}
+	}
+}
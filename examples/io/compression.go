@@ -0,0 +1,70 @@
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Magic numbers of the supported compression formats
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// OpenFile opens a file for reading, which is transparently decompressed if it is gzip- or zstd-compressed, see NewDecompressingReader.
+func OpenFile(filename string) (io.ReadCloser, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := NewDecompressingReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &readCloser{Reader: reader, closers: []io.Closer{reader, file}}, nil
+}
+
+// NewDecompressingReader detects the compression of a stream by its magic number and returns a reader of the decompressed data.
+// gzip (including concatenated members as written by pigz) and zstd are supported; other data is passed through unchanged.
+// Closing the returned reader releases the decompressor, but does not close r.
+func NewDecompressingReader(r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(buffered)
+	case bytes.HasPrefix(magic, zstdMagic):
+		decoder, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return io.NopCloser(buffered), nil
+	}
+}
+
+// readCloser reads from a decompressor and closes the decompressor and the underlying file.
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (rc *readCloser) Close() error {
+	var err error
+	for _, closer := range rc.closers {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,191 @@
+package io
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Column names of the structural columns of nodes.csv and edges.csv
+const (
+	CSV_ID_COLUMN     = "id"
+	CSV_SOURCE_COLUMN = "source"
+	CSV_TARGET_COLUMN = "target"
+)
+
+// WriteCsvFiles writes a graph into a nodes.csv and an edges.csv file, see WriteCsv.
+func WriteCsvFiles[N any, E g.IHalfEdge](graph g.Graph[N, E], nodesFilename, edgesFilename string, nodeAttributes *AttributeMap[N], edgeAttributes *AttributeMap[E]) error {
+	err := writeFile(nodesFilename, func(w io.Writer) error {
+		return writeCsvNodes(graph, w, nodeAttributes)
+	})
+	if err != nil {
+		return err
+	}
+	return writeFile(edgesFilename, func(w io.Writer) error {
+		return writeCsvEdges(graph, w, edgeAttributes)
+	})
+}
+
+// WriteCsv writes the nodes of a graph as CSV with the columns 'id' and the node attributes,
+// and the edges as CSV with the columns 'source', 'target' and the edge attributes.
+// The format is understood by pandas, networkx (from_pandas_edgelist) and Gephi's spreadsheet import.
+func WriteCsv[N any, E g.IHalfEdge](graph g.Graph[N, E], nodesWriter, edgesWriter io.Writer, nodeAttributes *AttributeMap[N], edgeAttributes *AttributeMap[E]) error {
+	if err := writeCsvNodes(graph, nodesWriter, nodeAttributes); err != nil {
+		return err
+	}
+	return writeCsvEdges(graph, edgesWriter, edgeAttributes)
+}
+
+func writeCsvNodes[N any, E g.IHalfEdge](graph g.Graph[N, E], w io.Writer, attributes *AttributeMap[N]) error {
+	writer := csv.NewWriter(w)
+	writer.Write(append([]string{CSV_ID_COLUMN}, attributes.Names()...))
+	for id := 0; id < graph.NodeCount(); id++ {
+		if err := writer.Write(append([]string{strconv.Itoa(id)}, attributes.Values(graph.GetNode(id))...)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeCsvEdges[N any, E g.IHalfEdge](graph g.Graph[N, E], w io.Writer, attributes *AttributeMap[E]) error {
+	writer := csv.NewWriter(w)
+	writer.Write(append([]string{CSV_SOURCE_COLUMN, CSV_TARGET_COLUMN}, attributes.Names()...))
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		for _, edge := range graph.GetHalfEdgesFrom(tail) {
+			record := append([]string{strconv.Itoa(tail), strconv.Itoa(edge.To())}, attributes.Values(edge)...)
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCsvFiles reads a graph from a nodes.csv and an edges.csv file, see ReadCsv.
+func ReadCsvFiles[N any, E g.IRetargetableHalfEdge](nodesFilename, edgesFilename string, nodeAttributes *AttributeMap[N], edgeAttributes *AttributeMap[E]) (*g.AdjacencyListGraph[N, E], error) {
+	nodesFile, err := OpenFile(nodesFilename)
+	if err != nil {
+		return nil, err
+	}
+	defer nodesFile.Close()
+	edgesFile, err := OpenFile(edgesFilename)
+	if err != nil {
+		return nil, err
+	}
+	defer edgesFile.Close()
+	return ReadCsv(nodesFile, edgesFile, nodeAttributes, edgeAttributes)
+}
+
+// ReadCsv reads a graph from CSV files as written by WriteCsv.
+// Columns are identified by the names in the header line, such that their order is arbitrary and unknown columns are ignored.
+// Node IDs are arbitrary strings, which are mapped to consecutive NodeIds in the order of the nodes file.
+// Duplicate edges (same tail and head) are ignored. Errors are of type *ParseError, unless reading fails.
+func ReadCsv[N any, E g.IRetargetableHalfEdge](nodesReader, edgesReader io.Reader, nodeAttributes *AttributeMap[N], edgeAttributes *AttributeMap[E]) (*g.AdjacencyListGraph[N, E], error) {
+	alg := g.AdjacencyListGraph[N, E]{Nodes: make([]N, 0), Edges: make([][]E, 0)}
+	ids := make(map[string]g.NodeId)
+
+	err := readCsv(nodesReader, []string{CSV_ID_COLUMN}, nodeAttributes.Names(), func(key []string, values []string) error {
+		if _, ok := ids[key[0]]; ok {
+			return fmt.Errorf("duplicate node ID '%s'", key[0])
+		}
+		node, err := nodeAttributes.Parse(values)
+		if err != nil {
+			return err
+		}
+		ids[key[0]] = alg.AppendNode(node)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = readCsv(edgesReader, []string{CSV_SOURCE_COLUMN, CSV_TARGET_COLUMN}, edgeAttributes.Names(), func(key []string, values []string) error {
+		tail, ok := ids[key[0]]
+		if !ok {
+			return fmt.Errorf("unknown source node '%s'", key[0])
+		}
+		head, ok := ids[key[1]]
+		if !ok {
+			return fmt.Errorf("unknown target node '%s'", key[1])
+		}
+		edge, err := edgeAttributes.Parse(values)
+		if err != nil {
+			return err
+		}
+		alg.InsertHalfEdge(tail, edge.SetTo(head).(E))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &alg, nil
+}
+
+// readCsv calls recordFnc with the values of the structural columns and the values of the attributes of each record.
+func readCsv(r io.Reader, structural []string, attributes []string, recordFnc func(key []string, values []string) error) error {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &ParseError{Format: "csv", Line: 1, Err: fmt.Errorf("missing header")}
+	} else if err != nil {
+		return wrapCsvError(err)
+	}
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[name] = i
+	}
+	lookup := func(names []string) ([]int, error) {
+		indices := make([]int, len(names))
+		for i, name := range names {
+			index, ok := positions[name]
+			if !ok {
+				return nil, &ParseError{Format: "csv", Line: 1, Err: fmt.Errorf("missing column '%s'", name)}
+			}
+			indices[i] = index
+		}
+		return indices, nil
+	}
+	keyColumns, err := lookup(structural)
+	if err != nil {
+		return err
+	}
+	valueColumns, err := lookup(attributes)
+	if err != nil {
+		return err
+	}
+
+	key, values := make([]string, len(keyColumns)), make([]string, len(valueColumns))
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return wrapCsvError(err)
+		}
+		for i, column := range keyColumns {
+			key[i] = record[column]
+		}
+		for i, column := range valueColumns {
+			values[i] = record[column]
+		}
+		if err := recordFnc(key, values); err != nil {
+			line, _ := reader.FieldPos(0)
+			return &ParseError{Format: "csv", Line: line, Err: err}
+		}
+	}
+}
+
+// wrapCsvError converts the errors of encoding/csv into a *ParseError.
+func wrapCsvError(err error) error {
+	if csvError, ok := err.(*csv.ParseError); ok {
+		return &ParseError{Format: "csv", Line: csvError.Line, Err: csvError.Err}
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package io
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// testPartitionedGraph returns a graph with partitions and 128-bit arc flags that span both flag fields.
+func testPartitionedGraph() *g.AdjacencyListGraph[g.PartGeoPoint, g.LargeFlaggedHalfEdge[int]] {
+	alg := g.AdjacencyListGraph[g.PartGeoPoint, g.LargeFlaggedHalfEdge[int]]{}
+	alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: 47.5, Lon: 11.25}, Partition_: 3})
+	alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: -20, Lon: 170}, Partition_: 100})
+	alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: 0.1, Lon: -0.3}, Partition_: 0})
+	alg.InsertHalfEdge(0, g.LargeFlaggedHalfEdge[int]{To_: 1, Weight_: 10, LsbFlag: 1 << 3, MsbFlag: 1 << 36})
+	alg.InsertHalfEdge(1, g.LargeFlaggedHalfEdge[int]{To_: 0, Weight_: 10, LsbFlag: ^uint64(0), MsbFlag: ^uint64(0)})
+	alg.InsertHalfEdge(1, g.LargeFlaggedHalfEdge[int]{To_: 2, Weight_: 42})
+	return &alg
+}
+
+func TestCsvRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	alg := testPartitionedGraph()
+	var nodes, edges bytes.Buffer
+	if err := WriteCsv[g.PartGeoPoint, g.LargeFlaggedHalfEdge[int]](alg, &nodes, &edges, PartGeoPointAttributes, LargeFlaggedHalfEdgeAttributes); err != nil {
+		t.Fatal(err)
+	}
+	if header := strings.SplitN(nodes.String(), "\n", 2)[0]; header != "id,lat,lon,partition" {
+		t.Errorf("Unexpected node header '%s'", header)
+	}
+	if line := strings.Split(edges.String(), "\n")[1]; line != "0,1,10,"+strings.Repeat("0", 3)+"1"+strings.Repeat("0", 96)+"1"+strings.Repeat("0", 27) {
+		t.Errorf("Unexpected edge line '%s'", line)
+	}
+
+	read, err := ReadCsv(&nodes, &edges, PartGeoPointAttributes, LargeFlaggedHalfEdgeAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqualGraphs[g.PartGeoPoint, g.LargeFlaggedHalfEdge[int]](t, alg, read)
+}
+
+func TestCsvColumnOrder(t *testing.T) {
+	t.Parallel()
+
+	// columns are identified by name, unknown columns are ignored and bitsets may be shorter than the flag fields
+	nodes := "label,lon,id,lat\nA,11.25,a,47.5\nB,170,b,-20\n"
+	edges := "weight,target,source,arc_flags,highway\n7,b,a,0101,primary\n"
+	alg, err := ReadCsv(strings.NewReader(nodes), strings.NewReader(edges), GeoPointAttributes, FlaggedHalfEdgeAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alg.NodeCount() != 2 || alg.GetNode(1) != (g.GeoPoint{Lat: -20, Lon: 170}) {
+		t.Errorf("Unexpected nodes %v", alg.Nodes)
+	}
+	if edge := alg.GetHalfEdgesFrom(0)[0]; edge.To_ != 1 || edge.Weight_ != 7 || edge.Flag != 0b1010 {
+		t.Errorf("Unexpected edge %v", edge)
+	}
+
+	invalid := map[[2]string]string{
+		{"id,lat\na,1\n", "source,target,weight\n"}:                          "missing column 'lon'",
+		{"id,lat,lon\na,1,2\na,1,2\n", "source,target,weight\n"}:             "line 3: duplicate node ID 'a'",
+		{"id,lat,lon\na,1,2\n", "source,target,weight,arc_flags\na,b,1,1\n"}: "line 2: unknown target node 'b'",
+		{"id,lat,lon\na,1,2\n", "source,target,weight,arc_flags\na,a,1,2\n"}: "invalid bitset '2'",
+	}
+	for input, message := range invalid {
+		_, err := ReadCsv(strings.NewReader(input[0]), strings.NewReader(input[1]), GeoPointAttributes, FlaggedHalfEdgeAttributes)
+		var parseError *ParseError
+		if !errors.As(err, &parseError) || !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected parse error '%s', got %v", message, err)
+		}
+	}
+}
+
+func assertEqualGraphs[N comparable, E interface {
+	comparable
+	g.IHalfEdge
+}](t *testing.T, expected, actual g.Graph[N, E]) {
+	t.Helper()
+	if expected.NodeCount() != actual.NodeCount() || expected.EdgeCount() != actual.EdgeCount() {
+		t.Fatalf("Expected %d nodes and %d edges, got %d nodes and %d edges", expected.NodeCount(), expected.EdgeCount(), actual.NodeCount(), actual.EdgeCount())
+	}
+	for id := 0; id < expected.NodeCount(); id++ {
+		if expected.GetNode(id) != actual.GetNode(id) {
+			t.Errorf("Expected node %d %v, got %v", id, expected.GetNode(id), actual.GetNode(id))
+		}
+		expectedEdges, actualEdges := expected.GetHalfEdgesFrom(id), actual.GetHalfEdgesFrom(id)
+		for i := range expectedEdges {
+			if i >= len(actualEdges) || expectedEdges[i] != actualEdges[i] {
+				t.Errorf("Expected edges %v from node %d, got %v", expectedEdges, id, actualEdges)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package io
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// DIMACS coordinate files store longitude and latitude as integers in millionths of a degree.
+const DIMACS_COORDINATE_SCALE = 1e6
+
+// DIMACS files of the 9th DIMACS Implementation Challenge (shortest paths) number nodes from 1 to n,
+// whereas graffiti numbers nodes from 0 to n-1. All readers and writers translate node IDs accordingly.
+
+// NewAdjacencyArrayFromDimacs builds an AdjacencyArrayGraph from a DIMACS graph file (.gr) and a coordinate file (.co).
+// If coFilename is empty, all nodes are located at (0, 0).
+// Parallel arcs, which occur in some of the challenge instances, are resolved according to the given policy.
+// Both files may be gzip- or zstd-compressed, as the challenge instances are distributed as .gz files.
+func NewAdjacencyArrayFromDimacs(grFilename, coFilename string, policy g.DuplicatePolicy) (*g.AdjacencyArrayGraph[g.GeoPoint, g.WeightedHalfEdge[int]], error) {
+	grFile, err := OpenFile(grFilename)
+	if err != nil {
+		return nil, err
+	}
+	defer grFile.Close()
+	nodeCount, arcs, err := ReadDimacsGraph(grFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", grFilename, err)
+	}
+
+	nodes := make([]g.GeoPoint, nodeCount)
+	if coFilename != "" {
+		coFile, err := OpenFile(coFilename)
+		if err != nil {
+			return nil, err
+		}
+		defer coFile.Close()
+		if nodes, err = ReadDimacsCoordinates(coFile, nodeCount); err != nil {
+			return nil, fmt.Errorf("%s: %w", coFilename, err)
+		}
+	}
+
+	builder := g.NewAdjacencyArrayBuilder[g.GeoPoint, g.WeightedHalfEdge[int], int](policy)
+	builder.AddNodes(nodes)
+	builder.AddEdges(arcs)
+	aag, _, err := builder.Build()
+	return aag, err
+}
+
+// ReadDimacsGraph parses a DIMACS graph file (.gr) and returns the number of nodes and the arcs of the graph.
+func ReadDimacsGraph(r io.Reader) (int, []g.Arc[g.WeightedHalfEdge[int]], error) {
+	nodeCount, arcCount := -1, 0
+	var arcs []g.Arc[g.WeightedHalfEdge[int]]
+
+	err := scanDimacs(r, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if nodeCount >= 0 {
+				return errors.New("duplicate problem line")
+			}
+			if len(fields) != 4 || fields[1] != "sp" {
+				return errors.New("expected problem line 'p sp <nodes> <arcs>'")
+			}
+			var err error
+			if nodeCount, err = parseCount(fields[2]); err != nil {
+				return err
+			}
+			if arcCount, err = parseCount(fields[3]); err != nil {
+				return err
+			}
+			arcs = make([]g.Arc[g.WeightedHalfEdge[int]], 0, arcCount)
+		case "a":
+			if nodeCount < 0 {
+				return errors.New("arc before problem line")
+			}
+			if len(fields) != 4 {
+				return errors.New("expected arc line 'a <tail> <head> <weight>'")
+			}
+			tail, err := parseDimacsNode(fields[1], nodeCount)
+			if err != nil {
+				return err
+			}
+			head, err := parseDimacsNode(fields[2], nodeCount)
+			if err != nil {
+				return err
+			}
+			weight, err := strconv.Atoi(fields[3])
+			if err != nil {
+				return err
+			}
+			arcs = append(arcs, g.Arc[g.WeightedHalfEdge[int]]{Tail: tail, Edge: g.WeightedHalfEdge[int]{To_: head, Weight_: weight}})
+		default:
+			return fmt.Errorf("unexpected line type '%s'", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	if nodeCount < 0 {
+		return 0, nil, errors.New("dimacs: missing problem line")
+	}
+	if len(arcs) != arcCount {
+		return 0, nil, fmt.Errorf("dimacs: problem line announces %d arcs, found %d", arcCount, len(arcs))
+	}
+	return nodeCount, arcs, nil
+}
+
+// ReadDimacsCoordinates parses a DIMACS coordinate file (.co) of a graph with the given number of nodes.
+// Coordinates are scaled by DIMACS_COORDINATE_SCALE, i.e. converted from millionths of a degree to degrees.
+func ReadDimacsCoordinates(r io.Reader, nodeCount int) ([]g.GeoPoint, error) {
+	nodes := make([]g.GeoPoint, nodeCount)
+	parsed := make([]bool, nodeCount)
+	parsedCount, announced := 0, -1
+
+	err := scanDimacs(r, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if len(fields) != 5 || fields[1] != "aux" || fields[2] != "sp" || fields[3] != "co" {
+				return errors.New("expected problem line 'p aux sp co <nodes>'")
+			}
+			var err error
+			if announced, err = parseCount(fields[4]); err != nil {
+				return err
+			}
+			if announced != nodeCount {
+				return fmt.Errorf("coordinate file describes %d nodes, graph has %d nodes", announced, nodeCount)
+			}
+		case "v":
+			if len(fields) != 4 {
+				return errors.New("expected coordinate line 'v <node> <x> <y>'")
+			}
+			id, err := parseDimacsNode(fields[1], nodeCount)
+			if err != nil {
+				return err
+			}
+			if parsed[id] {
+				return fmt.Errorf("duplicate coordinates of node %s", fields[1])
+			}
+			x, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return err
+			}
+			y, err := strconv.Atoi(fields[3])
+			if err != nil {
+				return err
+			}
+			nodes[id] = g.GeoPoint{Lat: float64(y) / DIMACS_COORDINATE_SCALE, Lon: float64(x) / DIMACS_COORDINATE_SCALE}
+			parsed[id] = true
+			parsedCount++
+		default:
+			return fmt.Errorf("unexpected line type '%s'", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if announced < 0 {
+		return nil, errors.New("dimacs: missing problem line")
+	}
+	if parsedCount != nodeCount {
+		return nil, fmt.Errorf("dimacs: coordinates of %d out of %d nodes are missing", nodeCount-parsedCount, nodeCount)
+	}
+	return nodes, nil
+}
+
+// ReadDimacsSources parses a DIMACS single-source query file (.ss) and returns the source nodes.
+func ReadDimacsSources(r io.Reader) ([]g.NodeId, error) {
+	var sources []g.NodeId
+	announced := -1
+
+	err := scanDimacs(r, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if len(fields) != 5 || fields[1] != "aux" || fields[2] != "sp" || fields[3] != "ss" {
+				return errors.New("expected problem line 'p aux sp ss <sources>'")
+			}
+			var err error
+			announced, err = parseCount(fields[4])
+			return err
+		case "s":
+			if len(fields) != 2 {
+				return errors.New("expected source line 's <node>'")
+			}
+			source, err := parseDimacsNode(fields[1], math.MaxInt)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, source)
+		default:
+			return fmt.Errorf("unexpected line type '%s'", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if announced != len(sources) {
+		return nil, fmt.Errorf("dimacs: problem line announces %d sources, found %d", announced, len(sources))
+	}
+	return sources, nil
+}
+
+// ReadDimacsQueries parses a DIMACS point-to-point query file (.p2p) and returns the queries.
+func ReadDimacsQueries(r io.Reader) ([]sp.Query, error) {
+	var queries []sp.Query
+	announced := -1
+
+	err := scanDimacs(r, func(fields []string) error {
+		switch fields[0] {
+		case "p":
+			if len(fields) != 5 || fields[1] != "aux" || fields[2] != "sp" || fields[3] != "p2p" {
+				return errors.New("expected problem line 'p aux sp p2p <queries>'")
+			}
+			var err error
+			announced, err = parseCount(fields[4])
+			return err
+		case "q":
+			if len(fields) != 3 {
+				return errors.New("expected query line 'q <source> <target>'")
+			}
+			source, err := parseDimacsNode(fields[1], math.MaxInt)
+			if err != nil {
+				return err
+			}
+			target, err := parseDimacsNode(fields[2], math.MaxInt)
+			if err != nil {
+				return err
+			}
+			queries = append(queries, sp.Query{Source: source, Target: target})
+		default:
+			return fmt.Errorf("unexpected line type '%s'", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if announced != len(queries) {
+		return nil, fmt.Errorf("dimacs: problem line announces %d queries, found %d", announced, len(queries))
+	}
+	return queries, nil
+}
+
+// WriteDimacs serializes a graph into a DIMACS graph file (.gr) and a coordinate file (.co).
+func WriteDimacs[N g.Locator, E g.IWeightedHalfEdge[int]](graph g.Graph[N, E], grFilename, coFilename string) error {
+	if err := writeFile(grFilename, func(w io.Writer) error { return WriteDimacsGraph(graph, w) }); err != nil {
+		return err
+	}
+	return writeFile(coFilename, func(w io.Writer) error { return WriteDimacsCoordinates(graph, w) })
+}
+
+// WriteDimacsGraph writes the arcs of a graph in the DIMACS graph format (.gr).
+func WriteDimacsGraph[N any, E g.IWeightedHalfEdge[int]](graph g.Graph[N, E], w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "p sp %d %d\n", graph.NodeCount(), graph.EdgeCount())
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		for _, halfEdge := range graph.GetHalfEdgesFrom(tail) {
+			fmt.Fprintf(writer, "a %d %d %d\n", tail+1, halfEdge.To()+1, halfEdge.Weight())
+		}
+	}
+	return writer.Flush()
+}
+
+// WriteDimacsCoordinates writes the locations of the nodes of a graph in the DIMACS coordinate format (.co).
+// Coordinates are scaled by DIMACS_COORDINATE_SCALE and rounded to integers.
+func WriteDimacsCoordinates[N g.Locator, E g.IHalfEdge](graph g.Graph[N, E], w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "p aux sp co %d\n", graph.NodeCount())
+	for id := 0; id < graph.NodeCount(); id++ {
+		location := graph.GetNode(id).Location()
+		x := int(math.Round(location.Lon * DIMACS_COORDINATE_SCALE))
+		y := int(math.Round(location.Lat * DIMACS_COORDINATE_SCALE))
+		fmt.Fprintf(writer, "v %d %d %d\n", id+1, x, y)
+	}
+	return writer.Flush()
+}
+
+// WriteDimacsQueries writes queries in the DIMACS point-to-point query format (.p2p).
+func WriteDimacsQueries(queries []sp.Query, w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "p aux sp p2p %d\n", len(queries))
+	for _, query := range queries {
+		fmt.Fprintf(writer, "q %d %d\n", query.Source+1, query.Target+1)
+	}
+	return writer.Flush()
+}
+
+// scanDimacs calls lineFnc with the whitespace-separated fields of each line, skipping empty lines and comment lines.
+// Lines are not limited in length. Errors returned by lineFnc are wrapped into a ParseError.
+func scanDimacs(r io.Reader, lineFnc func(fields []string) error) error {
+	reader := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] != "c" {
+			if err := lineFnc(fields); err != nil {
+				return &ParseError{Format: "dimacs", Line: lineNumber, Err: err}
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// parseCount parses a non-negative number of nodes, arcs or queries.
+func parseCount(field string) (int, error) {
+	count, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, err
+	}
+	if count < 0 {
+		return 0, fmt.Errorf("negative count %d", count)
+	}
+	return count, nil
+}
+
+// parseDimacsNode parses a 1-based DIMACS node ID and returns the corresponding 0-based NodeId.
+func parseDimacsNode(field string, nodeCount int) (g.NodeId, error) {
+	id, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, err
+	}
+	if id < 1 || id > nodeCount {
+		return 0, fmt.Errorf("node %d out of range [1, %d]", id, nodeCount)
+	}
+	return id - 1, nil
+}
+
+// writeFile creates a file and passes it to writeFnc.
+func writeFile(filename string, writeFnc func(w io.Writer) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := writeFnc(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
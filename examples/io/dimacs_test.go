@@ -0,0 +1,117 @@
+package io
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+const dimacsGraph = `c 9th DIMACS Implementation Challenge: Shortest Paths
+c
+p sp 3 4
+a 1 2 10
+a 2 1 10
+a 2 3 42
+a 2 3 40
+`
+
+const dimacsCoordinates = `c coordinates
+p aux sp co 3
+v 1 -73530767 41085396
+v 2 -73530538 41086098
+v 3 11000000 -47500000
+`
+
+func TestDimacsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	grFile, coFile := filepath.Join(dir, "graph.gr"), filepath.Join(dir, "graph.co")
+	os.WriteFile(grFile, []byte(dimacsGraph), 0644)
+	os.WriteFile(coFile, []byte(dimacsCoordinates), 0644)
+
+	aag, err := NewAdjacencyArrayFromDimacs(grFile, coFile, g.KEEP_MIN_WEIGHT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aag.NodeCount() != 3 || aag.EdgeCount() != 3 {
+		t.Fatalf("Expected 3 nodes and 3 edges, got %d nodes and %d edges", aag.NodeCount(), aag.EdgeCount())
+	}
+	if edges := aag.GetHalfEdgesFrom(1); len(edges) != 2 || edges[1].To() != 2 || edges[1].Weight() != 40 {
+		t.Errorf("Parallel arcs have not been merged: %v", edges)
+	}
+	if node := aag.GetNode(2); node.Lat != -47.5 || node.Lon != 11 {
+		t.Errorf("Expected node at (-47.5, 11), got %v", node)
+	}
+
+	// writing and reading again preserves nodes and edges
+	outGr, outCo := filepath.Join(dir, "out.gr"), filepath.Join(dir, "out.co")
+	if err := WriteDimacs[g.GeoPoint, g.WeightedHalfEdge[int]](aag, outGr, outCo); err != nil {
+		t.Fatal(err)
+	}
+	read, err := NewAdjacencyArrayFromDimacs(outGr, outCo, g.REJECT_DUPLICATES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id := 0; id < aag.NodeCount(); id++ {
+		if read.GetNode(id) != aag.GetNode(id) {
+			t.Errorf("Node %d: expected %v, got %v", id, aag.GetNode(id), read.GetNode(id))
+		}
+		expected, actual := aag.GetHalfEdgesFrom(id), read.GetHalfEdgesFrom(id)
+		if len(expected) != len(actual) {
+			t.Fatalf("Node %d: expected %d edges, got %d", id, len(expected), len(actual))
+		}
+		for i := range expected {
+			if expected[i] != actual[i] {
+				t.Errorf("Node %d: expected edge %v, got %v", id, expected[i], actual[i])
+			}
+		}
+	}
+}
+
+func TestDimacsQueries(t *testing.T) {
+	t.Parallel()
+
+	queries := []sp.Query{{Source: 0, Target: 2}, {Source: 5, Target: 1}}
+	var buffer bytes.Buffer
+	if err := WriteDimacsQueries(queries, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadDimacsQueries(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(queries) || read[0] != queries[0] || read[1] != queries[1] {
+		t.Errorf("Expected %v, got %v", queries, read)
+	}
+
+	sources, err := ReadDimacsSources(strings.NewReader("c sources\np aux sp ss 2\ns 1\ns 7"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 2 || sources[0] != 0 || sources[1] != 6 {
+		t.Errorf("Expected [0 6], got %v", sources)
+	}
+}
+
+func TestDimacsErrors(t *testing.T) {
+	t.Parallel()
+
+	invalid := map[string]string{
+		"a 1 2 3\n":                        "line 1: arc before problem line",
+		"p sp 2 1\na 1 3 5\n":              "line 2: node 3 out of range [1, 2]",
+		"p sp 2 2\na 1 2 5\n":              "announces 2 arcs, found 1",
+		"p sp 2 1\n\nc comment\nx 1 2 5\n": "line 4: unexpected line type 'x'",
+	}
+	for input, message := range invalid {
+		_, _, err := ReadDimacsGraph(strings.NewReader(input))
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected error containing '%s', got %v", message, err)
+		}
+	}
+}
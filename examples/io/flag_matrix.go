@@ -0,0 +1,207 @@
+package io
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc64"
+	"hash/fnv"
+	"io"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Binary flag matrix format
+//
+// The arc flags of a FlagMatrix are stored separately from the graph as follows (all integers little-endian):
+//
+//	header     fixed-size description of the matrix (see flagMatrixHeader)
+//	metadata   length (32-bit integer) and JSON encoding of the Metadata
+//	rows       for each edge in the order of the adjacency array: Words 64-bit words of flags
+//	checksum   CRC-64 (ECMA) of all preceding bytes
+//
+// Since the rows of the matrix are identified by the position of the edges in the adjacency array, the header contains
+// a hash of the order of the edges in addition to the fingerprint of the graph (see graph.Fingerprint). Since the columns
+// refer to the partitions of the assignment the flags have been computed for, the header also contains a hash of the assignment.
+
+// magic bytes at the beginning of every flag matrix file
+var flagMatrixMagic = [8]byte{'G', 'R', 'A', 'F', 'F', 'F', 'L', 'G'}
+
+// current version of the flag matrix format
+const flagMatrixVersion = 2
+
+// flagMatrixHeader is the fixed-size header of a flag matrix file.
+type flagMatrixHeader struct {
+	Magic       [8]byte
+	Version     uint32
+	Width       uint32 // number of partitions
+	NodeCount   uint64
+	EdgeCount   uint64 // number of rows
+	Fingerprint uint64 // graph.Fingerprint of the graph
+	EdgeOrder   uint64 // hash of the heads of the edges in the order of the adjacency array
+	Partitions  uint64 // hash of the partition assignment
+}
+
+// WriteFlagMatrixFile writes the arc flags of the given graph, whose edges refer to the rows of the matrix, into a file.
+// The columns of the matrix refer to the partitions of the assignment.
+// The metadata describes the partitioning; its base graph is set to the fingerprint of the graph.
+func WriteFlagMatrixFile[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], assignment g.PartitionAssignment, matrix *g.FlagMatrix, metadata Metadata, filename string) error {
+	if len(assignment) != graph.NodeCount() {
+		return fmt.Errorf("flag matrix: assignment of %d nodes does not match the node count %d", len(assignment), graph.NodeCount())
+	}
+	metadata.BaseGraph = g.Fingerprint[N, E, W](graph)
+	return writeFile(filename, func(w io.Writer) error {
+		return EncodeFlagMatrix(matrix, graph.NodeCount(), edgeOrder[N, E](graph), partitionHash(assignment), metadata, w)
+	})
+}
+
+// EncodeFlagMatrix writes a flag matrix in the binary flag matrix format to w.
+// nodeCount, edgeOrder and the base graph of the metadata describe the graph the flags have been computed for,
+// partitions is the hash of the partition assignment.
+func EncodeFlagMatrix(matrix *g.FlagMatrix, nodeCount int, edgeOrder uint64, partitions uint64, metadata Metadata, w io.Writer) error {
+	metadata.Artifact = ARTIFACT_FLAG_MATRIX
+	encodedMetadata := []byte(metadata.String())
+
+	checksum := crc64.New(crc64Table)
+	writer := bufio.NewWriter(io.MultiWriter(w, checksum))
+	header := flagMatrixHeader{
+		Magic:       flagMatrixMagic,
+		Version:     flagMatrixVersion,
+		Width:       uint32(matrix.Width),
+		NodeCount:   uint64(nodeCount),
+		EdgeCount:   uint64(matrix.Rows()),
+		Fingerprint: metadata.BaseGraph,
+		EdgeOrder:   edgeOrder,
+		Partitions:  partitions,
+	}
+	binary.Write(writer, binary.LittleEndian, header)
+	binary.Write(writer, binary.LittleEndian, uint32(len(encodedMetadata)))
+	writer.Write(encodedMetadata)
+	buffer := make([]byte, 8)
+	for _, word := range matrix.Bits {
+		binary.LittleEndian.PutUint64(buffer, word)
+		writer.Write(buffer)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint64(buffer, checksum.Sum64())
+	_, err := w.Write(buffer)
+	return err
+}
+
+// ReadFlagMatrixFile reads the flag matrix of the given graph and partition assignment from a file, whose edges can then be attached
+// to the matrix by graph.NewMatrixFlaggedGraph. An error wrapping ErrIncompatibleArtifact is returned if the file has been written
+// for a different graph, a different order of the edges or a different assignment.
+func ReadFlagMatrixFile[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], assignment g.PartitionAssignment, filename string) (*g.FlagMatrix, *Metadata, error) {
+	file, err := OpenFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	matrix, metadata, err := DecodeFlagMatrix(file, graph.NodeCount(), graph.EdgeCount(), g.Fingerprint[N, E, W](graph), edgeOrder[N, E](graph), partitionHash(assignment))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return matrix, metadata, nil
+}
+
+// DecodeFlagMatrix reads a flag matrix in the binary flag matrix format. An error wrapping ErrIncompatibleArtifact is returned
+// if nodeCount, edgeCount, fingerprint or edgeOrder do not match the graph the flags have been computed for, or if partitions
+// does not match the hash of the partition assignment.
+func DecodeFlagMatrix(r io.Reader, nodeCount int, edgeCount int, fingerprint uint64, edgeOrder uint64, partitions uint64) (*g.FlagMatrix, *Metadata, error) {
+	reader := &checksumReader{reader: bufio.NewReader(r)}
+
+	var header flagMatrixHeader
+	if err := binary.Read(reader, binary.LittleEndian, &header); err != nil {
+		return nil, nil, fmt.Errorf("flag matrix: %w", err)
+	}
+	if header.Magic != flagMatrixMagic {
+		return nil, nil, errors.New("flag matrix: not a flag matrix file")
+	}
+	if header.Version != flagMatrixVersion {
+		return nil, nil, fmt.Errorf("flag matrix: unsupported version %d", header.Version)
+	}
+	if header.NodeCount != uint64(nodeCount) || header.EdgeCount != uint64(edgeCount) || header.Fingerprint != fingerprint || header.EdgeOrder != edgeOrder {
+		return nil, nil, fmt.Errorf("flag matrix: %w", ErrIncompatibleArtifact)
+	}
+	if header.Partitions != partitions {
+		return nil, nil, fmt.Errorf("flag matrix: %w (different partition assignment)", ErrIncompatibleArtifact)
+	}
+	if header.Width < 1 || header.Width > 1<<16-1 {
+		return nil, nil, fmt.Errorf("flag matrix: invalid width %d", header.Width)
+	}
+
+	var length uint32
+	if err := binary.Read(reader, binary.LittleEndian, &length); err != nil {
+		return nil, nil, fmt.Errorf("flag matrix: %w", err)
+	}
+	if length > 1<<20 {
+		return nil, nil, fmt.Errorf("flag matrix: invalid metadata length %d", length)
+	}
+	encodedMetadata := make([]byte, length)
+	if _, err := io.ReadFull(reader, encodedMetadata); err != nil {
+		return nil, nil, fmt.Errorf("flag matrix: %w", err)
+	}
+	metadata := &Metadata{}
+	if err := json.Unmarshal(encodedMetadata, metadata); err != nil {
+		return nil, nil, fmt.Errorf("flag matrix: invalid metadata: %w", err)
+	}
+
+	matrix := g.NewFlagMatrix(edgeCount, int(header.Width))
+	buffer := make([]byte, 8)
+	for i := range matrix.Bits {
+		if _, err := io.ReadFull(reader, buffer); err != nil {
+			return nil, nil, fmt.Errorf("flag matrix: %w", err)
+		}
+		matrix.Bits[i] = binary.LittleEndian.Uint64(buffer)
+	}
+
+	checksum := reader.Sum64()
+	if _, err := io.ReadFull(reader.reader, buffer); err != nil {
+		return nil, nil, fmt.Errorf("flag matrix: %w", err)
+	}
+	if binary.LittleEndian.Uint64(buffer) != checksum {
+		return nil, nil, errors.New("flag matrix: checksum mismatch")
+	}
+	return matrix, metadata, nil
+}
+
+// edgeOrder computes a hash of the heads of all edges in the order of the adjacency array, which identifies the rows of a flag matrix.
+// In contrast to graph.Fingerprint, the hash changes if the edges that leave a node are reordered.
+func edgeOrder[N any, E g.IHalfEdge](graph g.Graph[N, E]) uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 4096)
+	position := 0
+	for id := 0; id < graph.NodeCount(); id++ {
+		for _, edge := range graph.GetHalfEdgesFrom(id) {
+			binary.LittleEndian.PutUint64(buffer[position:], uint64(edge.To()))
+			position += 8
+			if position == len(buffer) {
+				hash.Write(buffer)
+				position = 0
+			}
+		}
+	}
+	hash.Write(buffer[:position])
+	return hash.Sum64()
+}
+
+// partitionHash computes a hash of the partitions of all nodes in the order of the node IDs, which identifies the columns of a flag matrix.
+func partitionHash(assignment g.PartitionAssignment) uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 4096)
+	position := 0
+	for _, partition := range assignment {
+		binary.LittleEndian.PutUint16(buffer[position:], uint16(partition))
+		position += 2
+		if position == len(buffer) {
+			hash.Write(buffer)
+			position = 0
+		}
+	}
+	hash.Write(buffer[:position])
+	return hash.Sum64()
+}
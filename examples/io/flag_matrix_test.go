@@ -0,0 +1,88 @@
+package io
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestFlagMatrixRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	alg, err := ReadFmi(strings.NewReader(fmiGraph), GeoPointSchema, WeightedHalfEdgeSchema.Lenient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	aag := g.NewAdjacencyArrayFromGraph[g.GeoPoint, g.WeightedHalfEdge[int]](alg)
+	maag := g.NewMatrixFlaggedGraph[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, g.NewFlagMatrix(aag.EdgeCount(), 1024))
+	maag.Edges[0].AddFlag(0)
+	maag.Edges[1].AddFlag(1023)
+	maag.Edges[3].AddFlag(512)
+	matrix := maag.Edges[0].Matrix
+	assignment := g.PartitionAssignment{0, 1023, 512}
+
+	filename := filepath.Join(t.TempDir(), "arcflags.bin")
+	metadata := NewMetadata("", 0)
+	metadata.Partitioner = "manual"
+	if err := WriteFlagMatrixFile[g.GeoPoint, g.MatrixFlaggedHalfEdge[int], int](maag, assignment, matrix, metadata, filename); err != nil {
+		t.Fatal(err)
+	}
+
+	// the flags of the matrix graph can be attached to the weighted graph
+	read, readMetadata, err := ReadFlagMatrixFile[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, assignment, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Width != 1024 || !reflect.DeepEqual(read.Bits, matrix.Bits) {
+		t.Errorf("Expected flag matrix %v, got %v", matrix, read)
+	}
+	if readMetadata.Artifact != ARTIFACT_FLAG_MATRIX || readMetadata.Partitioner != "manual" {
+		t.Errorf("Unexpected metadata %v", readMetadata)
+	}
+	if readGraph := g.NewMatrixFlaggedGraph[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, read); !readGraph.Edges[1].IsFlagged(1023) || readGraph.Edges[2].IsFlagged(1023) {
+		t.Errorf("Expected flag 1023 of edge 1 only")
+	}
+
+	// reordering the edges that leave node 1 changes the rows of the matrix
+	aag.Edges[1], aag.Edges[2] = aag.Edges[2], aag.Edges[1]
+	if _, _, err := ReadFlagMatrixFile[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, assignment, filename); !errors.Is(err, ErrIncompatibleArtifact) {
+		t.Errorf("Expected incompatible artifact, got %v", err)
+	}
+	aag.Edges[1], aag.Edges[2] = aag.Edges[2], aag.Edges[1]
+
+	// the columns of the matrix refer to the partitions of the assignment
+	other := g.PartitionAssignment{0, 512, 1023}
+	if _, _, err := ReadFlagMatrixFile[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, other, filename); !errors.Is(err, ErrIncompatibleArtifact) {
+		t.Errorf("Expected incompatible artifact, got %v", err)
+	}
+
+	// flags of a modified graph are stale
+	aag.Edges[0].Weight_++
+	if _, _, err := ReadFlagMatrixFile[g.GeoPoint, g.WeightedHalfEdge[int], int](aag, assignment, filename); !errors.Is(err, ErrIncompatibleArtifact) {
+		t.Errorf("Expected incompatible artifact, got %v", err)
+	}
+}
+
+func TestFlagMatrixChecksum(t *testing.T) {
+	t.Parallel()
+
+	matrix := g.NewFlagMatrix(2, 100)
+	matrix.Set(1, 99)
+	var buffer bytes.Buffer
+	if err := EncodeFlagMatrix(matrix, 2, 7, 13, Metadata{BaseGraph: 42}, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	data := buffer.Bytes()
+	if _, _, err := DecodeFlagMatrix(bytes.NewReader(data), 2, 2, 42, 7, 13); err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-9] ^= 1
+	if _, _, err := DecodeFlagMatrix(bytes.NewReader(data), 2, 2, 42, 7, 13); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("Expected checksum mismatch, got %v", err)
+	}
+}
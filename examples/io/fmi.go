@@ -2,97 +2,143 @@ package io
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"runtime"
 	"strconv"
+	"strings"
 
 	g "github.com/dmholtz/graffiti/graph"
 )
 
-// fmi parse states
-const (
-	PARSE_NODE_COUNT = iota
-	PARSE_EDGE_COUNT = iota
-	PARSE_NODES      = iota
-	PARSE_EDGES      = iota
-)
-
 // Build an AdjacencyListGraph from an .fmi file.
-// nodeParseFnc parses a line of the .fmi file and returns a (nodeId, node) tuple
-// edgeParseFnc parses a line of the .fmi file and returns a (nodeId, halfEdge) tuple
-func NewAdjacencyListFromFmi[N any, E g.IHalfEdge](filename string, nodeParseFnc func(line string) (int, N), edgeParseFnc func(line string) (int, E)) *g.AdjacencyListGraph[N, E] {
-
-	file, err := os.Open(filename)
+// nodeParseFnc parses a line of the .fmi file and returns a (nodeId, node, error) tuple
+// edgeParseFnc parses a line of the .fmi file and returns a (nodeId, halfEdge, error) tuple
+//
+// The function terminates the program if the file cannot be read or a line cannot be parsed. New code should prefer ReadFmi, which reports errors.
+func NewAdjacencyListFromFmi[N any, E g.IHalfEdge](filename string, nodeParseFnc func(line string) (int, N, error), edgeParseFnc func(line string) (int, E, error)) *g.AdjacencyListGraph[N, E] {
+	file, err := OpenFile(filename)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	scanner.Split(bufio.ScanLines)
+	reader := newFmiReader(file, nodeParseFnc, edgeParseFnc)
+	alg, err := readAdjacencyList(reader, runtime.NumCPU())
+	if err != nil {
+		log.Fatal(fmt.Errorf("%s: %w", filename, err))
+	}
+	return alg
+}
 
-	numNodes := 0
-	numParsedNodes := 0
+// Serialize a graph into the .fmi format
+// node2Fmi outputs a string description of a node in the .fmi format
+// edge2Fmi outputs a string description of a half edge in the .fmi format
+//
+// The function terminates the program if the file cannot be written. New code should prefer WriteFmiTo, which reports errors.
+func WriteFmi[N any, E g.IHalfEdge](graph g.Graph[N, E], filename string, node2Fmi func(id g.NodeId, node N) string, edge2Fmi func(from g.NodeId, halfEdge E) string) {
+	err := writeFile(filename, func(w io.Writer) error {
+		return writeFmi(graph, w, node2Fmi, edge2Fmi)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
 
-	alg := g.AdjacencyListGraph[N, E]{}
-	id2index := make(map[int]int)
+// ReadFmiFile reads a possibly gzip- or zstd-compressed .fmi file, whose lines are parsed on all CPUs, see ReadFmiParallel.
+func ReadFmiFile[N any, E g.IHalfEdge](filename string, nodeSchema *Schema[N], edgeSchema *Schema[E]) (*g.AdjacencyListGraph[N, E], error) {
+	file, err := OpenFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	alg, err := ReadFmiParallel(file, nodeSchema, edgeSchema, runtime.NumCPU())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return alg, nil
+}
 
-	parseState := PARSE_NODE_COUNT
-	for scanner.Scan() {
-		line := scanner.Text()
-		if len(line) < 1 {
-			// skip empty lines
-			continue
-		} else if line[0] == '#' {
-			// skip comments
-			continue
+// WriteFmiArtifactFile writes a preprocessed graph, e.g. a graph with arc flags, into an .fmi file whose first line contains the metadata.
+// The content hash of the graph is added to the metadata, such that ReadFmiArtifactFile detects modified or truncated files.
+func WriteFmiArtifactFile[N any, E g.IHalfEdge](graph g.Graph[N, E], filename string, nodeSchema *Schema[N], edgeSchema *Schema[E], metadata Metadata) error {
+	metadata.Content = g.ContentHash[N, E](graph)
+	return writeFile(filename, func(w io.Writer) error {
+		if _, err := io.WriteString(w, fmiMetadataPrefix+metadata.String()+"\n"); err != nil {
+			return err
 		}
+		return WriteFmiTo(graph, w, nodeSchema, edgeSchema)
+	})
+}
 
-		switch parseState {
-		case PARSE_NODE_COUNT:
-			if val, err := strconv.Atoi(line); err == nil {
-				numNodes = val
-				parseState = PARSE_EDGE_COUNT
-			}
-		case PARSE_EDGE_COUNT:
-			parseState = PARSE_NODES
-		case PARSE_NODES:
-			id, node := nodeParseFnc(line)
-			id2index[id] = alg.NodeCount()
-			alg.AppendNode(node)
-			numParsedNodes++
-			if numParsedNodes == numNodes {
-				parseState = PARSE_EDGES
-			}
-		case PARSE_EDGES:
-			from, edge := edgeParseFnc(line)
-			alg.InsertHalfEdge(id2index[from], edge)
-		}
+// ReadFmiArtifactFile reads a preprocessed graph written by WriteFmiArtifactFile and verifies its content hash.
+// baseGraph is the fingerprint of the graph the artifact is expected to be computed for (see graph.Fingerprint);
+// an error wrapping ErrIncompatibleArtifact is returned if the metadata refers to a different base graph.
+func ReadFmiArtifactFile[N any, E g.IHalfEdge](filename string, nodeSchema *Schema[N], edgeSchema *Schema[E], baseGraph uint64) (*g.AdjacencyListGraph[N, E], *Metadata, error) {
+	file, err := OpenFile(filename)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	if alg.NodeCount() != numNodes {
-		// cannot check edge count because ocean.fmi contains duplicates, which are removed during import
-		panic("Invalid parsing result")
+	defer file.Close()
+	reader := NewFmiReader(file, nodeSchema, edgeSchema)
+	alg, err := readAdjacencyList(reader, runtime.NumCPU())
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
 	}
-
-	if err := scanner.Err(); err != nil {
-		log.Fatal(err)
+	if reader.Metadata == nil {
+		return nil, nil, fmt.Errorf("%s: missing metadata", filename)
+	}
+	if hash := g.ContentHash[N, E](alg); hash != reader.Metadata.Content {
+		return nil, nil, fmt.Errorf("%s: content hash %016x does not match the metadata (%016x)", filename, hash, reader.Metadata.Content)
+	}
+	if err := reader.Metadata.CheckBaseGraph(baseGraph); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
 	}
+	return alg, reader.Metadata, nil
+}
 
-	return &alg
+// ReadFmi reads a graph in the .fmi format, whose node and edge lines are described by the given schemas.
+// Duplicate edges (same tail and head) are ignored, such that the edge count of the graph may be lower than declared in the file.
+// Errors are of type *ParseError, unless reading from r fails.
+func ReadFmi[N any, E g.IHalfEdge](r io.Reader, nodeSchema *Schema[N], edgeSchema *Schema[E]) (*g.AdjacencyListGraph[N, E], error) {
+	return readAdjacencyList(NewFmiReader(r, nodeSchema, edgeSchema), 1)
 }
 
-// Serialize a graph into the .fmi format
-// node2Fmi outputs a string description of a node in the .fmi format
-// edge2Fmi outputs a string description of a half edge in the .fmi format
-func WriteFmi[N any, E g.IHalfEdge](graph g.Graph[N, E], filename string, node2Fmi func(id g.NodeId, node N) string, edge2Fmi func(from g.NodeId, halfEdge E) string) {
-	file, cErr := os.Create(filename)
+// ReadFmiParallel reads a graph in the .fmi format like ReadFmi, but parses the node and edge lines in chunks on the given number of workers.
+// Lines are read sequentially and inserted in file order, such that the graph and the reported errors are identical to ReadFmi.
+func ReadFmiParallel[N any, E g.IHalfEdge](r io.Reader, nodeSchema *Schema[N], edgeSchema *Schema[E], workers int) (*g.AdjacencyListGraph[N, E], error) {
+	return readAdjacencyList(NewFmiReader(r, nodeSchema, edgeSchema), workers)
+}
 
-	if cErr != nil {
-		log.Fatal(cErr)
-	}
-	writer := bufio.NewWriter(file)
+// WriteFmiFile writes a graph into an .fmi file, see WriteFmiTo.
+func WriteFmiFile[N any, E g.IHalfEdge](graph g.Graph[N, E], filename string, nodeSchema *Schema[N], edgeSchema *Schema[E]) error {
+	return writeFile(filename, func(w io.Writer) error {
+		return WriteFmiTo(graph, w, nodeSchema, edgeSchema)
+	})
+}
+
+// WriteFmiTo writes a graph in the .fmi format, whose node and edge lines are described by the given schemas.
+func WriteFmiTo[N any, E g.IHalfEdge](graph g.Graph[N, E], w io.Writer, nodeSchema *Schema[N], edgeSchema *Schema[E]) error {
+	var sb strings.Builder
+	return writeFmi(graph, w,
+		func(id g.NodeId, node N) string {
+			sb.Reset()
+			nodeSchema.appendFormatted(&sb, id, node)
+			sb.WriteByte('\n')
+			return sb.String()
+		},
+		func(from g.NodeId, edge E) string {
+			sb.Reset()
+			edgeSchema.appendFormatted(&sb, from, edge)
+			sb.WriteByte('\n')
+			return sb.String()
+		})
+}
+
+func writeFmi[N any, E g.IHalfEdge](graph g.Graph[N, E], w io.Writer, node2Fmi func(id g.NodeId, node N) string, edge2Fmi func(from g.NodeId, halfEdge E) string) error {
+	writer := bufio.NewWriter(w)
 
 	// write number of nodes and number of edges
 	writer.WriteString(fmt.Sprintf("%d\n", graph.NodeCount()))
@@ -107,74 +153,292 @@ func WriteFmi[N any, E g.IHalfEdge](graph g.Graph[N, E], filename string, node2F
 	// list all edges structured as "fromId targetId distance" and append additional information such as arcflags
 	for id := 0; id < graph.NodeCount(); id++ {
 		for _, halfEdge := range graph.GetHalfEdgesFrom(id) {
-			writer.WriteString(edge2Fmi(id, halfEdge))
+			if _, err := writer.WriteString(edge2Fmi(id, halfEdge)); err != nil {
+				return err
+			}
 		}
 	}
 
-	writer.Flush()
+	return writer.Flush()
 }
 
-// Parsing functions
+// readAdjacencyList reads all nodes and edges into an AdjacencyListGraph. Lines are parsed in parallel iff workers > 1.
+func readAdjacencyList[N any, E g.IHalfEdge](reader *FmiReader[N, E], workers int) (*g.AdjacencyListGraph[N, E], error) {
+	if err := reader.ReadHeader(); err != nil {
+		return nil, err
+	}
+	alg := g.AdjacencyListGraph[N, E]{Nodes: make([]N, 0, reader.NodeCount), Edges: make([][]E, 0, reader.NodeCount)}
+	if workers > 1 {
+		if err := reader.readParallel(workers, func(node N) { alg.AppendNode(node) }, alg.InsertHalfEdge); err != nil {
+			return nil, err
+		}
+		return &alg, nil
+	}
+	for {
+		_, node, err := reader.ReadNode()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		alg.AppendNode(node)
+	}
+	for {
+		from, edge, err := reader.ReadEdge()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		alg.InsertHalfEdge(from, edge)
+	}
+	return &alg, nil
+}
 
-func ParseGeoPoint(line string) (int, g.GeoPoint) {
+// ParseError reports an invalid line of a text file.
+type ParseError struct {
+	Format string // name of the file format
+	Line   int    // number of the invalid line, starting at 1
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: line %d: %v", e.Format, e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// FmiReader reads a graph in the .fmi format line by line, such that the graph does not have to be kept in memory as a whole.
+//
+// An .fmi file consists of the number of nodes, the number of edges, a line per node and a line per edge.
+// Empty lines and lines starting with '#' are ignored, except for the metadata comment of preprocessed graphs (see Metadata).
+// Lines are not limited in length.
+// The reader validates that the file contains exactly the declared number of nodes and edges, that node IDs are consecutive
+// and start at 0, and that edges connect existing nodes.
+type FmiReader[N any, E g.IHalfEdge] struct {
+	NodeCount int       // declared number of nodes, available after ReadHeader
+	EdgeCount int       // declared number of edges, available after ReadHeader
+	Metadata  *Metadata // metadata of preprocessed graphs, available after ReadHeader, nil if the file has no metadata
+
+	reader    *bufio.Reader
+	parseNode func(line string) (int, N, error)
+	parseEdge func(line string) (int, E, error)
+	line      int // number of the last line read
+	nodesRead int
+	edgesRead int
+	done      bool
+}
+
+// NewFmiReader creates a reader, whose node and edge lines are described by the given schemas.
+// If the node schema has no ID column, the nodes are numbered in the order of their lines. The edge schema requires a 'from' column.
+func NewFmiReader[N any, E g.IHalfEdge](r io.Reader, nodeSchema *Schema[N], edgeSchema *Schema[E]) *FmiReader[N, E] {
+	parseEdge := edgeSchema.ParseLine
+	if !edgeSchema.HasId() {
+		parseEdge = func(line string) (int, E, error) {
+			var edge E
+			return -1, edge, errors.New("edge schema has no 'from' column")
+		}
+	}
+	return newFmiReader(r, nodeSchema.ParseLine, parseEdge)
+}
+
+func newFmiReader[N any, E g.IHalfEdge](r io.Reader, parseNode func(line string) (int, N, error), parseEdge func(line string) (int, E, error)) *FmiReader[N, E] {
+	return &FmiReader[N, E]{NodeCount: -1, EdgeCount: -1, reader: bufio.NewReader(r), parseNode: parseNode, parseEdge: parseEdge}
+}
+
+// ReadHeader reads the number of nodes and edges.
+func (fr *FmiReader[N, E]) ReadHeader() error {
+	for _, count := range []*int{&fr.NodeCount, &fr.EdgeCount} {
+		line, err := fr.nextLine()
+		if err == io.EOF {
+			return fr.errorf("unexpected end of file, expected node and edge count")
+		} else if err != nil {
+			return err
+		}
+		if *count, err = strconv.Atoi(strings.TrimSpace(line)); err != nil || *count < 0 {
+			return fr.errorf("invalid count '%s'", strings.TrimSpace(line))
+		}
+	}
+	return nil
+}
+
+// ReadNode reads the next node and returns its ID. It returns io.EOF after the declared number of nodes has been read.
+func (fr *FmiReader[N, E]) ReadNode() (g.NodeId, N, error) {
+	var node N
+	if fr.nodesRead == fr.NodeCount {
+		return -1, node, io.EOF
+	}
+	line, err := fr.nextLine()
+	if err == io.EOF {
+		return -1, node, fr.errorf("unexpected end of file after %d out of %d nodes", fr.nodesRead, fr.NodeCount)
+	} else if err != nil {
+		return -1, node, err
+	}
+	id, node, err := fr.parseNode(line)
+	if err != nil {
+		return -1, node, fr.wrap(err)
+	}
+	if id, err = checkNodeId(id, fr.nodesRead); err != nil {
+		return -1, node, fr.wrap(err)
+	}
+	fr.nodesRead++
+	return id, node, nil
+}
+
+// ReadEdge reads the next edge and returns its tail. It returns io.EOF after the declared number of edges has been read
+// and fails if the file contains additional lines.
+func (fr *FmiReader[N, E]) ReadEdge() (g.NodeId, E, error) {
+	var edge E
+	if fr.nodesRead != fr.NodeCount {
+		return -1, edge, errors.New("fmi: edges must be read after all nodes")
+	}
+	if fr.edgesRead == fr.EdgeCount {
+		if !fr.done {
+			if _, err := fr.nextLine(); err != io.EOF {
+				if err != nil {
+					return -1, edge, err
+				}
+				return -1, edge, fr.errorf("unexpected line after %d edges", fr.EdgeCount)
+			}
+			fr.done = true
+		}
+		return -1, edge, io.EOF
+	}
+	line, err := fr.nextLine()
+	if err == io.EOF {
+		return -1, edge, fr.errorf("unexpected end of file after %d out of %d edges", fr.edgesRead, fr.EdgeCount)
+	} else if err != nil {
+		return -1, edge, err
+	}
+	from, edge, err := fr.parseEdge(line)
+	if err != nil {
+		return -1, edge, fr.wrap(err)
+	}
+	if err := checkEdge(from, edge, fr.NodeCount); err != nil {
+		return -1, edge, fr.wrap(err)
+	}
+	fr.edgesRead++
+	return from, edge, nil
+}
+
+// nextLine returns the next line that is neither empty nor a comment.
+func (fr *FmiReader[N, E]) nextLine() (string, error) {
+	for {
+		line, err := fr.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+		fr.line++
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) > 0 && line[0] != '#' && strings.TrimSpace(line) != "" {
+			return line, nil
+		}
+		metadata, err := parseMetadataComment(line)
+		if err != nil {
+			return "", fr.wrap(err)
+		} else if metadata != nil {
+			fr.Metadata = metadata
+		}
+	}
+}
+
+// checkNodeId validates the ID of the i-th node. Nodes without an ID column are numbered in the order of their lines.
+func checkNodeId(id int, i int) (g.NodeId, error) {
+	if id == -1 {
+		return i, nil
+	} else if id != i {
+		return -1, fmt.Errorf("expected node ID %d, got %d", i, id)
+	}
+	return id, nil
+}
+
+// checkEdge validates that an edge connects existing nodes.
+func checkEdge[E g.IHalfEdge](from g.NodeId, edge E, nodeCount int) error {
+	if from < 0 || from >= nodeCount || edge.To() < 0 || edge.To() >= nodeCount {
+		return fmt.Errorf("edge from node %d to node %d refers to a node outside of [0, %d)", from, edge.To(), nodeCount)
+	}
+	return nil
+}
+
+func (fr *FmiReader[N, E]) errorf(format string, args ...interface{}) error {
+	return fr.wrap(fmt.Errorf(format, args...))
+}
+
+func (fr *FmiReader[N, E]) wrap(err error) error {
+	return &ParseError{Format: "fmi", Line: fr.line, Err: err}
+}
+
+// Parsing functions, which return the error of fmt.Sscanf iff a line does not contain all values
+
+func ParseGeoPoint(line string) (int, g.GeoPoint, error) {
 	var id int
 	var lat, lon float64
-	fmt.Sscanf(line, "%d %f %f", &id, &lat, &lon)
-	return id, g.GeoPoint{Lon: lon, Lat: lat}
+	_, err := fmt.Sscanf(line, "%d %f %f", &id, &lat, &lon)
+	return id, g.GeoPoint{Lon: lon, Lat: lat}, err
 }
 
-func ParsePartGeoPoint(line string) (int, g.PartGeoPoint) {
+func ParsePartGeoPoint(line string) (int, g.PartGeoPoint, error) {
 	var id int
 	var lat, lon float64
 	var part g.PartitionId
-	fmt.Sscanf(line, "%d %f %f %d", &id, &lat, &lon, &part)
-	return id, g.PartGeoPoint{GeoPoint: g.GeoPoint{Lon: lon, Lat: lat}, Partition_: part}
+	_, err := fmt.Sscanf(line, "%d %f %f %d", &id, &lat, &lon, &part)
+	return id, g.PartGeoPoint{GeoPoint: g.GeoPoint{Lon: lon, Lat: lat}, Partition_: part}, err
 }
 
-func Parse2LPartGeoPoint(line string) (int, g.TwoLevelPartGeoPoint) {
+func Parse2LPartGeoPoint(line string) (int, g.TwoLevelPartGeoPoint, error) {
 	var id int
 	var lat, lon float64
 	var l1Part, l2Part g.PartitionId
-	fmt.Sscanf(line, "%d %f %f %d %d", &id, &lat, &lon, &l1Part, &l2Part)
-	return id, g.TwoLevelPartGeoPoint{GeoPoint: g.GeoPoint{Lon: lon, Lat: lat}, L1Part_: l1Part, L2Part_: l2Part}
+	_, err := fmt.Sscanf(line, "%d %f %f %d %d", &id, &lat, &lon, &l1Part, &l2Part)
+	return id, g.TwoLevelPartGeoPoint{GeoPoint: g.GeoPoint{Lon: lon, Lat: lat}, L1Part_: l1Part, L2Part_: l2Part}, err
 }
 
-func ParseWeightedHalfEdge(line string) (int, g.WeightedHalfEdge[int]) {
+func ParseWeightedHalfEdge(line string) (int, g.WeightedHalfEdge[int], error) {
 	var from, to, weight int
-	fmt.Sscanf(line, "%d %d %d", &from, &to, &weight)
-	return from, g.WeightedHalfEdge[int]{To_: to, Weight_: weight}
+	_, err := fmt.Sscanf(line, "%d %d %d", &from, &to, &weight)
+	return from, g.WeightedHalfEdge[int]{To_: to, Weight_: weight}, err
 }
 
-func ParseFlaggedHalfEdge(line string) (int, g.FlaggedHalfEdge[int, uint64]) {
+func ParseFlaggedHalfEdge(line string) (int, g.FlaggedHalfEdge[int, uint64], error) {
 	var from, to, weight int
 	var flag uint64
-	fmt.Sscanf(line, "%d %d %d %d", &from, &to, &weight, &flag)
-	return from, g.FlaggedHalfEdge[int, uint64]{To_: to, Weight_: weight, Flag: flag}
+	_, err := fmt.Sscanf(line, "%d %d %d %d", &from, &to, &weight, &flag)
+	return from, g.FlaggedHalfEdge[int, uint64]{To_: to, Weight_: weight, Flag: flag}, err
 }
 
-func ParseLargeFlaggedHalfEdge(line string) (int, g.LargeFlaggedHalfEdge[int]) {
+func ParseLargeFlaggedHalfEdge(line string) (int, g.LargeFlaggedHalfEdge[int], error) {
 	var from, to, weight int
 	var msbFlag, lsbFlag uint64
-	fmt.Sscanf(line, "%d %d %d %d %d", &from, &to, &weight, &msbFlag, &lsbFlag)
-	return from, g.LargeFlaggedHalfEdge[int]{To_: to, Weight_: weight, MsbFlag: msbFlag, LsbFlag: lsbFlag}
+	_, err := fmt.Sscanf(line, "%d %d %d %d %d", &from, &to, &weight, &msbFlag, &lsbFlag)
+	return from, g.LargeFlaggedHalfEdge[int]{To_: to, Weight_: weight, MsbFlag: msbFlag, LsbFlag: lsbFlag}, err
 }
 
-func Parse256BitFlaggedHalfEdge(line string) (int, g.B256FlaggedHalfEdge[int]) {
+func Parse256BitFlaggedHalfEdge(line string) (int, g.B256FlaggedHalfEdge[int], error) {
 	var from, to, weight int
 	var f1, f2, f3, f4 uint64
-	fmt.Sscanf(line, "%d %d %d %d %d %d %d", &from, &to, &weight, &f1, &f2, &f3, &f4)
-	return from, g.B256FlaggedHalfEdge[int]{To_: to, Weight_: weight, Flag_: [4]uint64{f1, f2, f3, f4}}
+	_, err := fmt.Sscanf(line, "%d %d %d %d %d %d %d", &from, &to, &weight, &f1, &f2, &f3, &f4)
+	return from, g.B256FlaggedHalfEdge[int]{To_: to, Weight_: weight, Flag_: [4]uint64{f1, f2, f3, f4}}, err
 }
 
-func Parse2LFlaggedHalfEdge(line string) (int, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]) {
+func Parse2LFlaggedHalfEdge(line string) (int, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64], error) {
 	var from, to, weight int
 	var l1Flag, l2Flag uint64
-	fmt.Sscanf(line, "%d %d %d %d %d", &from, &to, &weight, &l1Flag, &l2Flag)
-	return from, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]{To_: to, Weight_: weight, L1Flag: l1Flag, L2Flag: l2Flag}
+	_, err := fmt.Sscanf(line, "%d %d %d %d %d", &from, &to, &weight, &l1Flag, &l2Flag)
+	return from, g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]{To_: to, Weight_: weight, L1Flag: l1Flag, L2Flag: l2Flag}, err
 }
 
 // Printer functions
 
+func GeoPoint2FmiLine(id g.NodeId, node g.GeoPoint) string {
+	return fmt.Sprintf("%d %f %f\n", id, node.Lat, node.Lon)
+}
+
+func WeightedHalfEdge2FmiLine(from g.NodeId, edge g.WeightedHalfEdge[int]) string {
+	return fmt.Sprintf("%d %d %d\n", from, edge.To_, edge.Weight_)
+}
+
 func PartGeoPoint2FmiLine(id g.NodeId, node g.PartGeoPoint) string {
 	return fmt.Sprintf("%d %f %f %d\n", id, node.Lat, node.Lon, node.Partition_)
 }
@@ -0,0 +1,142 @@
+package io
+
+import (
+	"fmt"
+	"io"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// fmiChunkSize is the number of lines that are parsed by a worker at once.
+const fmiChunkSize = 1 << 13
+
+// fmiChunk is a sequence of consecutive node or edge lines.
+type fmiChunk[T any] struct {
+	lines    []string
+	numbers  []int // line numbers
+	ids      []int // parsed IDs or tails
+	values   []T   // parsed nodes or edges
+	err      error // parse error of the line after the last parsed value
+	readErr  error // error that terminated reading after the last line, e.g. io.EOF
+	readLine int
+	parsed   chan struct{}
+}
+
+// parse parses the lines of the chunk until the first invalid line.
+func (c *fmiChunk[T]) parse(parseFnc func(line string) (int, T, error)) {
+	c.ids, c.values = make([]int, 0, len(c.lines)), make([]T, 0, len(c.lines))
+	for _, line := range c.lines {
+		id, value, err := parseFnc(line)
+		if err != nil {
+			c.err = err
+			break
+		}
+		c.ids, c.values = append(c.ids, id), append(c.values, value)
+	}
+	c.lines = nil
+}
+
+// readParallel reads the nodes and edges after the header, parses them on the given number of workers and passes them in file order
+// to nodeFnc and edgeFnc. It validates the lines like ReadNode and ReadEdge.
+func (fr *FmiReader[N, E]) readParallel(workers int, nodeFnc func(node N), edgeFnc func(from g.NodeId, edge E)) error {
+	next := func() (string, int, error) {
+		line, err := fr.nextLine()
+		return line, fr.line, err
+	}
+
+	err := parseChunks(next, fr.NodeCount, "nodes", workers, fr.parseNode, func(i int, id int, node N) error {
+		if _, err := checkNodeId(id, i); err != nil {
+			return err
+		}
+		nodeFnc(node)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fr.nodesRead = fr.NodeCount
+
+	err = parseChunks(next, fr.EdgeCount, "edges", workers, fr.parseEdge, func(i int, from int, edge E) error {
+		if err := checkEdge(from, edge, fr.NodeCount); err != nil {
+			return err
+		}
+		edgeFnc(from, edge)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fr.edgesRead = fr.EdgeCount
+
+	// fails if the file contains additional lines
+	if _, _, err := fr.ReadEdge(); err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// parseChunks reads count lines with nextFnc, parses chunks of lines concurrently and passes the results in order to consumeFnc.
+// Reading and consuming are sequential, such that only parsing is parallelized.
+func parseChunks[T any](nextFnc func() (string, int, error), count int, kind string, workers int,
+	parseFnc func(line string) (int, T, error), consumeFnc func(i int, id int, value T) error) error {
+	jobs := make(chan *fmiChunk[T], workers)
+	queue := make(chan *fmiChunk[T], 2*workers) // chunks in file order
+	stop := make(chan struct{})
+	defer close(stop)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			for chunk := range jobs {
+				chunk.parse(parseFnc)
+				close(chunk.parsed)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		defer close(queue)
+		for read := 0; read < count; {
+			chunk := &fmiChunk[T]{parsed: make(chan struct{})}
+			for len(chunk.lines) < fmiChunkSize && read < count {
+				line, number, err := nextFnc()
+				if err != nil {
+					chunk.readErr, chunk.readLine, read = err, number, count
+					break
+				}
+				chunk.lines, chunk.numbers = append(chunk.lines, line), append(chunk.numbers, number)
+				read++
+			}
+			select {
+			case jobs <- chunk:
+			case <-stop:
+				return
+			}
+			select {
+			case queue <- chunk:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	i := 0
+	for chunk := range queue {
+		<-chunk.parsed
+		for j, value := range chunk.values {
+			if err := consumeFnc(i, chunk.ids[j], value); err != nil {
+				return &ParseError{Format: "fmi", Line: chunk.numbers[j], Err: err}
+			}
+			i++
+		}
+		if chunk.err != nil {
+			return &ParseError{Format: "fmi", Line: chunk.numbers[len(chunk.values)], Err: chunk.err}
+		}
+		if chunk.readErr == io.EOF {
+			return &ParseError{Format: "fmi", Line: chunk.readLine, Err: fmt.Errorf("unexpected end of file after %d out of %d %s", i, count, kind)}
+		} else if chunk.readErr != nil {
+			return chunk.readErr
+		}
+	}
+	return nil
+}
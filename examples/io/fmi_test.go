@@ -0,0 +1,272 @@
+package io
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+	"github.com/klauspost/compress/zstd"
+)
+
+const fmiGraph = `# comment
+3
+4
+0 47.5 11.25
+1 -20 170
+2 0 0
+
+0 1 10 9 0 0 18446744073709551615
+1 0 10 1 2 3 4
+1 2 42 0 0 0 0
+2 1 42 0 0 0 1
+`
+
+func TestFmiRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	alg, err := ReadFmi(strings.NewReader(fmiGraph), GeoPointSchema, B256FlaggedHalfEdgeSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alg.NodeCount() != 3 || alg.EdgeCount() != 4 {
+		t.Fatalf("Expected 3 nodes and 4 edges, got %d nodes and %d edges", alg.NodeCount(), alg.EdgeCount())
+	}
+	if node := alg.GetNode(0); node.Lat != 47.5 || node.Lon != 11.25 {
+		t.Errorf("Unexpected node %v", node)
+	}
+	if edge := alg.GetHalfEdgesFrom(0)[0]; edge.To_ != 1 || edge.Weight_ != 10 || edge.Flag_ != [4]uint64{9, 0, 0, 18446744073709551615} {
+		t.Errorf("Unexpected edge %v", edge)
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteFmiTo[g.GeoPoint, g.B256FlaggedHalfEdge[int]](alg, &buffer, GeoPointSchema, B256FlaggedHalfEdgeSchema); err != nil {
+		t.Fatal(err)
+	}
+	expected := "3\n4\n0 47.5 11.25\n1 -20 170\n2 0 0\n0 1 10 9 0 0 18446744073709551615\n1 0 10 1 2 3 4\n1 2 42 0 0 0 0\n2 1 42 0 0 0 1\n"
+	if buffer.String() != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, buffer.String())
+	}
+
+	// extra columns are ignored by lenient schemas
+	weighted, err := ReadFmi(strings.NewReader(fmiGraph), GeoPointSchema, WeightedHalfEdgeSchema.Lenient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edge := weighted.GetHalfEdgesFrom(1)[1]; edge.To_ != 2 || edge.Weight_ != 42 {
+		t.Errorf("Unexpected edge %v", edge)
+	}
+}
+
+func TestFmiErrors(t *testing.T) {
+	t.Parallel()
+
+	invalid := map[string]string{
+		"":                            "line 0: unexpected end of file, expected node and edge count",
+		"2\nx\n":                      "line 2: invalid count 'x'",
+		"2\n0\n0 1 2\n":               "line 3: unexpected end of file after 1 out of 2 nodes",
+		"2\n0\n0 1 2\n2 1 2\n":        "line 4: expected node ID 1, got 2",
+		"1\n0\n0 1 x\n":               "line 3: column 'Lon': strconv.ParseFloat: parsing \"x\": invalid syntax",
+		"1\n0\n0 1 2 3\n":             "line 3: expected 3 columns, got 4",
+		"2\n1\n0 1 2\n1 1 2\n0 2 5\n": "line 5: edge from node 0 to node 2 refers to a node outside of [0, 2)",
+		"2\n2\n0 1 2\n1 1 2\n0 1 5\n": "line 5: unexpected end of file after 1 out of 2 edges",
+		"2\n1\n0 1 2\n1 1 2\n0 1 5\n# comment\n1 0 5": "line 7: unexpected line after 1 edges",
+	}
+	for input, message := range invalid {
+		_, err := ReadFmi(strings.NewReader(input), GeoPointSchema, WeightedHalfEdgeSchema)
+		var parseError *ParseError
+		if !errors.As(err, &parseError) || !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected parse error '%s', got %v", message, err)
+		}
+	}
+}
+
+func TestFmiLongLines(t *testing.T) {
+	t.Parallel()
+
+	// lines exceed the default buffer size of bufio.Scanner
+	padding := strings.Repeat(" ", 100*1024)
+	input := "#" + padding + "\n2\n1\n0 1 2" + padding + "\n1 3 4\n0 1 5\n"
+	alg, err := ReadFmi(strings.NewReader(input), GeoPointSchema, WeightedHalfEdgeSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alg.NodeCount() != 2 || alg.GetNode(0).Lon != 2 {
+		t.Errorf("Unexpected graph %v", alg)
+	}
+}
+
+// customNode demonstrates a node type without hand-written parse functions.
+type customNode struct {
+	g.GeoPoint
+	Elevation int16
+	Tags      [2]uint8
+	Port      bool
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := MustSchema[customNode](ID_COLUMN, "Lat", "Lon", "Elevation", "Tags", "Port")
+	if schema.Width() != 7 {
+		t.Errorf("Expected 7 columns, got %d", schema.Width())
+	}
+	id, node, err := schema.ParseLine("5 1.5 -2 -300 7 255 true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := customNode{GeoPoint: g.GeoPoint{Lat: 1.5, Lon: -2}, Elevation: -300, Tags: [2]uint8{7, 255}, Port: true}
+	if id != 5 || node != expected {
+		t.Errorf("Expected node 5 %v, got node %d %v", expected, id, node)
+	}
+	if line := schema.Format(id, node); line != "5 1.5 -2 -300 7 255 true" {
+		t.Errorf("Unexpected formatted line '%s'", line)
+	}
+	if _, _, err := schema.ParseLine("5 1.5 -2 -300 7 256 true"); err == nil {
+		t.Errorf("Expected overflow error")
+	}
+
+	if _, err := NewSchema[customNode]("id", "Altitude"); err == nil {
+		t.Errorf("Expected error for unknown field")
+	}
+	if _, err := NewSchema[customNode]("id", "GeoPoint"); err == nil {
+		t.Errorf("Expected error for unsupported field type")
+	}
+}
+
+func TestLegacyFmiReader(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "graph.fmi")
+	os.WriteFile(filename, []byte(fmiGraph), 0644)
+	alg := NewAdjacencyListFromFmi(filename, ParseGeoPoint, ParseWeightedHalfEdge)
+	if alg.NodeCount() != 3 || alg.EdgeCount() != 4 {
+		t.Fatalf("Expected 3 nodes and 4 edges, got %d nodes and %d edges", alg.NodeCount(), alg.EdgeCount())
+	}
+
+	// a malformed line must not become a zero-valued edge
+	malformed := strings.Replace(fmiGraph, "1 2 42", "1 2 x", 1)
+	_, err := readAdjacencyList(newFmiReader(strings.NewReader(malformed), ParseGeoPoint, ParseWeightedHalfEdge), 1)
+	var parseError *ParseError
+	if !errors.As(err, &parseError) || parseError.Line != 10 {
+		t.Errorf("Expected parse error in line 10, got %v", err)
+	}
+	if _, _, err := ParsePartGeoPoint("0 47.5 11.25"); err == nil {
+		t.Errorf("Expected error for a missing partition, got nil")
+	}
+}
+
+func TestFmiParallel(t *testing.T) {
+	t.Parallel()
+
+	// the graph spans multiple chunks
+	n := 3*fmiChunkSize + 17
+	var sb strings.Builder
+	sb.WriteString(strconv.Itoa(n) + "\n" + strconv.Itoa(2*n) + "\n")
+	for i := 0; i < n; i++ {
+		sb.WriteString(fmt.Sprintf("%d %d %d\n", i, i%90, -i%180))
+	}
+	for i := 0; i < n; i++ {
+		sb.WriteString(fmt.Sprintf("%d %d %d\n# comment\n%d %d %d\n", i, (i+1)%n, i, i, (i*7)%n, 2*i))
+	}
+	input := sb.String()
+
+	sequential, err := ReadFmi(strings.NewReader(input), GeoPointSchema, WeightedHalfEdgeSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := ReadFmiParallel(strings.NewReader(input), GeoPointSchema, WeightedHalfEdgeSchema, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqualGraphs[g.GeoPoint, g.WeightedHalfEdge[int]](t, sequential, parallel)
+
+	// errors are identical to the sequential reader
+	invalid := []string{
+		input[:len(input)-20],
+		strings.Replace(input, "\n4711 ", "\n4712 ", 1),
+		strings.Replace(input, "\n20000 20001 20000\n", "\n20000 20001 x\n", 1),
+		strings.Replace(input, "\n20000 20001 20000\n", "\n20000 99999 20000\n", 1),
+		input + "0 1 1\n",
+	}
+	for i, input := range invalid {
+		_, expected := ReadFmi(strings.NewReader(input), GeoPointSchema, WeightedHalfEdgeSchema)
+		_, err := ReadFmiParallel(strings.NewReader(input), GeoPointSchema, WeightedHalfEdgeSchema, 4)
+		if expected == nil || err == nil || err.Error() != expected.Error() {
+			t.Errorf("Input %d: expected error %v, got %v", i, expected, err)
+		}
+	}
+}
+
+func TestCompressedFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	gw.Write([]byte(fmiGraph))
+	gw.Close()
+	zw, _ := zstd.NewWriter(nil)
+	files := map[string][]byte{
+		"graph.fmi":     []byte(fmiGraph),
+		"graph.fmi.gz":  gzipped.Bytes(),
+		"graph.fmi.zst": zw.EncodeAll([]byte(fmiGraph), nil),
+	}
+	for name, data := range files {
+		filename := filepath.Join(dir, name)
+		os.WriteFile(filename, data, 0644)
+		alg, err := ReadFmiFile(filename, GeoPointSchema, WeightedHalfEdgeSchema.Lenient())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if alg.NodeCount() != 3 || alg.EdgeCount() != 4 {
+			t.Errorf("%s: expected 3 nodes and 4 edges, got %d nodes and %d edges", name, alg.NodeCount(), alg.EdgeCount())
+		}
+	}
+}
+
+func TestFmiArtifact(t *testing.T) {
+	t.Parallel()
+
+	alg, err := ReadFmi(strings.NewReader(fmiGraph), GeoPointSchema, B256FlaggedHalfEdgeSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseGraph := g.Fingerprint[g.GeoPoint, g.B256FlaggedHalfEdge[int], int](alg)
+	metadata := NewMetadata(ARTIFACT_ARC_FLAGS, baseGraph)
+	metadata.Partitioner = "grid"
+	metadata.Parameters["partitions"] = "256"
+
+	filename := filepath.Join(t.TempDir(), "arcflags.fmi")
+	if err := WriteFmiArtifactFile[g.GeoPoint, g.B256FlaggedHalfEdge[int]](alg, filename, GeoPointSchema, B256FlaggedHalfEdgeSchema, metadata); err != nil {
+		t.Fatal(err)
+	}
+	read, readMetadata, err := ReadFmiArtifactFile(filename, GeoPointSchema, B256FlaggedHalfEdgeSchema, baseGraph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.EdgeCount() != 4 || readMetadata.Partitioner != "grid" || readMetadata.Parameters["partitions"] != "256" || readMetadata.Created != metadata.Created {
+		t.Errorf("Unexpected metadata %v", readMetadata)
+	}
+	if _, _, err := ReadFmiArtifactFile(filename, GeoPointSchema, B256FlaggedHalfEdgeSchema, baseGraph+1); !errors.Is(err, ErrIncompatibleArtifact) {
+		t.Errorf("Expected incompatible artifact, got %v", err)
+	}
+
+	// readers without metadata support ignore the comment
+	if _, err := ReadFmiFile(filename, GeoPointSchema, WeightedHalfEdgeSchema.Lenient()); err != nil {
+		t.Error(err)
+	}
+
+	// modified arc flags are detected
+	data, _ := os.ReadFile(filename)
+	os.WriteFile(filename, bytes.Replace(data, []byte("0 1 10 9 "), []byte("0 1 10 8 "), 1), 0644)
+	if _, _, err := ReadFmiArtifactFile(filename, GeoPointSchema, B256FlaggedHalfEdgeSchema, baseGraph); err == nil || !strings.Contains(err.Error(), "content hash") {
+		t.Errorf("Expected content hash mismatch, got %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package io
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// FeatureCollection is the root object of a GeoJSON document (RFC 7946).
+// Features are styled according to the simplestyle specification, which is understood by viewers such as geojson.io.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON feature, i.e. a geometry with properties.
+type Feature struct {
+	Type       string                 `json:"type"`
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   Geometry               `json:"geometry"`
+}
+
+// Geometry is a GeoJSON geometry, whose coordinates are nested arrays of [longitude, latitude] positions.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// PartitionedLocator describes nodes that have a location and a partition.
+type PartitionedLocator interface {
+	g.Locator
+	g.Partitioner
+}
+
+// NewFeatureCollection creates an empty FeatureCollection.
+func NewFeatureCollection() *FeatureCollection {
+	return &FeatureCollection{Type: "FeatureCollection", Features: make([]Feature, 0)}
+}
+
+// Add appends a feature with the given geometry and properties to the collection.
+func (fc *FeatureCollection) Add(geometryType string, coordinates interface{}, properties map[string]interface{}) {
+	if properties == nil {
+		properties = make(map[string]interface{})
+	}
+	fc.Features = append(fc.Features, Feature{Type: "Feature", Properties: properties, Geometry: Geometry{Type: geometryType, Coordinates: coordinates}})
+}
+
+// PathToGeoJson exports the result of a shortest path search: the path as LineString and, if recorded, the search space as MultiPoint.
+func PathToGeoJson[N g.Locator, E g.IHalfEdge, W g.Weight](graph g.Graph[N, E], result sp.ShortestPathResult[W]) *FeatureCollection {
+	fc := NewFeatureCollection()
+	if result.SearchSpace != nil {
+		fc.Add("MultiPoint", positions(graph, result.SearchSpace), map[string]interface{}{
+			"name":          "search space",
+			"nodes":         len(result.SearchSpace),
+			"marker-color":  "#7e7e7e",
+			"marker-size":   "small",
+			"marker-symbol": "circle",
+		})
+	}
+	if len(result.Path) > 0 {
+		fc.Add("LineString", unwrap(positions(graph, result.Path)), map[string]interface{}{
+			"name":         "path",
+			"length":       result.Length,
+			"pq-pops":      result.PqPops,
+			"stroke":       "#e31a1c",
+			"stroke-width": 3,
+		})
+		fc.Add("Point", position(graph.GetNode(result.Path[0])), map[string]interface{}{"name": "source", "node": result.Path[0], "marker-color": "#33a02c"})
+		last := result.Path[len(result.Path)-1]
+		fc.Add("Point", position(graph.GetNode(last)), map[string]interface{}{"name": "target", "node": last, "marker-color": "#1f78b4"})
+	}
+	return fc
+}
+
+// PartitionsToGeoJson exports the nodes of a partitioned graph as one MultiPoint per partition. Each partition has a distinct color.
+func PartitionsToGeoJson[N PartitionedLocator, E g.IHalfEdge](graph g.Graph[N, E]) *FeatureCollection {
+	partitions := make(map[g.PartitionId][]g.NodeId)
+	for id := 0; id < graph.NodeCount(); id++ {
+		p := graph.GetNode(id).Partition()
+		partitions[p] = append(partitions[p], id)
+	}
+	ids := make([]int, 0, len(partitions))
+	for p := range partitions {
+		ids = append(ids, int(p))
+	}
+	sort.Ints(ids)
+
+	fc := NewFeatureCollection()
+	for _, id := range ids {
+		p := g.PartitionId(id)
+		fc.Add("MultiPoint", positions(graph, partitions[p]), map[string]interface{}{
+			"partition":     p,
+			"nodes":         len(partitions[p]),
+			"marker-color":  partitionColor(p),
+			"marker-size":   "small",
+			"marker-symbol": "circle",
+		})
+	}
+	return fc
+}
+
+// FlaggedEdgesToGeoJson exports the edges whose arc flag is set for the given partition as MultiLineString,
+// together with the nodes of the partition as MultiPoint.
+// Edges without the flag are exported as a separate MultiLineString iff includeUnflagged is true.
+func FlaggedEdgesToGeoJson[N PartitionedLocator, E g.IFlaggedHalfEdge[W], W g.Weight](graph g.Graph[N, E], partition g.PartitionId, includeUnflagged bool) *FeatureCollection {
+	flagged, unflagged := make([][][2]float64, 0), make([][][2]float64, 0)
+	nodes := make([]g.NodeId, 0)
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		if graph.GetNode(tail).Partition() == partition {
+			nodes = append(nodes, tail)
+		}
+		for _, edge := range graph.GetHalfEdgesFrom(tail) {
+			line := unwrap(positions(graph, []g.NodeId{tail, edge.To()}))
+			if edge.IsFlagged(partition) {
+				flagged = append(flagged, line)
+			} else if includeUnflagged {
+				unflagged = append(unflagged, line)
+			}
+		}
+	}
+
+	fc := NewFeatureCollection()
+	if includeUnflagged {
+		fc.Add("MultiLineString", unflagged, map[string]interface{}{"name": "unflagged edges", "edges": len(unflagged), "stroke": "#bdbdbd", "stroke-width": 1})
+	}
+	fc.Add("MultiLineString", flagged, map[string]interface{}{"name": "flagged edges", "partition": partition, "edges": len(flagged), "stroke": "#e31a1c", "stroke-width": 2})
+	fc.Add("MultiPoint", positions(graph, nodes), map[string]interface{}{
+		"name":          "partition",
+		"partition":     partition,
+		"nodes":         len(nodes),
+		"marker-color":  partitionColor(partition),
+		"marker-size":   "small",
+		"marker-symbol": "circle",
+	})
+	return fc
+}
+
+// WriteGeoJson writes a FeatureCollection to a .geo.json file.
+func WriteGeoJson(fc *FeatureCollection, filename string) error {
+	return writeFile(filename, func(w io.Writer) error { return EncodeGeoJson(fc, w) })
+}
+
+// EncodeGeoJson writes a FeatureCollection as indented JSON.
+func EncodeGeoJson(fc *FeatureCollection, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(fc)
+}
+
+// position returns the GeoJSON position of a node, i.e. [longitude, latitude].
+func position[N g.Locator](node N) [2]float64 {
+	location := node.Location()
+	return [2]float64{location.Lon, location.Lat}
+}
+
+func positions[N g.Locator, E g.IHalfEdge](graph g.Graph[N, E], ids []g.NodeId) [][2]float64 {
+	result := make([][2]float64, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, position(graph.GetNode(id)))
+	}
+	return result
+}
+
+// unwrap shifts the longitudes of a line by multiples of 360 degrees, such that consecutive positions differ by at most 180 degrees.
+// Hence, lines that cross the antimeridian are not drawn around the globe.
+func unwrap(line [][2]float64) [][2]float64 {
+	for i := 1; i < len(line); i++ {
+		delta := line[i][0] - line[i-1][0]
+		line[i][0] -= 360 * math.Round(delta/360)
+	}
+	return line
+}
+
+// partitionColor assigns distinct colors to consecutive partitions by rotating the hue by the golden angle.
+func partitionColor(p g.PartitionId) string {
+	hue := math.Mod(float64(p)*137.508, 360)
+	red, green, blue := hsvToRgb(hue, 0.75, 0.9)
+	return fmt.Sprintf("#%02x%02x%02x", red, green, blue)
+}
+
+// hsvToRgb converts a color from the HSV color space (hue in degrees) to 8-bit RGB values.
+func hsvToRgb(hue, saturation, value float64) (uint8, uint8, uint8) {
+	c := value * saturation
+	x := c * (1 - math.Abs(math.Mod(hue/60, 2)-1))
+	m := value - c
+	var red, green, blue float64
+	switch {
+	case hue < 60:
+		red, green, blue = c, x, 0
+	case hue < 120:
+		red, green, blue = x, c, 0
+	case hue < 180:
+		red, green, blue = 0, c, x
+	case hue < 240:
+		red, green, blue = 0, x, c
+	case hue < 300:
+		red, green, blue = x, 0, c
+	default:
+		red, green, blue = c, 0, x
+	}
+	return uint8(math.Round((red + m) * 255)), uint8(math.Round((green + m) * 255)), uint8(math.Round((blue + m) * 255))
+}
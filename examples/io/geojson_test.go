@@ -0,0 +1,60 @@
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	sp "github.com/dmholtz/graffiti/algorithms/shortest_path"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestGeoJsonExport(t *testing.T) {
+	t.Parallel()
+
+	alg := &g.AdjacencyListGraph[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]]{}
+	alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: 10, Lon: 170}, Partition_: 0})
+	alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: 11, Lon: 179}, Partition_: 0})
+	alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: 12, Lon: -175}, Partition_: 1})
+	alg.InsertHalfEdge(0, g.FlaggedHalfEdge[int, uint64]{To_: 1, Weight_: 1, Flag: 0b11})
+	alg.InsertHalfEdge(1, g.FlaggedHalfEdge[int, uint64]{To_: 2, Weight_: 1, Flag: 0b10})
+	alg.InsertHalfEdge(2, g.FlaggedHalfEdge[int, uint64]{To_: 1, Weight_: 1, Flag: 0b01})
+
+	result := sp.ShortestPathResult[int]{Length: 2, Path: []g.NodeId{0, 1, 2}, PqPops: 3, SearchSpace: []g.NodeId{0, 1, 2}}
+	path := decode(t, PathToGeoJson[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](alg, result))
+	if len(path.Features) != 4 || path.Features[0].Geometry.Type != "MultiPoint" || path.Features[1].Geometry.Type != "LineString" {
+		t.Fatalf("Unexpected features: %v", path.Features)
+	}
+	// the path crosses the antimeridian, hence the last longitude is unwrapped to 185
+	line := path.Features[1].Geometry.Coordinates.([]interface{})
+	if last := line[2].([]interface{}); last[0].(float64) != 185 || last[1].(float64) != 12 {
+		t.Errorf("Expected last position [185, 12], got %v", last)
+	}
+
+	partitions := decode(t, PartitionsToGeoJson[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64]](alg))
+	if len(partitions.Features) != 2 || partitions.Features[0].Properties["marker-color"] == partitions.Features[1].Properties["marker-color"] {
+		t.Errorf("Expected two partitions with distinct colors, got %v", partitions.Features)
+	}
+
+	flagged := decode(t, FlaggedEdgesToGeoJson[g.PartGeoPoint, g.FlaggedHalfEdge[int, uint64], int](alg, 1, true))
+	counts := []float64{1, 2, 1} // unflagged edges, flagged edges, nodes of partition 1
+	keys := []string{"edges", "edges", "nodes"}
+	for i, feature := range flagged.Features {
+		if feature.Properties[keys[i]] != counts[i] {
+			t.Errorf("Feature %d: expected %v %s, got %v", i, counts[i], keys[i], feature.Properties[keys[i]])
+		}
+	}
+}
+
+// decode encodes a FeatureCollection and decodes it again, such that coordinates are generic JSON values.
+func decode(t *testing.T, fc *FeatureCollection) FeatureCollection {
+	var buffer bytes.Buffer
+	if err := EncodeGeoJson(fc, &buffer); err != nil {
+		t.Fatal(err)
+	}
+	var decoded FeatureCollection
+	if err := json.Unmarshal(buffer.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
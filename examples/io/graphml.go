@@ -0,0 +1,247 @@
+package io
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// WriteGraphMLFile writes a graph into a .graphml file, see WriteGraphML.
+func WriteGraphMLFile[N any, E g.IHalfEdge](graph g.Graph[N, E], filename string, nodeAttributes *AttributeMap[N], edgeAttributes *AttributeMap[E]) error {
+	return writeFile(filename, func(w io.Writer) error {
+		return WriteGraphML(graph, w, nodeAttributes, edgeAttributes)
+	})
+}
+
+// WriteGraphML writes a graph as directed GraphML document, which can be opened with networkx (read_graphml), Gephi or yEd.
+// Node IDs are written as decimal numbers. Attributes are declared as GraphML keys, where bitsets and unsigned 64-bit integers
+// are strings, since GraphML has no unsigned integer type.
+func WriteGraphML[N any, E g.IHalfEdge](graph g.Graph[N, E], w io.Writer, nodeAttributes *AttributeMap[N], edgeAttributes *AttributeMap[E]) error {
+	writer := bufio.NewWriter(w)
+	writer.WriteString(xml.Header)
+	writer.WriteString("<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+
+	// keys of the node attributes are d0, d1, ..., followed by the keys of the edge attributes
+	nodeKeys := writeGraphMLKeys(writer, "node", 0, nodeAttributes.Names(), nodeAttributes.graphMLTypes())
+	edgeKeys := writeGraphMLKeys(writer, "edge", len(nodeKeys), edgeAttributes.Names(), edgeAttributes.graphMLTypes())
+
+	writer.WriteString("  <graph edgedefault=\"directed\">\n")
+	for id := 0; id < graph.NodeCount(); id++ {
+		writer.WriteString(fmt.Sprintf("    <node id=\"%d\">", id))
+		writeGraphMLData(writer, nodeKeys, nodeAttributes.Values(graph.GetNode(id)))
+		writer.WriteString("</node>\n")
+	}
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		for _, edge := range graph.GetHalfEdgesFrom(tail) {
+			writer.WriteString(fmt.Sprintf("    <edge source=\"%d\" target=\"%d\">", tail, edge.To()))
+			writeGraphMLData(writer, edgeKeys, edgeAttributes.Values(edge))
+			if _, err := writer.WriteString("</edge>\n"); err != nil {
+				return err
+			}
+		}
+	}
+	writer.WriteString("  </graph>\n</graphml>\n")
+	return writer.Flush()
+}
+
+func writeGraphMLKeys(writer *bufio.Writer, domain string, offset int, names []string, types []string) []string {
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = "d" + strconv.Itoa(offset+i)
+		writer.WriteString(fmt.Sprintf("  <key id=\"%s\" for=\"%s\" attr.name=\"", keys[i], domain))
+		xml.EscapeText(writer, []byte(name))
+		writer.WriteString(fmt.Sprintf("\" attr.type=\"%s\"/>\n", types[i]))
+	}
+	return keys
+}
+
+func writeGraphMLData(writer *bufio.Writer, keys []string, values []string) {
+	for i, value := range values {
+		writer.WriteString("<data key=\"" + keys[i] + "\">")
+		xml.EscapeText(writer, []byte(value))
+		writer.WriteString("</data>")
+	}
+}
+
+// graphMLKey is the declaration of an attribute.
+type graphMLKey struct {
+	Id      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr"`
+	Default *string `xml:"default"`
+}
+
+// graphMLElement is a node or an edge.
+type graphMLElement struct {
+	Id       string        `xml:"id,attr"`
+	Source   string        `xml:"source,attr"`
+	Target   string        `xml:"target,attr"`
+	Directed string        `xml:"directed,attr"`
+	Data     []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ReadGraphMLFile reads a .graphml file, see ReadGraphML.
+func ReadGraphMLFile[N any, E g.IRetargetableHalfEdge](filename string, nodeAttributes *AttributeMap[N], edgeAttributes *AttributeMap[E]) (*g.AdjacencyListGraph[N, E], error) {
+	file, err := OpenFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	alg, err := ReadGraphML(file, nodeAttributes, edgeAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return alg, nil
+}
+
+// ReadGraphML reads the first graph of a GraphML document, e.g. as written by networkx (write_graphml) or Gephi.
+//
+// Attributes are identified by the attr.name of their keys. Missing data elements fall back to the default value of the key.
+// Node IDs are arbitrary strings, which are mapped to consecutive NodeIds in document order.
+// Undirected edges (edgedefault="undirected" or directed="false") are inserted in both directions.
+// Duplicate edges (same tail and head) are ignored. Nested graphs, hyperedges and ports are not supported.
+func ReadGraphML[N any, E g.IRetargetableHalfEdge](r io.Reader, nodeAttributes *AttributeMap[N], edgeAttributes *AttributeMap[E]) (*g.AdjacencyListGraph[N, E], error) {
+	decoder := xml.NewDecoder(r)
+	keys := make([]graphMLKey, 0)
+	nodes, edges := make([]graphMLElement, 0), make([]graphMLElement, 0)
+	undirected, inGraph := false, false
+
+	// keys, nodes and edges may appear in any order, hence the elements are resolved after the document has been decoded
+	for done := false; !done; {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("graphml: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "key":
+				var key graphMLKey
+				if err := decoder.DecodeElement(&key, &element); err != nil {
+					return nil, fmt.Errorf("graphml: %w", err)
+				}
+				keys = append(keys, key)
+			case "graph":
+				if inGraph {
+					return nil, fmt.Errorf("graphml: nested graphs are not supported")
+				}
+				inGraph = true
+				for _, a := range element.Attr {
+					if a.Name.Local == "edgedefault" {
+						undirected = a.Value == "undirected"
+					}
+				}
+			case "node", "edge":
+				if !inGraph {
+					continue
+				}
+				var e graphMLElement
+				if err := decoder.DecodeElement(&e, &element); err != nil {
+					return nil, fmt.Errorf("graphml: %w", err)
+				}
+				if element.Name.Local == "node" {
+					nodes = append(nodes, e)
+				} else {
+					edges = append(edges, e)
+				}
+			case "hyperedge":
+				return nil, fmt.Errorf("graphml: hyperedges are not supported")
+			}
+		case xml.EndElement:
+			// only the first graph is read
+			done = element.Name.Local == "graph"
+		}
+	}
+
+	alg := g.AdjacencyListGraph[N, E]{Nodes: make([]N, 0, len(nodes)), Edges: make([][]E, 0, len(nodes))}
+	ids := make(map[string]g.NodeId, len(nodes))
+	nodeKeys := resolveGraphMLKeys(keys, "node", nodeAttributes.Names())
+	for _, element := range nodes {
+		if _, ok := ids[element.Id]; ok {
+			return nil, fmt.Errorf("graphml: duplicate node ID '%s'", element.Id)
+		}
+		values, err := graphMLValues(element, nodeKeys, nodeAttributes.Names())
+		if err != nil {
+			return nil, fmt.Errorf("graphml: node '%s': %w", element.Id, err)
+		}
+		node, err := nodeAttributes.Parse(values)
+		if err != nil {
+			return nil, fmt.Errorf("graphml: node '%s': %w", element.Id, err)
+		}
+		ids[element.Id] = alg.AppendNode(node)
+	}
+
+	edgeKeys := resolveGraphMLKeys(keys, "edge", edgeAttributes.Names())
+	for _, element := range edges {
+		tail, ok := ids[element.Source]
+		if !ok {
+			return nil, fmt.Errorf("graphml: edge from unknown node '%s'", element.Source)
+		}
+		head, ok := ids[element.Target]
+		if !ok {
+			return nil, fmt.Errorf("graphml: edge to unknown node '%s'", element.Target)
+		}
+		values, err := graphMLValues(element, edgeKeys, edgeAttributes.Names())
+		if err != nil {
+			return nil, fmt.Errorf("graphml: edge from '%s' to '%s': %w", element.Source, element.Target, err)
+		}
+		edge, err := edgeAttributes.Parse(values)
+		if err != nil {
+			return nil, fmt.Errorf("graphml: edge from '%s' to '%s': %w", element.Source, element.Target, err)
+		}
+		alg.InsertHalfEdge(tail, edge.SetTo(head).(E))
+		if (undirected && element.Directed != "true") || element.Directed == "false" {
+			alg.InsertHalfEdge(head, edge.SetTo(tail).(E))
+		}
+	}
+	return &alg, nil
+}
+
+// resolveGraphMLKeys returns the key of each attribute name for the given domain (node or edge) or nil if the key is not declared.
+func resolveGraphMLKeys(keys []graphMLKey, domain string, names []string) []*graphMLKey {
+	resolved := make([]*graphMLKey, len(names))
+	for i, name := range names {
+		for k := range keys {
+			if keys[k].Name == name && (keys[k].For == domain || keys[k].For == "all" || keys[k].For == "") {
+				resolved[i] = &keys[k]
+				break
+			}
+		}
+	}
+	return resolved
+}
+
+// graphMLValues returns the value of each attribute of a node or edge, falling back to the default values of the keys.
+func graphMLValues(element graphMLElement, keys []*graphMLKey, names []string) ([]string, error) {
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		found := false
+		if key != nil {
+			for _, data := range element.Data {
+				if data.Key == key.Id {
+					values[i], found = data.Value, true
+					break
+				}
+			}
+			if !found && key.Default != nil {
+				values[i], found = *key.Default, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("missing attribute '%s'", names[i])
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,84 @@
+package io
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	alg := testPartitionedGraph()
+	var buffer bytes.Buffer
+	if err := WriteGraphML[g.PartGeoPoint, g.LargeFlaggedHalfEdge[int]](alg, &buffer, PartGeoPointAttributes, LargeFlaggedHalfEdgeAttributes); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{
+		`<key id="d0" for="node" attr.name="lat" attr.type="double"/>`,
+		`<key id="d2" for="node" attr.name="partition" attr.type="long"/>`,
+		`<key id="d4" for="edge" attr.name="arc_flags" attr.type="string"/>`,
+	} {
+		if !strings.Contains(buffer.String(), key) {
+			t.Errorf("Expected key %s in\n%s", key, buffer.String())
+		}
+	}
+
+	read, err := ReadGraphML(&buffer, PartGeoPointAttributes, LargeFlaggedHalfEdgeAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqualGraphs[g.PartGeoPoint, g.LargeFlaggedHalfEdge[int]](t, alg, read)
+}
+
+// networkxGraph resembles the output of networkx.write_graphml for an undirected graph with string node IDs.
+const networkxGraph = `<?xml version='1.0' encoding='utf-8'?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <key id="d2" for="edge" attr.name="weight" attr.type="long">
+    <default>1</default>
+  </key>
+  <key id="d1" for="node" attr.name="lon" attr.type="double"/>
+  <key id="d0" for="node" attr.name="lat" attr.type="double"/>
+  <graph edgedefault="undirected">
+    <edge source="munich" target="berlin">
+      <data key="d2">585</data>
+    </edge>
+    <node id="munich">
+      <data key="d0">48.14</data>
+      <data key="d1">11.58</data>
+    </node>
+    <node id="berlin">
+      <data key="d0">52.52</data>
+      <data key="d1">13.40</data>
+    </node>
+    <edge source="berlin" target="berlin" directed="true"/>
+  </graph>
+</graphml>
+`
+
+func TestGraphMLUndirected(t *testing.T) {
+	t.Parallel()
+
+	alg, err := ReadGraphML(strings.NewReader(networkxGraph), GeoPointAttributes, WeightedHalfEdgeAttributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alg.NodeCount() != 2 || alg.EdgeCount() != 3 {
+		t.Fatalf("Expected 2 nodes and 3 edges, got %d nodes and %d edges", alg.NodeCount(), alg.EdgeCount())
+	}
+	if node := alg.GetNode(1); node.Lat != 52.52 || node.Lon != 13.4 {
+		t.Errorf("Unexpected node %v", node)
+	}
+	if edge := alg.GetHalfEdgesFrom(1)[0]; edge.To_ != 0 || edge.Weight_ != 585 {
+		t.Errorf("Unexpected reverse edge %v", edge)
+	}
+	if edge := alg.GetHalfEdgesFrom(1)[1]; edge.To_ != 1 || edge.Weight_ != 1 {
+		t.Errorf("Expected loop with default weight, got %v", edge)
+	}
+
+	if _, err := ReadGraphML(strings.NewReader(networkxGraph), PartGeoPointAttributes, WeightedHalfEdgeAttributes); err == nil || !strings.Contains(err.Error(), "missing attribute 'partition'") {
+		t.Errorf("Expected missing attribute error, got %v", err)
+	}
+}
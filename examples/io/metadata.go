@@ -0,0 +1,70 @@
+package io
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kinds of preprocessed artifacts
+const (
+	ARTIFACT_ARC_FLAGS            = "arc-flags"
+	ARTIFACT_TWO_LEVEL_ARC_FLAGS  = "two-level-arc-flags"
+	ARTIFACT_LANDMARKS            = "landmarks"
+	ARTIFACT_PARTITIONS           = "partitions"
+	ARTIFACT_TWO_LEVEL_PARTITIONS = "two-level-partitions"
+	ARTIFACT_FLAG_MATRIX          = "flag-matrix"
+)
+
+// ErrIncompatibleArtifact is returned if a preprocessed artifact has been computed for a different graph.
+var ErrIncompatibleArtifact = errors.New("artifact has been computed for a different graph")
+
+// Metadata records the provenance of a preprocessed artifact such as arc flags or landmark distances:
+// the graph it has been computed for, the partitioner and the parameters of the preprocessing.
+//
+// .fmi files store the metadata as JSON in a comment line at the beginning of the file, which is ignored by readers
+// that do not know about metadata. Binary ALT files store the metadata in a section after the header.
+type Metadata struct {
+	Artifact    string            `json:"artifact"`              // kind of the artifact, e.g. ARTIFACT_ARC_FLAGS
+	BaseGraph   uint64            `json:"base_graph"`            // graph.Fingerprint of the graph the artifact has been computed for
+	Content     uint64            `json:"content,omitempty"`     // graph.ContentHash of the artifact itself, if it is a graph
+	Partitioner string            `json:"partitioner,omitempty"` // e.g. "grid" or "kd"
+	Parameters  map[string]string `json:"parameters,omitempty"`  // e.g. the number of partitions or landmarks
+	Created     string            `json:"created,omitempty"`     // time of the preprocessing (RFC 3339)
+}
+
+// NewMetadata creates the metadata of an artifact that is computed now for the graph with the given fingerprint.
+func NewMetadata(artifact string, baseGraph uint64) Metadata {
+	return Metadata{Artifact: artifact, BaseGraph: baseGraph, Parameters: make(map[string]string), Created: time.Now().UTC().Format(time.RFC3339)}
+}
+
+// CheckBaseGraph returns an error wrapping ErrIncompatibleArtifact iff the artifact has not been computed for the graph with the given fingerprint.
+func (m *Metadata) CheckBaseGraph(fingerprint uint64) error {
+	if m.BaseGraph != fingerprint {
+		return fmt.Errorf("%s: %w (base graph %016x, expected %016x)", m.Artifact, ErrIncompatibleArtifact, m.BaseGraph, fingerprint)
+	}
+	return nil
+}
+
+// String formats the metadata as a single line of JSON.
+func (m Metadata) String() string {
+	encoded, _ := json.Marshal(m)
+	return string(encoded)
+}
+
+// prefix of the comment line of an .fmi file that contains the metadata
+const fmiMetadataPrefix = "# graffiti-metadata: "
+
+// parseMetadataComment parses the metadata of a comment line. It returns nil iff the line does not contain metadata.
+func parseMetadataComment(line string) (*Metadata, error) {
+	if !strings.HasPrefix(line, fmiMetadataPrefix) {
+		return nil, nil
+	}
+	var metadata Metadata
+	if err := json.Unmarshal([]byte(line[len(fmiMetadataPrefix):]), &metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package io
+
+// mapFile reads the entire file into memory on platforms without mmap support.
+func mapFile(filename string) ([]byte, error) {
+	return readAligned(filename)
+}
+
+// unmapFile releases a mapping created by mapFile.
+func unmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,167 @@
+package io
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Partition file format
+//
+// A partition assignment is stored as a text file:
+//
+//	# graffiti-metadata: {...}   metadata (see Metadata), whose base graph is the fingerprint of the partitioned graph
+//	<nodeCount> <levels>         number of nodes and partition levels (1 or 2)
+//	<partition> ...              for each node in the order of the node IDs: one partition ID per level
+//
+// Since the assignment is stored separately from the graph, different partitionings of the same graph can be stored
+// and swapped without rewriting the graph file.
+
+// WritePartitionFile writes a partition assignment of the given graph into a file.
+// The metadata describes the partitioner; its base graph is set to the fingerprint of the graph.
+func WritePartitionFile[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], assignment g.PartitionAssignment, metadata Metadata, filename string) error {
+	metadata.Artifact = ARTIFACT_PARTITIONS
+	metadata.BaseGraph = g.Fingerprint[N, E, W](graph)
+	return writeFile(filename, func(w io.Writer) error {
+		return encodePartitions(w, graph.NodeCount(), metadata, assignment)
+	})
+}
+
+// WriteTwoLevelPartitionFile writes a two-level partition assignment of the given graph into a file (see WritePartitionFile).
+func WriteTwoLevelPartitionFile[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], assignment g.TwoLevelPartitionAssignment, metadata Metadata, filename string) error {
+	metadata.Artifact = ARTIFACT_TWO_LEVEL_PARTITIONS
+	metadata.BaseGraph = g.Fingerprint[N, E, W](graph)
+	return writeFile(filename, func(w io.Writer) error {
+		return encodePartitions(w, graph.NodeCount(), metadata, assignment.L1, assignment.L2)
+	})
+}
+
+// ReadPartitionFile reads a partition assignment of the given graph from a file.
+// An error wrapping ErrIncompatibleArtifact is returned if the file has been written for a different graph.
+func ReadPartitionFile[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], filename string) (g.PartitionAssignment, *Metadata, error) {
+	levels, metadata, err := readPartitionFile[N, E, W](graph, filename, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	return levels[0], metadata, nil
+}
+
+// ReadTwoLevelPartitionFile reads a two-level partition assignment of the given graph from a file (see ReadPartitionFile).
+func ReadTwoLevelPartitionFile[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], filename string) (g.TwoLevelPartitionAssignment, *Metadata, error) {
+	levels, metadata, err := readPartitionFile[N, E, W](graph, filename, 2)
+	if err != nil {
+		return g.TwoLevelPartitionAssignment{}, nil, err
+	}
+	return g.TwoLevelPartitionAssignment{L1: levels[0], L2: levels[1]}, metadata, nil
+}
+
+func readPartitionFile[N any, E g.IWeightedHalfEdge[W], W g.Weight](graph g.Graph[N, E], filename string, levelCount int) ([]g.PartitionAssignment, *Metadata, error) {
+	file, err := OpenFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	levels, metadata, err := decodePartitions(file, graph.NodeCount(), levelCount)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if err := metadata.CheckBaseGraph(g.Fingerprint[N, E, W](graph)); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return levels, metadata, nil
+}
+
+// encodePartitions writes the partitions of all levels of an assignment of nodeCount nodes in the partition file format to w.
+func encodePartitions(w io.Writer, nodeCount int, metadata Metadata, levels ...g.PartitionAssignment) error {
+	for _, level := range levels {
+		if len(level) != nodeCount {
+			return fmt.Errorf("partitions: assignment of %d nodes does not match the node count %d", len(level), nodeCount)
+		}
+	}
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "%s%s\n", fmiMetadataPrefix, metadata)
+	fmt.Fprintf(writer, "%d %d\n", nodeCount, len(levels))
+	line := make([]byte, 0, 16)
+	for id := 0; id < nodeCount; id++ {
+		line = line[:0]
+		for i, level := range levels {
+			if i > 0 {
+				line = append(line, ' ')
+			}
+			line = strconv.AppendUint(line, uint64(level[id]), 10)
+		}
+		line = append(line, '\n')
+		writer.Write(line)
+	}
+	return writer.Flush()
+}
+
+// decodePartitions reads an assignment of nodeCount nodes with the given number of levels in the partition file format.
+// An error wrapping ErrIncompatibleArtifact is returned if the file contains a different number of nodes.
+func decodePartitions(r io.Reader, nodeCount int, levelCount int) ([]g.PartitionAssignment, *Metadata, error) {
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	nextLine := func() (string, error) {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", fmt.Errorf("partitions: %w", err)
+			}
+			return "", fmt.Errorf("partitions: %w", io.ErrUnexpectedEOF)
+		}
+		lineNumber++
+		return scanner.Text(), nil
+	}
+
+	line, err := nextLine()
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata, err := parseMetadataComment(line)
+	if err != nil {
+		return nil, nil, fmt.Errorf("partitions: %w", err)
+	}
+	if metadata == nil {
+		return nil, nil, errors.New("partitions: missing metadata")
+	}
+
+	if line, err = nextLine(); err != nil {
+		return nil, nil, err
+	}
+	var fileNodeCount, fileLevels int
+	if _, err := fmt.Sscanf(line, "%d %d", &fileNodeCount, &fileLevels); err != nil {
+		return nil, nil, fmt.Errorf("partitions: line %d: invalid header %q", lineNumber, line)
+	}
+	if fileNodeCount != nodeCount {
+		return nil, nil, fmt.Errorf("partitions: %w (%d nodes, expected %d)", ErrIncompatibleArtifact, fileNodeCount, nodeCount)
+	}
+	if fileLevels != levelCount {
+		return nil, nil, fmt.Errorf("partitions: file contains %d levels, expected %d", fileLevels, levelCount)
+	}
+
+	levels := make([]g.PartitionAssignment, levelCount)
+	for i := range levels {
+		levels[i] = make(g.PartitionAssignment, nodeCount)
+	}
+	for id := 0; id < nodeCount; id++ {
+		if line, err = nextLine(); err != nil {
+			return nil, nil, err
+		}
+		fields := strings.Fields(line)
+		if len(fields) != levelCount {
+			return nil, nil, fmt.Errorf("partitions: line %d: expected %d partitions, got %d", lineNumber, levelCount, len(fields))
+		}
+		for i, field := range fields {
+			partition, err := strconv.ParseUint(field, 10, 16)
+			if err != nil {
+				return nil, nil, fmt.Errorf("partitions: line %d: invalid partition %q", lineNumber, field)
+			}
+			levels[i][id] = g.PartitionId(partition)
+		}
+	}
+	return levels, metadata, nil
+}
@@ -0,0 +1,63 @@
+package io
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestPartitionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	alg, err := ReadFmi(strings.NewReader(fmiGraph), GeoPointSchema, WeightedHalfEdgeSchema.Lenient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	assignment := g.PartitionAssignment{3, 0, 65535}
+	metadata := NewMetadata("", 0)
+	metadata.Partitioner = "manual"
+	filename := filepath.Join(dir, "partitions.txt")
+	if err := WritePartitionFile[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, assignment, metadata, filename); err != nil {
+		t.Fatal(err)
+	}
+	read, readMetadata, err := ReadPartitionFile[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, assignment) {
+		t.Errorf("Expected partitions %v, got %v", assignment, read)
+	}
+	if readMetadata.Artifact != ARTIFACT_PARTITIONS || readMetadata.Partitioner != "manual" {
+		t.Errorf("Unexpected metadata %v", readMetadata)
+	}
+
+	twoLevelAssignment := g.TwoLevelPartitionAssignment{L1: g.PartitionAssignment{0, 1, 1}, L2: g.PartitionAssignment{2, 0, 1}}
+	twoLevelFilename := filepath.Join(dir, "two_level_partitions.txt")
+	if err := WriteTwoLevelPartitionFile[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, twoLevelAssignment, metadata, twoLevelFilename); err != nil {
+		t.Fatal(err)
+	}
+	twoLevelRead, _, err := ReadTwoLevelPartitionFile[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, twoLevelFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(twoLevelRead, twoLevelAssignment) {
+		t.Errorf("Expected partitions %v, got %v", twoLevelAssignment, twoLevelRead)
+	}
+
+	// the number of levels must match
+	if _, _, err := ReadPartitionFile[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, twoLevelFilename); err == nil {
+		t.Error("Expected an error for a two-level partition file")
+	}
+
+	// partitions of a modified graph are rejected
+	alg.Edges[1][1].Weight_++
+	if _, _, err := ReadPartitionFile[g.GeoPoint, g.WeightedHalfEdge[int], int](alg, filename); !errors.Is(err, ErrIncompatibleArtifact) {
+		t.Errorf("Expected incompatible partitions, got %v", err)
+	}
+}
@@ -0,0 +1,253 @@
+package io
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Column names that refer to the ID of a node line or to the tail of an edge line rather than to a struct field.
+const (
+	ID_COLUMN   = "id"
+	FROM_COLUMN = "from"
+)
+
+// Schema declaratively describes the whitespace-separated columns of a node or edge line of a text file.
+// Each column refers to an exported field of T by name; promoted fields of embedded structs are referenced by their own name.
+// Array fields span one column per element. The special column 'id' (nodes) or 'from' (edges) holds the node ID or the tail of an edge.
+//
+// Supported field types are integers, floating point numbers, booleans, strings without whitespace and arrays of these types.
+type Schema[T any] struct {
+	// Names of the columns as passed to NewSchema
+	Columns []string
+	// AllowExtraColumns ignores additional columns at the end of a line, e.g. arc flags when only the weighted graph is of interest.
+	AllowExtraColumns bool
+
+	idColumn int // position of the ID column or -1
+	columns  []schemaColumn
+}
+
+// schemaColumn maps a column to a (possibly nested) struct field or to an element of an array field.
+type schemaColumn struct {
+	name    string
+	spec    int   // index of the column in Schema.Columns
+	index   []int // field index for reflect.Value.FieldByIndex
+	element int   // array element or -1 for scalar fields
+	kind    reflect.Kind
+	bits    int
+}
+
+// NewSchema creates a schema for the given columns. An error is returned iff a column does not refer to a supported field of T.
+func NewSchema[T any](columns ...string) (*Schema[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema: type %s is not a struct", t)
+	}
+	schema := Schema[T]{Columns: columns, idColumn: -1, columns: make([]schemaColumn, 0, len(columns))}
+	for spec, name := range columns {
+		if name == ID_COLUMN || name == FROM_COLUMN {
+			if schema.idColumn >= 0 {
+				return nil, fmt.Errorf("schema: duplicate ID column '%s'", name)
+			}
+			schema.idColumn = len(schema.columns)
+			schema.columns = append(schema.columns, schemaColumn{name: name, spec: spec, element: -1, kind: reflect.Int, bits: strconv.IntSize})
+			continue
+		}
+		field, ok := t.FieldByName(name)
+		if !ok || !field.IsExported() {
+			return nil, fmt.Errorf("schema: type %s has no exported field '%s'", t, name)
+		}
+		fieldType, elements := field.Type, 1
+		if fieldType.Kind() == reflect.Array {
+			fieldType, elements = fieldType.Elem(), field.Type.Len()
+		}
+		if !isScalarKind(fieldType.Kind()) {
+			return nil, fmt.Errorf("schema: field '%s' of type %s is not supported", name, field.Type)
+		}
+		for i := 0; i < elements; i++ {
+			column := schemaColumn{name: name, spec: spec, index: field.Index, element: -1, kind: fieldType.Kind(), bits: bitSize(fieldType)}
+			if field.Type.Kind() == reflect.Array {
+				column.element = i
+				column.name = fmt.Sprintf("%s[%d]", name, i)
+			}
+			schema.columns = append(schema.columns, column)
+		}
+	}
+	return &schema, nil
+}
+
+// MustSchema is like NewSchema but panics if the columns are invalid. It simplifies the initialization of global schemas.
+func MustSchema[T any](columns ...string) *Schema[T] {
+	schema, err := NewSchema[T](columns...)
+	if err != nil {
+		panic(err.Error() + "\n")
+	}
+	return schema
+}
+
+// Lenient returns a copy of the schema that ignores additional columns.
+func (s *Schema[T]) Lenient() *Schema[T] {
+	lenient := *s
+	lenient.AllowExtraColumns = true
+	return &lenient
+}
+
+// HasId returns true iff the schema contains an 'id' or 'from' column.
+func (s *Schema[T]) HasId() bool {
+	return s.idColumn >= 0
+}
+
+// Width returns the number of columns, where array fields count once per element.
+func (s *Schema[T]) Width() int {
+	return len(s.columns)
+}
+
+// ParseLine parses a line of whitespace-separated values.
+func (s *Schema[T]) ParseLine(line string) (int, T, error) {
+	return s.Parse(strings.Fields(line))
+}
+
+// Parse parses the values of a line. It returns the value of the ID column (or -1 if the schema has no ID column) and the parsed value.
+func (s *Schema[T]) Parse(fields []string) (int, T, error) {
+	var result T
+	if len(fields) < len(s.columns) || (len(fields) > len(s.columns) && !s.AllowExtraColumns) {
+		return -1, result, fmt.Errorf("expected %d columns, got %d", len(s.columns), len(fields))
+	}
+
+	id := -1
+	value := reflect.ValueOf(&result).Elem()
+	for i, column := range s.columns {
+		if i == s.idColumn {
+			parsed, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return -1, result, fmt.Errorf("column '%s': %w", column.name, err)
+			}
+			id = parsed
+			continue
+		}
+		target := value.FieldByIndex(column.index)
+		if column.element >= 0 {
+			target = target.Index(column.element)
+		}
+		if err := parseScalar(target, column, fields[i]); err != nil {
+			return -1, result, fmt.Errorf("column '%s': %w", column.name, err)
+		}
+	}
+	return id, result, nil
+}
+
+// Format formats a value as line of whitespace-separated values (without line break). The ID is written to the ID column, if any.
+// Floating point numbers are formatted with the minimal number of digits that represent them exactly.
+func (s *Schema[T]) Format(id int, t T) string {
+	var sb strings.Builder
+	s.appendFormatted(&sb, id, t)
+	return sb.String()
+}
+
+func (s *Schema[T]) appendFormatted(sb *strings.Builder, id int, t T) {
+	value := reflect.ValueOf(&t).Elem()
+	for i := range s.columns {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(s.formatColumn(i, id, value))
+	}
+}
+
+// formatFields formats the value of each column.
+func (s *Schema[T]) formatFields(id int, t T) []string {
+	value := reflect.ValueOf(&t).Elem()
+	fields := make([]string, len(s.columns))
+	for i := range s.columns {
+		fields[i] = s.formatColumn(i, id, value)
+	}
+	return fields
+}
+
+func (s *Schema[T]) formatColumn(i int, id int, value reflect.Value) string {
+	if i == s.idColumn {
+		return strconv.Itoa(id)
+	}
+	column := s.columns[i]
+	source := value.FieldByIndex(column.index)
+	if column.element >= 0 {
+		source = source.Index(column.element)
+	}
+	switch column.kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(source.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(source.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(source.Float(), 'f', -1, column.bits)
+	case reflect.Bool:
+		return strconv.FormatBool(source.Bool())
+	default:
+		return source.String()
+	}
+}
+
+func parseScalar(target reflect.Value, column schemaColumn, field string) error {
+	switch column.kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(field, 10, column.bits)
+		if err != nil {
+			return err
+		}
+		target.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(field, 10, column.bits)
+		if err != nil {
+			return err
+		}
+		target.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(field, column.bits)
+		if err != nil {
+			return err
+		}
+		target.SetFloat(parsed)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(field)
+		if err != nil {
+			return err
+		}
+		target.SetBool(parsed)
+	case reflect.String:
+		target.SetString(field)
+	}
+	return nil
+}
+
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return true
+	}
+	return false
+}
+
+func bitSize(t reflect.Type) int {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String:
+		return 0
+	}
+	return t.Bits()
+}
+
+// Schemas of the .fmi files produced by graffiti's preprocessors
+var (
+	GeoPointSchema                = MustSchema[g.GeoPoint](ID_COLUMN, "Lat", "Lon")
+	PartGeoPointSchema            = MustSchema[g.PartGeoPoint](ID_COLUMN, "Lat", "Lon", "Partition_")
+	TwoLevelPartGeoPointSchema    = MustSchema[g.TwoLevelPartGeoPoint](ID_COLUMN, "Lat", "Lon", "L1Part_", "L2Part_")
+	WeightedHalfEdgeSchema        = MustSchema[g.WeightedHalfEdge[int]](FROM_COLUMN, "To_", "Weight_")
+	FlaggedHalfEdgeSchema         = MustSchema[g.FlaggedHalfEdge[int, uint64]](FROM_COLUMN, "To_", "Weight_", "Flag")
+	LargeFlaggedHalfEdgeSchema    = MustSchema[g.LargeFlaggedHalfEdge[int]](FROM_COLUMN, "To_", "Weight_", "MsbFlag", "LsbFlag")
+	B256FlaggedHalfEdgeSchema     = MustSchema[g.B256FlaggedHalfEdge[int]](FROM_COLUMN, "To_", "Weight_", "Flag_")
+	TwoLevelFlaggedHalfEdgeSchema = MustSchema[g.TwoLevelFlaggedHalfEdge[int, uint64, uint64]](FROM_COLUMN, "To_", "Weight_", "L1Flag", "L2Flag")
+)
@@ -0,0 +1,260 @@
+// Package osm imports road and ferry networks from OpenStreetMap extracts (.osm.pbf or .osm XML files).
+package osm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dmholtz/graffiti/examples/heuristics"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// TagRule matches OSM elements that have a tag with the given key and one of the given values.
+type TagRule struct {
+	Key    string
+	Values []string // an empty slice matches any value
+}
+
+// Matches returns true iff the tags contain the key of the rule with a matching value.
+func (r TagRule) Matches(tags map[string]string) bool {
+	value, ok := tags[r.Key]
+	if !ok {
+		return false
+	}
+	if len(r.Values) == 0 {
+		return true
+	}
+	for _, v := range r.Values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter selects the ways that are imported: a way is imported iff it matches at least one include rule and no exclude rule.
+type Filter struct {
+	Include []TagRule
+	Exclude []TagRule
+	// IgnoreOneway imports all ways in both directions, e.g. for pedestrian networks.
+	IgnoreOneway bool
+}
+
+// Matches returns true iff a way with the given tags is imported.
+func (f Filter) Matches(tags map[string]string) bool {
+	for _, rule := range f.Exclude {
+		if rule.Matches(tags) {
+			return false
+		}
+	}
+	for _, rule := range f.Include {
+		if rule.Matches(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoadFilter selects roads that are accessible by car.
+func RoadFilter() Filter {
+	return Filter{
+		Include: []TagRule{{Key: "highway", Values: []string{
+			"motorway", "motorway_link", "trunk", "trunk_link", "primary", "primary_link", "secondary", "secondary_link",
+			"tertiary", "tertiary_link", "unclassified", "residential", "living_street", "service", "road"}}},
+		Exclude: []TagRule{
+			{Key: "area", Values: []string{"yes"}},
+			{Key: "access", Values: []string{"no", "private"}},
+			{Key: "motor_vehicle", Values: []string{"no", "private"}},
+		},
+	}
+}
+
+// FerryFilter selects ferry routes.
+func FerryFilter() Filter {
+	return Filter{Include: []TagRule{{Key: "route", Values: []string{"ferry"}}}}
+}
+
+// Network is a graph imported from OpenStreetMap.
+// Edge weights are haversine distances in meters along the geometry of the ways.
+type Network struct {
+	Graph *g.AdjacencyArrayGraph[g.GeoPoint, g.WeightedHalfEdge[int]]
+	// OsmIds maps each NodeId of the graph to the ID of the corresponding OSM node.
+	OsmIds []int64
+	// NodeIds maps the ID of an OSM node to its NodeId in the graph. Only junctions and end points of ways are nodes of the graph.
+	NodeIds map[int64]g.NodeId
+}
+
+// handler receives the decoded elements of an OSM file. Elements of nil callbacks are not decoded.
+type handler struct {
+	node func(id int64, lat, lon float64)
+	way  func(id int64, refs []int64, tags map[string]string)
+}
+
+// way of the OSM file that matches the filter
+type way struct {
+	refs     []int64
+	forward  bool
+	backward bool
+}
+
+// Import reads an OpenStreetMap extract, selects the ways matching any of the filters and builds a graph.
+// The file format is determined by the file extension: '.pbf' for the PBF format, otherwise the XML format.
+//
+// Ways are split at junctions, i.e. a node of a way becomes a node of the graph iff it is an end point of a way or shared by several ways.
+// Intermediate nodes are contracted into the edges, whose weights are the haversine distances summed along the way.
+// One-way streets (tags 'oneway', 'junction=roundabout' and 'highway=motorway') yield edges in one direction only,
+// unless the first matching filter ignores one-way streets.
+// Ways that refer to nodes outside of the extract are split at the missing nodes.
+func Import(filename string, filters ...Filter) (*Network, error) {
+	read := readXml
+	if strings.HasSuffix(filename, ".pbf") {
+		read = readPbf
+	}
+
+	// first pass: collect matching ways and count how often each node is referenced
+	ways := make([]way, 0)
+	references := make(map[int64]int)
+	err := readFile(filename, read, handler{way: func(id int64, refs []int64, tags map[string]string) {
+		if len(refs) < 2 {
+			return
+		}
+		filter, ok := matchingFilter(filters, tags)
+		if !ok {
+			return
+		}
+		forward, backward := true, true
+		if !filter.IgnoreOneway {
+			forward, backward = direction(tags)
+		}
+		ways = append(ways, way{refs: refs, forward: forward, backward: backward})
+		for i, ref := range refs {
+			references[ref]++
+			if i == 0 || i == len(refs)-1 {
+				// end points are always nodes of the graph
+				references[ref]++
+			}
+		}
+		if refs[0] == refs[len(refs)-1] {
+			// split closed ways (e.g. roundabouts) in the middle, since edges cannot start and end at the same node
+			references[refs[len(refs)/2]] += 2
+		}
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	// second pass: collect the locations of all referenced nodes
+	locations := make(map[int64]g.GeoPoint, len(references))
+	err = readFile(filename, read, handler{node: func(id int64, lat, lon float64) {
+		if _, ok := references[id]; ok {
+			locations[id] = g.GeoPoint{Lat: lat, Lon: lon}
+		}
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	// nodes next to missing nodes become end points of the split ways
+	for _, w := range ways {
+		for i, ref := range w.refs {
+			if _, ok := locations[ref]; ok {
+				continue
+			}
+			if i > 0 {
+				references[w.refs[i-1]] += 2
+			}
+			if i < len(w.refs)-1 {
+				references[w.refs[i+1]] += 2
+			}
+		}
+	}
+
+	// nodes of the graph in ascending order of their OSM IDs
+	osmIds := make([]int64, 0)
+	for ref, count := range references {
+		if _, ok := locations[ref]; ok && count > 1 {
+			osmIds = append(osmIds, ref)
+		}
+	}
+	sort.Slice(osmIds, func(i, j int) bool { return osmIds[i] < osmIds[j] })
+	nodeIds := make(map[int64]g.NodeId, len(osmIds))
+	builder := g.NewAdjacencyArrayBuilder[g.GeoPoint, g.WeightedHalfEdge[int], int](g.KEEP_MIN_WEIGHT)
+	for _, osmId := range osmIds {
+		nodeIds[osmId] = builder.AddNode(locations[osmId])
+	}
+
+	// split ways at the nodes of the graph
+	for _, w := range ways {
+		tail, distance := -1, 0
+		for i, ref := range w.refs {
+			location, ok := locations[ref]
+			if !ok {
+				tail = -1
+				continue
+			}
+			if tail >= 0 {
+				distance += heuristics.Haversine(locations[w.refs[i-1]], location)
+			}
+			head, isNode := nodeIds[ref]
+			if !isNode {
+				continue
+			}
+			if tail >= 0 && tail != head {
+				if w.forward {
+					builder.AddEdge(tail, g.WeightedHalfEdge[int]{To_: head, Weight_: distance})
+				}
+				if w.backward {
+					builder.AddEdge(head, g.WeightedHalfEdge[int]{To_: tail, Weight_: distance})
+				}
+			}
+			tail, distance = head, 0
+		}
+	}
+
+	aag, _, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	return &Network{Graph: aag, OsmIds: osmIds, NodeIds: nodeIds}, nil
+}
+
+// matchingFilter returns the first filter that matches the tags.
+func matchingFilter(filters []Filter, tags map[string]string) (Filter, bool) {
+	for _, filter := range filters {
+		if filter.Matches(tags) {
+			return filter, true
+		}
+	}
+	return Filter{}, false
+}
+
+// direction determines whether a way can be traversed in forward and in backward direction.
+func direction(tags map[string]string) (forward, backward bool) {
+	switch tags["oneway"] {
+	case "yes", "true", "1":
+		return true, false
+	case "-1", "reverse":
+		return false, true
+	case "no", "false", "0":
+		return true, true
+	}
+	if tags["junction"] == "roundabout" || tags["highway"] == "motorway" {
+		return true, false
+	}
+	return true, true
+}
+
+func readFile(filename string, read func(r io.Reader, h handler) error, h handler) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := read(file, h); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package osm
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/dmholtz/graffiti/examples/heuristics"
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+type testNode struct {
+	id       int64
+	lat, lon float64
+}
+
+type testWay struct {
+	id   int64
+	refs []int64
+	tags [][2]string
+}
+
+// Node 5 is an intermediate node of way 11, node 99 is missing in the extract and way 12 is not a road.
+var testNodes = []testNode{
+	{1, 0, 0}, {2, 0, 0.001}, {3, 0, 0.002}, {4, 0.001, 0.002}, {5, 0.001, 0.001}, {6, 0.002, 0.002}, {7, 0.002, 0.003},
+}
+
+var testWays = []testWay{
+	{10, []int64{1, 2, 3}, [][2]string{{"highway", "residential"}}},
+	{11, []int64{2, 5, 4}, [][2]string{{"highway", "primary"}, {"oneway", "yes"}}},
+	{12, []int64{3, 6}, [][2]string{{"highway", "footway"}}},
+	{13, []int64{4, 6}, [][2]string{{"route", "ferry"}}},
+	{14, []int64{4, 7, 99}, [][2]string{{"highway", "residential"}}},
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	xmlFile, pbfFile := filepath.Join(dir, "test.osm"), filepath.Join(dir, "test.osm.pbf")
+	os.WriteFile(xmlFile, encodeTestXml(), 0644)
+	os.WriteFile(pbfFile, encodeTestPbf(t), 0644)
+
+	location := func(id int64) g.GeoPoint {
+		for _, n := range testNodes {
+			if n.id == id {
+				return g.GeoPoint{Lat: n.lat, Lon: n.lon}
+			}
+		}
+		return g.GeoPoint{}
+	}
+	distance := func(refs ...int64) int {
+		d := 0
+		for i := 1; i < len(refs); i++ {
+			d += heuristics.Haversine(location(refs[i-1]), location(refs[i]))
+		}
+		return d
+	}
+
+	for _, filename := range []string{xmlFile, pbfFile} {
+		network, err := Import(filename, RoadFilter(), FerryFilter())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expectedIds := []int64{1, 2, 3, 4, 6, 7}
+		if len(network.OsmIds) != len(expectedIds) {
+			t.Fatalf("%s: expected OSM IDs %v, got %v", filename, expectedIds, network.OsmIds)
+		}
+		for i, osmId := range expectedIds {
+			if network.OsmIds[i] != osmId || network.NodeIds[osmId] != i {
+				t.Errorf("%s: expected OSM ID %d at node %d, got %d", filename, osmId, i, network.OsmIds[i])
+			}
+			if node := network.Graph.GetNode(i); node != location(osmId) {
+				t.Errorf("%s: expected node %d at %v, got %v", filename, i, location(osmId), node)
+			}
+		}
+
+		expectedEdges := map[[2]int64]int{
+			{1, 2}: distance(1, 2), {2, 1}: distance(1, 2),
+			{2, 3}: distance(2, 3), {3, 2}: distance(2, 3),
+			{2, 4}: distance(2, 5, 4),                      // one-way street with intermediate node
+			{4, 6}: distance(4, 6), {6, 4}: distance(4, 6), // ferry
+			{4, 7}: distance(4, 7), {7, 4}: distance(4, 7), // split at missing node
+		}
+		if network.Graph.EdgeCount() != len(expectedEdges) {
+			t.Errorf("%s: expected %d edges, got %d", filename, len(expectedEdges), network.Graph.EdgeCount())
+		}
+		for tail := 0; tail < network.Graph.NodeCount(); tail++ {
+			for _, edge := range network.Graph.GetHalfEdgesFrom(tail) {
+				key := [2]int64{network.OsmIds[tail], network.OsmIds[edge.To()]}
+				if weight, ok := expectedEdges[key]; !ok || weight != edge.Weight() {
+					t.Errorf("%s: unexpected edge %v with weight %d", filename, key, edge.Weight())
+				}
+			}
+		}
+	}
+
+	// without ferries, node 6 is not part of the network
+	network, err := Import(pbfFile, RoadFilter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := network.NodeIds[6]; ok || network.Graph.EdgeCount() != 7 {
+		t.Errorf("Expected 7 road edges without node 6, got %d edges", network.Graph.EdgeCount())
+	}
+}
+
+func encodeTestXml() []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<osm version=\"0.6\">\n")
+	for _, n := range testNodes {
+		buffer.WriteString("  <node id=\"" + itoa(n.id) + "\" lat=\"" + ftoa(n.lat) + "\" lon=\"" + ftoa(n.lon) + "\"/>\n")
+	}
+	for _, w := range testWays {
+		buffer.WriteString("  <way id=\"" + itoa(w.id) + "\">\n")
+		for _, ref := range w.refs {
+			buffer.WriteString("    <nd ref=\"" + itoa(ref) + "\"/>\n")
+		}
+		for _, tag := range w.tags {
+			buffer.WriteString("    <tag k=\"" + tag[0] + "\" v=\"" + tag[1] + "\"/>\n")
+		}
+		buffer.WriteString("  </way>\n")
+	}
+	buffer.WriteString("</osm>\n")
+	return buffer.Bytes()
+}
+
+// Compressed blobs that exceed the size limit or whose content does not match raw_size are rejected.
+func TestDecodeBlob(t *testing.T) {
+	t.Parallel()
+
+	zlibBlob := func(content []byte, rawSize int) []byte {
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		zw.Write(content)
+		zw.Close()
+		return appendBytesField(appendVarintField(nil, 2, uint64(rawSize)), 3, compressed.Bytes())
+	}
+
+	content := []byte("graffiti")
+	if raw, err := decodeBlob(zlibBlob(content, len(content))); err != nil || !bytes.Equal(raw, content) {
+		t.Errorf("Expected content '%s', got '%s' (error: %v)", content, raw, err)
+	}
+	if _, err := decodeBlob(zlibBlob(content, len(content)+1)); err == nil {
+		t.Errorf("Expected error for a blob that is shorter than its raw_size, got nil")
+	}
+	if _, err := decodeBlob(zlibBlob(make([]byte, maxBlobSize+1), maxBlobSize)); err == nil {
+		t.Errorf("Expected error for a blob that exceeds the limit of %d bytes, got nil", maxBlobSize)
+	}
+}
+
+// encodeTestPbf encodes the test data as PBF file with a raw header blob and a zlib-compressed data blob.
+func encodeTestPbf(t *testing.T) []byte {
+	header := appendBytesField(nil, 4, []byte("OsmSchema-V0.6"))
+	header = appendBytesField(header, 4, []byte("DenseNodes"))
+
+	// string table, index 0 is reserved
+	strings := []string{""}
+	index := func(s string) uint64 {
+		for i, existing := range strings {
+			if existing == s {
+				return uint64(i)
+			}
+		}
+		strings = append(strings, s)
+		return uint64(len(strings) - 1)
+	}
+
+	var ids, lats, lons []uint64
+	var lastId, lastLat, lastLon int64
+	for _, n := range testNodes {
+		lat, lon := int64(n.lat*1e7+0.5), int64(n.lon*1e7+0.5) // granularity 100 nanodegrees
+		ids, lats, lons = append(ids, zigzagEncode(n.id-lastId)), append(lats, zigzagEncode(lat-lastLat)), append(lons, zigzagEncode(lon-lastLon))
+		lastId, lastLat, lastLon = n.id, lat, lon
+	}
+	dense := appendBytesField(nil, 1, packVarints(ids))
+	dense = appendBytesField(dense, 8, packVarints(lats))
+	dense = appendBytesField(dense, 9, packVarints(lons))
+	nodeGroup := appendBytesField(nil, 2, dense)
+
+	var wayGroup []byte
+	for _, w := range testWays {
+		var keys, values, refs []uint64
+		for _, tag := range w.tags {
+			keys, values = append(keys, index(tag[0])), append(values, index(tag[1]))
+		}
+		var last int64
+		for _, ref := range w.refs {
+			refs = append(refs, zigzagEncode(ref-last))
+			last = ref
+		}
+		way := appendVarintField(nil, 1, uint64(w.id))
+		way = appendBytesField(way, 2, packVarints(keys))
+		way = appendBytesField(way, 3, packVarints(values))
+		way = appendBytesField(way, 8, packVarints(refs))
+		wayGroup = appendBytesField(wayGroup, 3, way)
+	}
+
+	var stringTable []byte
+	for _, s := range strings {
+		stringTable = appendBytesField(stringTable, 1, []byte(s))
+	}
+	block := appendBytesField(nil, 1, stringTable)
+	block = appendBytesField(block, 2, nodeGroup)
+	block = appendBytesField(block, 2, wayGroup)
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(block)
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var file []byte
+	file = appendFileBlock(file, "OSMHeader", appendBytesField(nil, 1, header))
+	file = appendFileBlock(file, "OSMData", appendBytesField(appendVarintField(nil, 2, uint64(len(block))), 3, compressed.Bytes()))
+	return file
+}
+
+func appendFileBlock(file []byte, blobType string, blob []byte) []byte {
+	blobHeader := appendBytesField(nil, 1, []byte(blobType))
+	blobHeader = appendVarintField(blobHeader, 3, uint64(len(blob)))
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(blobHeader)))
+	file = append(file, size...)
+	file = append(file, blobHeader...)
+	return append(file, blob...)
+}
+
+func appendVarintField(b []byte, field int, value uint64) []byte {
+	b = appendUvarint(b, uint64(field<<3|WIRE_VARINT))
+	return appendUvarint(b, value)
+}
+
+func appendBytesField(b []byte, field int, data []byte) []byte {
+	b = appendUvarint(b, uint64(field<<3|WIRE_LENGTH_DELIMITED))
+	b = appendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendUvarint(b []byte, value uint64) []byte {
+	buffer := make([]byte, binary.MaxVarintLen64)
+	return append(b, buffer[:binary.PutUvarint(buffer, value)]...)
+}
+
+func packVarints(values []uint64) []byte {
+	var b []byte
+	for _, value := range values {
+		b = appendUvarint(b, value)
+	}
+	return b
+}
+
+func zigzagEncode(value int64) uint64 {
+	return uint64(value<<1) ^ uint64(value>>63)
+}
+
+func itoa(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+
+func ftoa(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
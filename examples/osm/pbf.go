@@ -0,0 +1,406 @@
+package osm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Decoder for the OpenStreetMap PBF format (https://wiki.openstreetmap.org/wiki/PBF_Format).
+// The format is a sequence of blobs, each preceded by its length and a header. Blobs are protocol buffer messages,
+// which are decoded by hand since graffiti does not depend on a protocol buffer library.
+
+const (
+	maxBlobHeaderSize = 64 * 1024
+	maxBlobSize       = 32 * 1024 * 1024
+)
+
+// PBF features that are understood by this decoder. Files that require other features are rejected.
+var supportedFeatures = map[string]bool{
+	"OsmSchema-V0.6":        true,
+	"DenseNodes":            true,
+	"HistoricalInformation": true,
+}
+
+// protocol buffer wire types
+const (
+	WIRE_VARINT           = 0
+	WIRE_FIXED64          = 1
+	WIRE_LENGTH_DELIMITED = 2
+	WIRE_FIXED32          = 5
+)
+
+// readPbf decodes a PBF stream and passes nodes and ways to the handler.
+func readPbf(r io.Reader, h handler) error {
+	reader := bufio.NewReader(r)
+	headerBuffer := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(reader, headerBuffer); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		headerSize := binary.BigEndian.Uint32(headerBuffer)
+		if headerSize > maxBlobHeaderSize {
+			return fmt.Errorf("pbf: blob header of %d bytes exceeds limit", headerSize)
+		}
+		blobHeader := make([]byte, headerSize)
+		if _, err := io.ReadFull(reader, blobHeader); err != nil {
+			return err
+		}
+		blobType, blobSize, err := decodeBlobHeader(blobHeader)
+		if err != nil {
+			return err
+		}
+		if blobSize > maxBlobSize {
+			return fmt.Errorf("pbf: blob of %d bytes exceeds limit", blobSize)
+		}
+		blob := make([]byte, blobSize)
+		if _, err := io.ReadFull(reader, blob); err != nil {
+			return err
+		}
+
+		switch blobType {
+		case "OSMHeader":
+			data, err := decodeBlob(blob)
+			if err != nil {
+				return err
+			}
+			if err := checkHeaderBlock(data); err != nil {
+				return err
+			}
+		case "OSMData":
+			data, err := decodeBlob(blob)
+			if err != nil {
+				return err
+			}
+			if err := decodePrimitiveBlock(data, h); err != nil {
+				return err
+			}
+		default:
+			// unknown blob types must be skipped
+		}
+	}
+}
+
+// decodeBlobHeader returns the type and the size of the subsequent blob.
+func decodeBlobHeader(data []byte) (string, uint64, error) {
+	var blobType string
+	var blobSize uint64
+	p := protoReader{data: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			blobType = string(p.bytes)
+		case 3:
+			blobSize = p.varint
+		}
+	}
+	return blobType, blobSize, p.err
+}
+
+// decodeBlob returns the uncompressed content of a blob.
+// The content of a zlib-compressed blob must not exceed maxBlobSize and must match the raw_size of the blob.
+func decodeBlob(data []byte) ([]byte, error) {
+	var rawSize uint64
+	var zlibData []byte
+	p := protoReader{data: data}
+	for p.next() {
+		switch p.field {
+		case 1: // raw
+			return p.bytes, nil
+		case 2: // raw_size
+			rawSize = p.varint
+		case 3: // zlib_data
+			zlibData = p.bytes
+		case 4, 5, 6, 7:
+			return nil, errors.New("pbf: unsupported blob compression (only raw and zlib are supported)")
+		}
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	if zlibData == nil {
+		return nil, errors.New("pbf: blob without data")
+	}
+
+	if rawSize > maxBlobSize {
+		return nil, fmt.Errorf("pbf: uncompressed blob of %d bytes exceeds limit", rawSize)
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(zlibData))
+	if err != nil {
+		return nil, err
+	}
+	raw := bytes.NewBuffer(make([]byte, 0, rawSize))
+	// read one byte beyond the limit to detect oversized blobs instead of truncating them
+	if _, err := io.Copy(raw, io.LimitReader(zr, maxBlobSize+1)); err != nil {
+		return nil, err
+	}
+	if raw.Len() > maxBlobSize {
+		return nil, fmt.Errorf("pbf: uncompressed blob exceeds limit of %d bytes", maxBlobSize)
+	}
+	if uint64(raw.Len()) != rawSize {
+		return nil, fmt.Errorf("pbf: uncompressed blob of %d bytes does not match raw_size %d", raw.Len(), rawSize)
+	}
+	return raw.Bytes(), zr.Close()
+}
+
+// checkHeaderBlock fails iff the file requires features that are not supported.
+func checkHeaderBlock(data []byte) error {
+	p := protoReader{data: data}
+	for p.next() {
+		if p.field == 4 && !supportedFeatures[string(p.bytes)] {
+			return fmt.Errorf("pbf: unsupported required feature '%s'", p.bytes)
+		}
+	}
+	return p.err
+}
+
+// primitiveBlock provides the string table and the coordinate transformation of a block to its groups.
+type primitiveBlock struct {
+	strings     [][]byte
+	granularity int64
+	latOffset   int64
+	lonOffset   int64
+}
+
+// coordinate converts a latitude or longitude from the block's units to degrees.
+func (b *primitiveBlock) coordinate(offset, value int64) float64 {
+	return 1e-9 * float64(offset+b.granularity*value)
+}
+
+func decodePrimitiveBlock(data []byte, h handler) error {
+	block := primitiveBlock{granularity: 100}
+	groups := make([][]byte, 0)
+	p := protoReader{data: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			st := protoReader{data: p.bytes}
+			for st.next() {
+				if st.field == 1 {
+					block.strings = append(block.strings, st.bytes)
+				}
+			}
+			if st.err != nil {
+				return st.err
+			}
+		case 2:
+			groups = append(groups, p.bytes)
+		case 17:
+			block.granularity = int64(p.varint)
+		case 19:
+			block.latOffset = int64(p.varint)
+		case 20:
+			block.lonOffset = int64(p.varint)
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+
+	// groups are decoded after the whole block has been read, since the string table and the granularity may follow the groups
+	for _, group := range groups {
+		pg := protoReader{data: group}
+		for pg.next() {
+			var err error
+			switch {
+			case pg.field == 1 && h.node != nil:
+				err = block.decodeNode(pg.bytes, h)
+			case pg.field == 2 && h.node != nil:
+				err = block.decodeDenseNodes(pg.bytes, h)
+			case pg.field == 3 && h.way != nil:
+				err = block.decodeWay(pg.bytes, h)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if pg.err != nil {
+			return pg.err
+		}
+	}
+	return nil
+}
+
+func (b *primitiveBlock) decodeNode(data []byte, h handler) error {
+	var id, lat, lon int64
+	p := protoReader{data: data}
+	for p.next() {
+		switch p.field {
+		case 1:
+			id = zigzag(p.varint)
+		case 8:
+			lat = zigzag(p.varint)
+		case 9:
+			lon = zigzag(p.varint)
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	h.node(id, b.coordinate(b.latOffset, lat), b.coordinate(b.lonOffset, lon))
+	return nil
+}
+
+func (b *primitiveBlock) decodeDenseNodes(data []byte, h handler) error {
+	var ids, lats, lons []uint64
+	p := protoReader{data: data}
+	for p.next() {
+		var err error
+		switch p.field {
+		case 1:
+			ids, err = p.appendVarints(ids)
+		case 8:
+			lats, err = p.appendVarints(lats)
+		case 9:
+			lons, err = p.appendVarints(lons)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	if len(lats) != len(ids) || len(lons) != len(ids) {
+		return errors.New("pbf: dense nodes with inconsistent number of ids and coordinates")
+	}
+
+	// ids and coordinates are delta-encoded
+	var id, lat, lon int64
+	for i := range ids {
+		id += zigzag(ids[i])
+		lat += zigzag(lats[i])
+		lon += zigzag(lons[i])
+		h.node(id, b.coordinate(b.latOffset, lat), b.coordinate(b.lonOffset, lon))
+	}
+	return nil
+}
+
+func (b *primitiveBlock) decodeWay(data []byte, h handler) error {
+	var id int64
+	var keys, values, deltas []uint64
+	p := protoReader{data: data}
+	for p.next() {
+		var err error
+		switch p.field {
+		case 1:
+			id = int64(p.varint)
+		case 2:
+			keys, err = p.appendVarints(keys)
+		case 3:
+			values, err = p.appendVarints(values)
+		case 8:
+			deltas, err = p.appendVarints(deltas)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if p.err != nil {
+		return p.err
+	}
+	if len(keys) != len(values) {
+		return fmt.Errorf("pbf: way %d has %d keys but %d values", id, len(keys), len(values))
+	}
+
+	tags := make(map[string]string, len(keys))
+	for i := range keys {
+		if keys[i] >= uint64(len(b.strings)) || values[i] >= uint64(len(b.strings)) {
+			return fmt.Errorf("pbf: way %d refers to a string outside of the string table", id)
+		}
+		tags[string(b.strings[keys[i]])] = string(b.strings[values[i]])
+	}
+	refs := make([]int64, len(deltas))
+	var ref int64
+	for i, delta := range deltas {
+		ref += zigzag(delta)
+		refs[i] = ref
+	}
+	h.way(id, refs, tags)
+	return nil
+}
+
+// protoReader iterates over the fields of a protocol buffer message.
+// After a successful call of next, field and wireType describe the current field and either varint or bytes holds its value.
+type protoReader struct {
+	data     []byte
+	field    int
+	wireType int
+	varint   uint64
+	bytes    []byte
+	err      error
+}
+
+// next advances to the next field and returns false at the end of the message or if an error occured.
+func (p *protoReader) next() bool {
+	if p.err != nil || len(p.data) == 0 {
+		return false
+	}
+	key, n := binary.Uvarint(p.data)
+	if n <= 0 {
+		p.err = errors.New("pbf: invalid field key")
+		return false
+	}
+	p.data = p.data[n:]
+	p.field, p.wireType = int(key>>3), int(key&7)
+
+	switch p.wireType {
+	case WIRE_VARINT:
+		p.varint, n = binary.Uvarint(p.data)
+		if n <= 0 {
+			p.err = errors.New("pbf: invalid varint")
+			return false
+		}
+		p.data = p.data[n:]
+	case WIRE_LENGTH_DELIMITED:
+		length, n := binary.Uvarint(p.data)
+		if n <= 0 || length > uint64(len(p.data)-n) {
+			p.err = errors.New("pbf: invalid length-delimited field")
+			return false
+		}
+		p.bytes = p.data[n : n+int(length)]
+		p.data = p.data[n+int(length):]
+	case WIRE_FIXED64, WIRE_FIXED32:
+		size := 8
+		if p.wireType == WIRE_FIXED32 {
+			size = 4
+		}
+		if len(p.data) < size {
+			p.err = errors.New("pbf: truncated fixed-size field")
+			return false
+		}
+		p.data = p.data[size:]
+	default:
+		p.err = fmt.Errorf("pbf: unsupported wire type %d", p.wireType)
+		return false
+	}
+	return true
+}
+
+// appendVarints appends the values of a repeated integer field, which may be packed or not.
+func (p *protoReader) appendVarints(values []uint64) ([]uint64, error) {
+	if p.wireType == WIRE_VARINT {
+		return append(values, p.varint), nil
+	}
+	packed := p.bytes
+	for len(packed) > 0 {
+		value, n := binary.Uvarint(packed)
+		if n <= 0 {
+			return nil, errors.New("pbf: invalid packed varint")
+		}
+		values = append(values, value)
+		packed = packed[n:]
+	}
+	return values, nil
+}
+
+// zigzag decodes a zigzag-encoded signed integer (sint64).
+func zigzag(value uint64) int64 {
+	return int64(value>>1) ^ -int64(value&1)
+}
@@ -0,0 +1,105 @@
+package osm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// readXml decodes an OpenStreetMap XML stream (.osm) and passes nodes and ways to the handler.
+// The stream is decoded token by token, such that the file does not have to fit into memory.
+func readXml(r io.Reader, h handler) error {
+	decoder := xml.NewDecoder(r)
+
+	// attributes of the way that is currently decoded
+	var wayId int64
+	var refs []int64
+	var tags map[string]string
+	inWay := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "node":
+				if h.node == nil {
+					continue
+				}
+				id, err := parseIntAttr(element, "id")
+				if err != nil {
+					return err
+				}
+				lat, err := parseFloatAttr(element, "lat")
+				if err != nil {
+					return err
+				}
+				lon, err := parseFloatAttr(element, "lon")
+				if err != nil {
+					return err
+				}
+				h.node(id, lat, lon)
+			case "way":
+				if h.way == nil {
+					continue
+				}
+				if wayId, err = parseIntAttr(element, "id"); err != nil {
+					return err
+				}
+				refs, tags, inWay = make([]int64, 0), make(map[string]string), true
+			case "nd":
+				if !inWay {
+					continue
+				}
+				ref, err := parseIntAttr(element, "ref")
+				if err != nil {
+					return err
+				}
+				refs = append(refs, ref)
+			case "tag":
+				if !inWay {
+					continue
+				}
+				tags[attr(element, "k")] = attr(element, "v")
+			}
+		case xml.EndElement:
+			if element.Name.Local == "way" && inWay {
+				h.way(wayId, refs, tags)
+				inWay = false
+			}
+		}
+	}
+}
+
+// attr returns the value of an attribute of an element or the empty string if the attribute does not exist.
+func attr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func parseIntAttr(element xml.StartElement, name string) (int64, error) {
+	value, err := strconv.ParseInt(attr(element, name), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("osm xml: invalid attribute '%s' of element '%s': %w", name, element.Name.Local, err)
+	}
+	return value, nil
+}
+
+func parseFloatAttr(element xml.StartElement, name string) (float64, error) {
+	value, err := strconv.ParseFloat(attr(element, name), 64)
+	if err != nil {
+		return 0, fmt.Errorf("osm xml: invalid attribute '%s' of element '%s': %w", name, element.Name.Local, err)
+	}
+	return value, nil
+}
@@ -7,10 +7,15 @@ import (
 )
 
 // GridPartitioning is a preprocessing step for arc flags and computes the grid partitioning of a graph of GeoPoints.
-func GridPartitioning[E g.IHalfEdge](graph *g.AdjacencyArrayGraph[g.PartGeoPoint, E], lat, lon int) *g.AdjacencyArrayGraph[g.PartGeoPoint, E] {
+func GridPartitioning[N geoPartitioner, E g.IHalfEdge](graph *g.AdjacencyArrayGraph[N, E], lat, lon int) *g.AdjacencyArrayGraph[N, E] {
+	return g.ApplyPartitions(graph, GridAssignment[N, E](graph, lat, lon))
+}
 
-	for i := 0; i < graph.NodeCount(); i++ {
-		geoPoint := graph.GetNode(i)
+// GridAssignment computes the grid partitioning of a graph of nodes with a location (see GridPartitioning) as an assignment.
+func GridAssignment[N g.Locator, E g.IHalfEdge](graph g.Graph[N, E], lat, lon int) g.PartitionAssignment {
+	assignment := make(g.PartitionAssignment, graph.NodeCount())
+	for i := range assignment {
+		geoPoint := graph.GetNode(i).Location()
 
 		// determine column (lon) and row (lat) index of geoPoint in the grid
 		col := int(math.Min(((geoPoint.Lon + 180) / 360 * float64(lon)), float64(lon-1)))
@@ -18,10 +23,8 @@ func GridPartitioning[E g.IHalfEdge](graph *g.AdjacencyArrayGraph[g.PartGeoPoint
 
 		partition := row*lon + col
 
-		geoPoint.Partition_ = g.PartitionId(partition)
-
-		graph.Nodes[i] = geoPoint
+		assignment[i] = g.PartitionId(partition)
 	}
 
-	return graph
+	return assignment
 }
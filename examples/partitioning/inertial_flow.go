@@ -0,0 +1,324 @@
+package partitioning
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// fraction of the nodes at each end of the projection that are used as sources and sinks of the max-flow computation
+const inertialFlowBalance = 0.25
+
+// directions (lon, lat) on which the nodes are projected: east-west, north-south and both diagonals
+var inertialFlowDirections = [][2]float64{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+
+// geoPartitioner is a node with a location, whose partition can be replaced.
+type geoPartitioner interface {
+	g.Locator
+	g.SettablePartitioner
+}
+
+// geoMultiLevelPartitioner is a node with a location, whose partitions of all levels can be replaced.
+type geoMultiLevelPartitioner interface {
+	g.Locator
+	g.SettableMultiLevelPartitioner
+}
+
+// geoTwoLevelPartitioner is a node with a location, whose level 1 and level 2 partitions can be replaced.
+type geoTwoLevelPartitioner interface {
+	g.Locator
+	g.SettableTwoLevelPartitioner
+}
+
+// InertialFlowPartitioning is a preprocessing step for arc flags and computes a partitioning into 2^depth regions by recursive
+// bisection with inertial flow, cf. Schild and Sommer: "On Balanced Separators in Road Networks", 2015.
+//
+// Each bisection projects the nodes on several directions. For each direction, the first and the last quarter of the nodes
+// are used as sources and sinks of a max-flow computation, whose minimum cut separates the region. The direction with the
+// smallest cut is chosen. In contrast to GridPartitioning and KdPartitioning, the cuts follow natural bottlenecks of the graph
+// such as straits and canals, while each side of a bisection contains at least a quarter of the nodes of its region.
+// Edges are considered undirected and the partition IDs are assigned as in KdPartitioning.
+func InertialFlowPartitioning[N geoPartitioner, E g.IHalfEdge](graph *g.AdjacencyArrayGraph[N, E], depth int) *g.AdjacencyArrayGraph[N, E] {
+	return g.ApplyPartitions(graph, InertialFlowAssignment[N, E](graph, depth))
+}
+
+// InertialFlowAssignment computes the inertial flow partitioning of a graph of nodes with a location (see InertialFlowPartitioning) as an assignment.
+func InertialFlowAssignment[N g.Locator, E g.IHalfEdge](graph g.Graph[N, E], depth int) g.PartitionAssignment {
+	if depth > 16 {
+		panic(fmt.Sprintf("Partition IDs are 16 bit integers. Got: depth=%d, 2^%d > 2^16", depth, depth))
+	}
+
+	wg, locations := newInertialFlowGraph[N, E](graph)
+	assignment := make(g.PartitionAssignment, graph.NodeCount())
+	bisectInertialFlow(wg, locations, allNodes(graph.NodeCount()), depth, 0, assignment, nil)
+	return assignment
+}
+
+// TwoLevelInertialFlowPartitioning is a preprocessing step for two-level arc flags and computes a partitioning into 2^l1Depth level 1
+// regions by inertial flow, each of which is subdivided into 2^l2Depth level 2 regions by inertial flow (see InertialFlowPartitioning).
+func TwoLevelInertialFlowPartitioning[N geoTwoLevelPartitioner, E g.IHalfEdge](graph *g.AdjacencyArrayGraph[N, E], l1Depth, l2Depth int) *g.AdjacencyArrayGraph[N, E] {
+	return g.ApplyTwoLevelPartitions(graph, TwoLevelInertialFlowAssignment[N, E](graph, l1Depth, l2Depth))
+}
+
+// TwoLevelInertialFlowAssignment computes the two-level inertial flow partitioning of a graph of nodes with a location
+// (see TwoLevelInertialFlowPartitioning) as an assignment.
+func TwoLevelInertialFlowAssignment[N g.Locator, E g.IHalfEdge](graph g.Graph[N, E], l1Depth, l2Depth int) g.TwoLevelPartitionAssignment {
+	if l1Depth > 5 {
+		panic(fmt.Sprintf("32 bit are reserved for level 1 partitions. Got: l1Depth=%d, 2^%d > 32", l1Depth, l1Depth))
+	}
+	if l2Depth > 5 {
+		panic(fmt.Sprintf("32 bit are reserved for level 2 partitions. Got: l2Depth=%d, 2^%d > 32", l2Depth, l2Depth))
+	}
+
+	assignment := MultiLevelInertialFlowAssignment[N, E](graph, l1Depth, l2Depth)
+	return g.TwoLevelPartitionAssignment{L1: assignment[0], L2: assignment[1]}
+}
+
+// MultiLevelInertialFlowPartitioning is a preprocessing step for multi-level arc flags and computes a hierarchical partitioning, whose
+// level l subdivides each region of level l-1 into 2^depths[l] regions by inertial flow (see InertialFlowPartitioning).
+func MultiLevelInertialFlowPartitioning[N geoMultiLevelPartitioner, E g.IHalfEdge](graph *g.AdjacencyArrayGraph[N, E], depths ...int) *g.AdjacencyArrayGraph[N, E] {
+	return g.ApplyMultiLevelPartitions(graph, MultiLevelInertialFlowAssignment[N, E](graph, depths...))
+}
+
+// MultiLevelInertialFlowAssignment computes the multi-level inertial flow partitioning of a graph of nodes with a location
+// (see MultiLevelInertialFlowPartitioning) as an assignment.
+func MultiLevelInertialFlowAssignment[N g.Locator, E g.IHalfEdge](graph g.Graph[N, E], depths ...int) g.MultiLevelPartitionAssignment {
+	for level, depth := range depths {
+		if depth > 6 {
+			panic(fmt.Sprintf("64 bit are reserved for the partitions of each level. Got: depth=%d on level %d, 2^%d > 64", depth, level, depth))
+		}
+	}
+
+	wg, locations := newInertialFlowGraph[N, E](graph)
+	assignment := g.NewMultiLevelPartitionAssignment(graph.NodeCount(), len(depths))
+
+	// subdivide each region of the previous level, starting with the entire graph: the subgraphs of the regions are split off
+	// from the subgraphs of their parent regions by the bisections, such that each level costs O(n+m) besides the max-flows
+	regions := []inertialFlowRegion{{wg: wg, ids: allNodes(graph.NodeCount())}}
+	for level, depth := range depths {
+		subregions := make([]inertialFlowRegion, 0, len(regions)<<depth)
+		for _, region := range regions {
+			if len(region.ids) == 0 {
+				continue
+			}
+			bisectInertialFlow(region.wg, locations, region.ids, depth, 0, assignment[level], &subregions)
+		}
+		regions = subregions
+	}
+	return assignment
+}
+
+// inertialFlowRegion is a region of a multi-level partitioning, i.e. the undirected subgraph of the region and its node IDs.
+type inertialFlowRegion struct {
+	wg  *weightedGraph
+	ids []g.NodeId
+}
+
+// newInertialFlowGraph returns the undirected graph of a graph and the locations of its nodes.
+func newInertialFlowGraph[N g.Locator, E g.IHalfEdge](graph g.Graph[N, E]) (*weightedGraph, []g.GeoPoint) {
+	locations := make([]g.GeoPoint, graph.NodeCount())
+	for id := range locations {
+		locations[id] = graph.GetNode(id).Location()
+	}
+	return newWeightedGraph[N, E](graph), locations
+}
+
+func allNodes(n int) []g.NodeId {
+	ids := make([]g.NodeId, n)
+	for i := range ids {
+		ids[i] = i
+	}
+	return ids
+}
+
+// bisectInertialFlow recursively bisects wg, whose nodes correspond to the indices ids of locations and partitions. If leaves is
+// not nil, the regions of the partitions are appended to leaves in the order of their partition IDs.
+func bisectInertialFlow(wg *weightedGraph, locations []g.GeoPoint, ids []int, depth int, partition g.PartitionId, partitions []g.PartitionId, leaves *[]inertialFlowRegion) {
+	if depth == 0 {
+		for _, id := range ids {
+			partitions[id] = partition
+		}
+		if leaves != nil {
+			*leaves = append(*leaves, inertialFlowRegion{wg: wg, ids: ids})
+		}
+		return
+	}
+
+	sides := inertialFlowCut(wg, locations, ids)
+	for side := 0; side < 2; side++ {
+		subgraph, subIds := wg.subgraph(sides, side, ids)
+		bisectInertialFlow(subgraph, locations, subIds, depth-1, partition<<1+g.PartitionId(side), partitions, leaves)
+	}
+}
+
+// inertialFlowCut returns the minimum cut of all projection directions as sides of the nodes (0: source side, 1: sink side).
+// Nodes that are not connected with any source are assigned to the sink side.
+func inertialFlowCut(wg *weightedGraph, locations []g.GeoPoint, ids []int) []int {
+	n := wg.nodeCount()
+	terminals := int(inertialFlowBalance * float64(n))
+	if terminals == 0 {
+		// too few nodes for a max-flow computation
+		sides := make([]int, n)
+		for u := n / 2; u < n; u++ {
+			sides[u] = 1
+		}
+		return sides
+	}
+
+	var best []int
+	bestCut := -1
+	order := make([]int, n)
+	for _, direction := range inertialFlowDirections {
+		projection := func(u int) float64 {
+			return direction[0]*locations[ids[u]].Lon + direction[1]*locations[ids[u]].Lat
+		}
+		for u := range order {
+			order[u] = u
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return projection(order[i]) < projection(order[j])
+		})
+
+		sources, sinks := make([]bool, n), make([]bool, n)
+		for i := 0; i < terminals; i++ {
+			sources[order[i]] = true
+			sinks[order[n-1-i]] = true
+		}
+		network := newFlowNetwork(wg)
+		if cut := network.maxFlow(sources, sinks); bestCut == -1 || cut < bestCut {
+			best, bestCut = network.minCut(sources), cut
+		}
+	}
+	return best
+}
+
+// flowNetwork computes maximum flows in an undirected weighted graph, where the edge weights are the capacities.
+type flowNetwork struct {
+	wg      *weightedGraph
+	reverse []int // index of the reverse edge of each edge
+	flow    []int // flow of each edge, such that flow[reverse[i]] = -flow[i]
+	levels  []int
+	next    []int // next edge to be explored by the depth-first search of each node
+}
+
+func newFlowNetwork(wg *weightedGraph) *flowNetwork {
+	network := flowNetwork{wg: wg, reverse: make([]int, len(wg.targets)), flow: make([]int, len(wg.targets)), levels: make([]int, wg.nodeCount()), next: make([]int, wg.nodeCount())}
+	for u := 0; u < wg.nodeCount(); u++ {
+		for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+			v := wg.targets[i]
+			for j := wg.offsets[v]; j < wg.offsets[v+1]; j++ {
+				if wg.targets[j] == u {
+					network.reverse[i] = j
+					break
+				}
+			}
+		}
+	}
+	return &network
+}
+
+func (fn *flowNetwork) residual(i int) int {
+	return fn.wg.weights[i] - fn.flow[i]
+}
+
+// maxFlow computes a maximum flow from the source nodes to the sink nodes with Dinic's algorithm and returns its value.
+func (fn *flowNetwork) maxFlow(sources, sinks []bool) int {
+	total := 0
+	for fn.buildLevels(sources, sinks) {
+		for u := range fn.next {
+			fn.next[u] = fn.wg.offsets[u]
+		}
+		for u, source := range sources {
+			if !source {
+				continue
+			}
+			for {
+				pushed := fn.augment(u, math.MaxInt, sinks)
+				if pushed == 0 {
+					break
+				}
+				total += pushed
+			}
+		}
+	}
+	return total
+}
+
+// buildLevels computes the breadth-first levels of the residual graph from the sources and reports whether a sink is reachable.
+func (fn *flowNetwork) buildLevels(sources, sinks []bool) bool {
+	queue := make([]int, 0)
+	for u := range fn.levels {
+		fn.levels[u] = -1
+		if sources[u] {
+			fn.levels[u] = 0
+			queue = append(queue, u)
+		}
+	}
+	reachable := false
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		if sinks[u] {
+			reachable = true
+			continue
+		}
+		for i := fn.wg.offsets[u]; i < fn.wg.offsets[u+1]; i++ {
+			if v := fn.wg.targets[i]; fn.levels[v] == -1 && fn.residual(i) > 0 {
+				fn.levels[v] = fn.levels[u] + 1
+				queue = append(queue, v)
+			}
+		}
+	}
+	return reachable
+}
+
+// augment pushes at most limit units of flow from u to a sink along edges of the level graph and returns the pushed flow.
+func (fn *flowNetwork) augment(u int, limit int, sinks []bool) int {
+	if sinks[u] {
+		return limit
+	}
+	for ; fn.next[u] < fn.wg.offsets[u+1]; fn.next[u]++ {
+		i := fn.next[u]
+		v := fn.wg.targets[i]
+		if fn.levels[v] != fn.levels[u]+1 || fn.residual(i) <= 0 {
+			continue
+		}
+		capacity := fn.residual(i)
+		if capacity > limit {
+			capacity = limit
+		}
+		if pushed := fn.augment(v, capacity, sinks); pushed > 0 {
+			fn.flow[i] += pushed
+			fn.flow[fn.reverse[i]] -= pushed
+			return pushed
+		}
+	}
+	return 0
+}
+
+// minCut returns the sides of a minimum cut after a maximum flow has been computed: 0 for all nodes that are reachable
+// from the sources in the residual graph and 1 otherwise.
+func (fn *flowNetwork) minCut(sources []bool) []int {
+	sides := make([]int, fn.wg.nodeCount())
+	queue := make([]int, 0)
+	for u := range sides {
+		sides[u] = 1
+		if sources[u] {
+			sides[u] = 0
+			queue = append(queue, u)
+		}
+	}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		for i := fn.wg.offsets[u]; i < fn.wg.offsets[u+1]; i++ {
+			if v := fn.wg.targets[i]; sides[v] == 1 && fn.residual(i) > 0 {
+				sides[v] = 0
+				queue = append(queue, v)
+			}
+		}
+	}
+	return sides
+}
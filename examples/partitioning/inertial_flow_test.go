@@ -0,0 +1,61 @@
+package partitioning
+
+import (
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestInertialFlowPartitioning(t *testing.T) {
+	t.Parallel()
+
+	// two 16x16 grids, which are connected by a single edge (bottleneck) in the upper half
+	alg := gridGraph(16, 32)
+	for row := 0; row < 16; row++ {
+		alg.Edges[row*32+15] = removeEdge(alg.Edges[row*32+15], row*32+16)
+		alg.Edges[row*32+16] = removeEdge(alg.Edges[row*32+16], row*32+15)
+	}
+	connect(alg, 12*32+15, 12*32+16)
+
+	aag := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]](alg)
+	aag = InertialFlowPartitioning(aag, 1)
+
+	if cut := cutEdges[g.WeightedHalfEdge[int]](aag); cut != 2 {
+		t.Errorf("Expected 2 cut edges, got %d", cut)
+	}
+	for row := 0; row < 16; row++ {
+		if aag.GetNode(row*32).Partition() == aag.GetNode(row*32+31).Partition() {
+			t.Errorf("Expected the grids in different partitions, got partition %d for nodes %d and %d", aag.GetNode(row*32).Partition(), row*32, row*32+31)
+		}
+	}
+}
+
+func TestTwoLevelInertialFlowPartitioning(t *testing.T) {
+	t.Parallel()
+
+	grid := gridGraph(32, 32)
+	alg := g.AdjacencyListGraph[g.TwoLevelPartGeoPoint, g.WeightedHalfEdge[int]]{Edges: grid.Edges}
+	for _, node := range grid.Nodes {
+		alg.Nodes = append(alg.Nodes, g.TwoLevelPartGeoPoint{GeoPoint: node.GeoPoint})
+	}
+	aag := g.NewAdjacencyArrayFromGraph[g.TwoLevelPartGeoPoint, g.WeightedHalfEdge[int]](&alg)
+	aag = TwoLevelInertialFlowPartitioning(aag, 2, 2)
+
+	sizes := make(map[[2]g.PartitionId]int)
+	for i := 0; i < aag.NodeCount(); i++ {
+		node := aag.GetNode(i)
+		if node.L1Part() >= 4 || node.L2Part() >= 4 {
+			t.Fatalf("Expected partitions in [0, 4), got (%d, %d)", node.L1Part(), node.L2Part())
+		}
+		sizes[[2]g.PartitionId{node.L1Part(), node.L2Part()}]++
+	}
+	if len(sizes) != 16 {
+		t.Errorf("Expected 16 regions, got %d", len(sizes))
+	}
+	// each side of a bisection contains at least a quarter of the nodes
+	for region, size := range sizes {
+		if size < aag.NodeCount()/256 {
+			t.Errorf("Expected at least %d nodes in region %v, got %d", aag.NodeCount()/256, region, size)
+		}
+	}
+}
@@ -8,22 +8,25 @@ import (
 )
 
 type KDNode struct {
-	node g.PartGeoPoint
-	id   g.NodeId
+	node      g.GeoPoint
+	partition g.PartitionId
+	id        g.NodeId
 }
 
 // KDPartitioning is a preprocessing step for arc flags and computes a partitioning by constructing a kD-tree of the graph
-func KdPartitioning[E g.IHalfEdge](graph *g.AdjacencyArrayGraph[g.PartGeoPoint, E], depth int) *g.AdjacencyArrayGraph[g.PartGeoPoint, E] {
-	if depth > 8 {
-		panic(fmt.Sprintf("256 bit are reserved for partitions. Got: depth=%d, 2^%d > 256", depth, depth))
+func KdPartitioning[N geoPartitioner, E g.IHalfEdge](graph *g.AdjacencyArrayGraph[N, E], depth int) *g.AdjacencyArrayGraph[N, E] {
+	return g.ApplyPartitions(graph, KdAssignment[N, E](graph, depth))
+}
+
+// KdAssignment computes the kD-tree partitioning of a graph of nodes with a location (see KdPartitioning) as an assignment.
+func KdAssignment[N g.Locator, E g.IHalfEdge](graph g.Graph[N, E], depth int) g.PartitionAssignment {
+	if depth > 16 {
+		panic(fmt.Sprintf("Partition IDs are 16 bit integers. Got: depth=%d, 2^%d > 2^16", depth, depth))
 	}
 
 	kdNodes := make([]KDNode, 0, graph.NodeCount())
-	// reset existing partitions
 	for i := 0; i < graph.NodeCount(); i++ {
-		point := graph.GetNode(i)
-		point.Partition_ = 0
-		kdNodes = append(kdNodes, KDNode{node: point, id: i})
+		kdNodes = append(kdNodes, KDNode{node: graph.GetNode(i).Location(), id: i})
 	}
 
 	queue := make([][]KDNode, 0)
@@ -51,12 +54,12 @@ func KdPartitioning[E g.IHalfEdge](graph *g.AdjacencyArrayGraph[g.PartGeoPoint,
 
 			first := kdNodes[:len(kdNodes)/2]
 			for j := 0; j < len(first); j++ {
-				first[j].node.Partition_ = first[j].node.Partition() << 1
+				first[j].partition = first[j].partition << 1
 			}
 
 			second := kdNodes[len(kdNodes)/2:]
 			for j := 0; j < len(second); j++ {
-				second[j].node.Partition_ = (second[j].node.Partition() << 1) + 1
+				second[j].partition = (second[j].partition << 1) + 1
 			}
 
 			queue = append(queue, first)
@@ -69,9 +72,10 @@ func KdPartitioning[E g.IHalfEdge](graph *g.AdjacencyArrayGraph[g.PartGeoPoint,
 		kdNodes = append(kdNodes, s...)
 	}
 
+	assignment := make(g.PartitionAssignment, graph.NodeCount())
 	for _, kdNode := range kdNodes {
-		graph.Nodes[kdNode.id] = kdNode.node
+		assignment[kdNode.id] = kdNode.partition
 	}
 
-	return graph
+	return assignment
 }
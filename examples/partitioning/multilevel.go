@@ -0,0 +1,489 @@
+package partitioning
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Parameters of the multilevel partitioning
+const (
+	coarsestNodeCount      = 128 // coarsening stops as soon as the graph has at most this number of nodes
+	minCoarseningRatio     = 0.95
+	initialBisectionTries  = 8  // number of initial bisections by graph growing, of which the best one is refined
+	refinementPasses       = 8  // maximum number of Fiduccia-Mattheyses passes per level
+	maxUnprofitableMoves   = 64 // a Fiduccia-Mattheyses pass stops after this number of moves without improvement
+	maxPartitionCount      = math.MaxUint16 + 1
+	maxCoarseNodeWeightPct = 150 // maximum weight of a coarse node in percent of the average weight of the coarsest nodes
+)
+
+// MultilevelPartitioning is a preprocessing step for arc flags and computes a partitioning into k regions of balanced size,
+// such that the number of edges between different regions is small. Unlike GridPartitioning and KdPartitioning,
+// the partitioning only depends on the structure of the graph and is therefore applicable to any node type.
+//
+// The k-way partitioning is computed by recursive bisection, where each bisection follows the multilevel scheme of
+// Karypis and Kumar: "A Fast and High Quality Multilevel Scheme for Partitioning Irregular Graphs", 1998 (METIS):
+// the graph is coarsened by heavy-edge matching, the coarsest graph is bisected by graph growing, and the bisection is
+// refined by the Fiduccia-Mattheyses heuristic while it is projected back to the original graph.
+//
+// Edges are considered undirected. The size of each region exceeds the average size n/k by a factor of at most 1+imbalance,
+// unless the graph structure does not admit a balanced bisection. The result is deterministic for a given seed.
+func MultilevelPartitioning[N g.SettablePartitioner, E g.IHalfEdge](graph *g.AdjacencyArrayGraph[N, E], k int, imbalance float64, seed int64) *g.AdjacencyArrayGraph[N, E] {
+	return g.ApplyPartitions(graph, MultilevelAssignment[N, E](graph, k, imbalance, seed))
+}
+
+// MultilevelAssignment computes the multilevel partitioning of a graph of any node type (see MultilevelPartitioning) as an assignment.
+func MultilevelAssignment[N any, E g.IHalfEdge](graph g.Graph[N, E], k int, imbalance float64, seed int64) g.PartitionAssignment {
+	if k < 1 || k > maxPartitionCount {
+		panic(fmt.Sprintf("The number of partitions must be in [1, %d]. Got: k=%d", maxPartitionCount, k))
+	}
+
+	wg := newWeightedGraph[N, E](graph)
+	ids := make([]g.NodeId, graph.NodeCount())
+	for i := range ids {
+		ids[i] = i
+	}
+	assignment := make(g.PartitionAssignment, graph.NodeCount())
+	partitionRecursively(wg, ids, k, 0, recursionImbalance(k, imbalance), rand.New(rand.NewSource(seed)), assignment)
+	return assignment
+}
+
+// recursionImbalance distributes the imbalance of a k-way partitioning among the levels of the recursive bisection.
+func recursionImbalance(k int, imbalance float64) float64 {
+	depth := math.Ceil(math.Log2(float64(k)))
+	if depth > 1 {
+		return math.Pow(1+imbalance, 1/depth) - 1
+	}
+	return imbalance
+}
+
+// partitionRecursively partitions wg, whose nodes correspond to the node IDs ids, into the partitions first, ..., first+k-1.
+func partitionRecursively(wg *weightedGraph, ids []g.NodeId, k int, first int, epsilon float64, rng *rand.Rand, partitions []g.PartitionId) {
+	if k == 1 {
+		for _, id := range ids {
+			partitions[id] = g.PartitionId(first)
+		}
+		return
+	}
+
+	k0 := k / 2
+	sides := wg.bisect(float64(k0)/float64(k), epsilon, rng)
+	for side, count := range []int{k0, k - k0} {
+		subgraph, subIds := wg.subgraph(sides, side, ids)
+		partitionRecursively(subgraph, subIds, count, first+side*k0, epsilon, rng, partitions)
+	}
+}
+
+// weightedGraph is an undirected graph with node and edge weights in compressed sparse row format.
+type weightedGraph struct {
+	offsets     []int // the edges of node u are at the indices offsets[u], ..., offsets[u+1]-1
+	targets     []int
+	weights     []int // edge weights
+	nodeWeights []int
+}
+
+// newWeightedGraph creates the undirected graph of a directed graph, in which the weight of an edge
+// is the number of directed edges between its endpoints. Self-loops are dropped.
+func newWeightedGraph[N any, E g.IHalfEdge](graph g.Graph[N, E]) *weightedGraph {
+	n := graph.NodeCount()
+	neighbors := make([][]int, n)
+	for tail := 0; tail < n; tail++ {
+		for _, edge := range graph.GetHalfEdgesFrom(tail) {
+			if head := edge.To(); head != tail {
+				neighbors[tail] = append(neighbors[tail], head)
+				neighbors[head] = append(neighbors[head], tail)
+			}
+		}
+	}
+
+	wg := weightedGraph{offsets: make([]int, n+1), targets: make([]int, 0), weights: make([]int, 0), nodeWeights: make([]int, n)}
+	positions := newPositions(n)
+	for u := 0; u < n; u++ {
+		wg.nodeWeights[u] = 1
+		start := len(wg.targets)
+		for _, v := range neighbors[u] {
+			wg.addEdge(positions, start, v, 1)
+		}
+		wg.offsets[u+1] = len(wg.targets)
+	}
+	return &wg
+}
+
+func newPositions(n int) []int {
+	positions := make([]int, n)
+	for i := range positions {
+		positions[i] = -1
+	}
+	return positions
+}
+
+// addEdge adds an edge to the node whose edges start at index start, or increases its weight if it already exists.
+// positions stores the index of the last edge to each node.
+func (wg *weightedGraph) addEdge(positions []int, start int, target int, weight int) {
+	if position := positions[target]; position >= start {
+		wg.weights[position] += weight
+		return
+	}
+	positions[target] = len(wg.targets)
+	wg.targets = append(wg.targets, target)
+	wg.weights = append(wg.weights, weight)
+}
+
+func (wg *weightedGraph) nodeCount() int {
+	return len(wg.nodeWeights)
+}
+
+func (wg *weightedGraph) totalWeight() int {
+	total := 0
+	for _, weight := range wg.nodeWeights {
+		total += weight
+	}
+	return total
+}
+
+// cut returns the total weight of the edges between both sides.
+func (wg *weightedGraph) cut(sides []int) int {
+	cut := 0
+	for u := 0; u < wg.nodeCount(); u++ {
+		for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+			if sides[u] != sides[wg.targets[i]] {
+				cut += wg.weights[i]
+			}
+		}
+	}
+	return cut / 2
+}
+
+// subgraph returns the subgraph induced by the nodes on the given side and the IDs of its nodes.
+func (wg *weightedGraph) subgraph(sides []int, side int, ids []g.NodeId) (*weightedGraph, []g.NodeId) {
+	newIds := newPositions(wg.nodeCount())
+	subIds := make([]g.NodeId, 0)
+	for u := 0; u < wg.nodeCount(); u++ {
+		if sides[u] == side {
+			newIds[u] = len(subIds)
+			subIds = append(subIds, ids[u])
+		}
+	}
+
+	sub := weightedGraph{offsets: make([]int, len(subIds)+1), targets: make([]int, 0), weights: make([]int, 0), nodeWeights: make([]int, len(subIds))}
+	for u := 0; u < wg.nodeCount(); u++ {
+		if sides[u] != side {
+			continue
+		}
+		for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+			if v := wg.targets[i]; sides[v] == side {
+				sub.targets = append(sub.targets, newIds[v])
+				sub.weights = append(sub.weights, wg.weights[i])
+			}
+		}
+		sub.nodeWeights[newIds[u]] = wg.nodeWeights[u]
+		sub.offsets[newIds[u]+1] = len(sub.targets)
+	}
+	return &sub, subIds
+}
+
+// coarsen contracts a heavy-edge matching and returns the coarse graph as well as the coarse node of each node.
+// Two nodes are only matched if the weight of the coarse node does not exceed maxNodeWeight.
+func (wg *weightedGraph) coarsen(rng *rand.Rand, maxNodeWeight int) (*weightedGraph, []int) {
+	n := wg.nodeCount()
+	matching := newPositions(n)
+	for _, u := range rng.Perm(n) {
+		if matching[u] != -1 {
+			continue
+		}
+		matching[u] = u
+		best, bestWeight := -1, 0
+		for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+			v := wg.targets[i]
+			if matching[v] == -1 && wg.weights[i] > bestWeight && wg.nodeWeights[u]+wg.nodeWeights[v] <= maxNodeWeight {
+				best, bestWeight = v, wg.weights[i]
+			}
+		}
+		if best != -1 {
+			matching[u], matching[best] = best, u
+		}
+	}
+
+	coarseIds := newPositions(n)
+	count := 0
+	for u := 0; u < n; u++ {
+		if coarseIds[u] == -1 {
+			coarseIds[u], coarseIds[matching[u]] = count, count
+			count++
+		}
+	}
+
+	coarse := weightedGraph{offsets: make([]int, count+1), targets: make([]int, 0), weights: make([]int, 0), nodeWeights: make([]int, count)}
+	positions := newPositions(count)
+	for u := 0; u < n; u++ {
+		if matching[u] < u {
+			// the coarse node has already been created by the other node of the matching
+			continue
+		}
+		c := coarseIds[u]
+		start := len(coarse.targets)
+		members := []int{u, matching[u]}
+		if matching[u] == u {
+			members = members[:1]
+		}
+		for _, member := range members {
+			coarse.nodeWeights[c] += wg.nodeWeights[member]
+			for i := wg.offsets[member]; i < wg.offsets[member+1]; i++ {
+				if target := coarseIds[wg.targets[i]]; target != c {
+					coarse.addEdge(positions, start, target, wg.weights[i])
+				}
+			}
+		}
+		coarse.offsets[c+1] = len(coarse.targets)
+	}
+	return &coarse, coarseIds
+}
+
+// bisect computes a bisection of the graph, where side 0 receives the given fraction of the total node weight.
+// The weight of each side exceeds its target weight by a factor of at most 1+epsilon, if possible.
+func (wg *weightedGraph) bisect(fraction float64, epsilon float64, rng *rand.Rand) []int {
+	total := wg.totalWeight()
+	target0 := int(math.Round(fraction * float64(total)))
+	maxWeights := [2]int{int(float64(target0) * (1 + epsilon)), int(float64(total-target0) * (1 + epsilon))}
+
+	// coarsening phase
+	levels := []*weightedGraph{wg}
+	coarseIds := make([][]int, 0)
+	maxNodeWeight := total * maxCoarseNodeWeightPct / 100 / coarsestNodeCount
+	if maxNodeWeight < 2 {
+		maxNodeWeight = 2
+	}
+	for current := wg; current.nodeCount() > coarsestNodeCount; {
+		coarse, ids := current.coarsen(rng, maxNodeWeight)
+		if float64(coarse.nodeCount()) > minCoarseningRatio*float64(current.nodeCount()) {
+			break
+		}
+		levels = append(levels, coarse)
+		coarseIds = append(coarseIds, ids)
+		current = coarse
+	}
+
+	// initial bisection of the coarsest graph
+	coarsest := levels[len(levels)-1]
+	var sides []int
+	bestViolation, bestCut := 0, 0
+	for try := 0; try < initialBisectionTries; try++ {
+		candidate := coarsest.growBisection(rng, target0)
+		coarsest.refine(candidate, maxWeights)
+		weights := coarsest.sideWeights(candidate)
+		violation, cut := weightViolation(weights, maxWeights), coarsest.cut(candidate)
+		if sides == nil || violation < bestViolation || (violation == bestViolation && cut < bestCut) {
+			sides, bestViolation, bestCut = candidate, violation, cut
+		}
+	}
+
+	// uncoarsening phase
+	for level := len(levels) - 2; level >= 0; level-- {
+		fine := make([]int, levels[level].nodeCount())
+		for u, c := range coarseIds[level] {
+			fine[u] = sides[c]
+		}
+		sides = fine
+		levels[level].refine(sides, maxWeights)
+	}
+	return sides
+}
+
+// growBisection grows side 0 from random nodes in breadth-first order until it reaches the target weight.
+func (wg *weightedGraph) growBisection(rng *rand.Rand, target0 int) []int {
+	n := wg.nodeCount()
+	sides := make([]int, n)
+	for u := range sides {
+		sides[u] = 1
+	}
+	visited := make([]bool, n)
+	seeds := rng.Perm(n)
+	queue := make([]int, 0)
+	for weight0 := 0; weight0 < target0; {
+		if len(queue) == 0 {
+			// start a new region, e.g. if the graph is disconnected
+			for len(seeds) > 0 && visited[seeds[0]] {
+				seeds = seeds[1:]
+			}
+			if len(seeds) == 0 {
+				break
+			}
+			visited[seeds[0]] = true
+			queue = append(queue, seeds[0])
+		}
+		u := queue[0]
+		queue = queue[1:]
+		sides[u] = 0
+		weight0 += wg.nodeWeights[u]
+		for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+			if v := wg.targets[i]; !visited[v] {
+				visited[v] = true
+				queue = append(queue, v)
+			}
+		}
+	}
+	return sides
+}
+
+func (wg *weightedGraph) sideWeights(sides []int) [2]int {
+	var weights [2]int
+	for u, side := range sides {
+		weights[side] += wg.nodeWeights[u]
+	}
+	return weights
+}
+
+// weightViolation returns the total weight by which the sides exceed their maximum weights.
+func weightViolation(weights, maxWeights [2]int) int {
+	violation := 0
+	for side := range weights {
+		if weights[side] > maxWeights[side] {
+			violation += weights[side] - maxWeights[side]
+		}
+	}
+	return violation
+}
+
+// refine improves a bisection by the Fiduccia-Mattheyses heuristic: In each pass, nodes are moved to the other side one
+// after another in the order of their gains (reduction of the cut), each node at most once. Finally, the pass is rolled back
+// to the best bisection found, which allows to escape local minima. Balance is restored first if it is violated.
+func (wg *weightedGraph) refine(sides []int, maxWeights [2]int) {
+	n := wg.nodeCount()
+	weights := wg.sideWeights(sides)
+	cut := wg.cut(sides)
+	gains := make([]int, n)
+	locked := make([]bool, n)
+
+	for pass := 0; pass < refinementPasses; pass++ {
+		var queues [2]gainQueue
+		for u := 0; u < n; u++ {
+			gains[u], locked[u] = 0, false
+			for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+				if sides[wg.targets[i]] != sides[u] {
+					gains[u] += wg.weights[i]
+				} else {
+					gains[u] -= wg.weights[i]
+				}
+			}
+			heap.Push(&queues[sides[u]], gainItem{node: u, gain: gains[u]})
+		}
+
+		moves := make([]int, 0)
+		bestMoves, bestViolation, bestCut := 0, weightViolation(weights, maxWeights), cut
+		for unprofitable := 0; unprofitable < maxUnprofitableMoves; {
+			// candidate of each side: the unlocked node with the largest gain
+			var candidates [2]int
+			for side := range queues {
+				candidates[side] = -1
+				for len(queues[side]) > 0 {
+					top := queues[side][0]
+					if !locked[top.node] && sides[top.node] == side && gains[top.node] == top.gain {
+						candidates[side] = top.node
+						break
+					}
+					heap.Pop(&queues[side]) // outdated entry
+				}
+			}
+
+			from := -1
+			for side, u := range candidates {
+				if u == -1 {
+					continue
+				}
+				if weights[side] > maxWeights[side] {
+					// restore balance first
+					from = side
+					break
+				}
+				if weights[1-side]+wg.nodeWeights[u] > maxWeights[1-side] {
+					continue
+				}
+				if from == -1 || gains[u] > gains[candidates[from]] {
+					from = side
+				}
+			}
+			if from == -1 {
+				break
+			}
+
+			u := candidates[from]
+			heap.Pop(&queues[from])
+			locked[u] = true
+			moves = append(moves, u)
+			cut -= gains[u]
+			sides[u] = 1 - from
+			weights[from] -= wg.nodeWeights[u]
+			weights[1-from] += wg.nodeWeights[u]
+			for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+				v := wg.targets[i]
+				if locked[v] {
+					continue
+				}
+				if sides[v] == sides[u] {
+					gains[v] -= 2 * wg.weights[i]
+				} else {
+					gains[v] += 2 * wg.weights[i]
+				}
+				heap.Push(&queues[sides[v]], gainItem{node: v, gain: gains[v]})
+			}
+
+			if violation := weightViolation(weights, maxWeights); violation < bestViolation || (violation == bestViolation && cut < bestCut) {
+				bestMoves, bestViolation, bestCut = len(moves), violation, cut
+				unprofitable = 0
+			} else {
+				unprofitable++
+			}
+		}
+
+		// roll back to the best bisection
+		for i := len(moves) - 1; i >= bestMoves; i-- {
+			u := moves[i]
+			weights[sides[u]] -= wg.nodeWeights[u]
+			sides[u] = 1 - sides[u]
+			weights[sides[u]] += wg.nodeWeights[u]
+		}
+		cut = bestCut
+		if bestMoves == 0 {
+			break
+		}
+	}
+}
+
+// gainItem is an entry of a gainQueue.
+type gainItem struct {
+	node int
+	gain int
+}
+
+// gainQueue is a max-priority queue of nodes ordered by their gains. It implements heap.Interface.
+// Entries are not updated, but outdated entries are skipped when they reach the top of the queue.
+type gainQueue []gainItem
+
+func (gq gainQueue) Len() int {
+	return len(gq)
+}
+
+func (gq gainQueue) Less(i, j int) bool {
+	if gq[i].gain != gq[j].gain {
+		return gq[i].gain > gq[j].gain
+	}
+	return gq[i].node < gq[j].node
+}
+
+func (gq gainQueue) Swap(i, j int) {
+	gq[i], gq[j] = gq[j], gq[i]
+}
+
+func (gq *gainQueue) Push(x interface{}) {
+	*gq = append(*gq, x.(gainItem))
+}
+
+func (gq *gainQueue) Pop() interface{} {
+	old := *gq
+	item := old[len(old)-1]
+	*gq = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,171 @@
+package partitioning
+
+import (
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// gridGraph creates an undirected rows x cols grid graph, whose nodes are located at lat=row and lon=col.
+func gridGraph(rows, cols int) *g.AdjacencyListGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]] {
+	alg := g.AdjacencyListGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]]{}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			alg.AppendNode(g.PartGeoPoint{GeoPoint: g.GeoPoint{Lat: float64(row), Lon: float64(col)}})
+		}
+	}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			id := row*cols + col
+			if col+1 < cols {
+				connect(&alg, id, id+1)
+			}
+			if row+1 < rows {
+				connect(&alg, id, id+cols)
+			}
+		}
+	}
+	return &alg
+}
+
+func connect(alg *g.AdjacencyListGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]], u, v g.NodeId) {
+	alg.InsertHalfEdge(u, g.WeightedHalfEdge[int]{To_: v, Weight_: 1})
+	alg.InsertHalfEdge(v, g.WeightedHalfEdge[int]{To_: u, Weight_: 1})
+}
+
+// cutEdges returns the number of edges between different partitions.
+func cutEdges[E g.IHalfEdge](graph g.Graph[g.PartGeoPoint, E]) int {
+	cut := 0
+	for tail := 0; tail < graph.NodeCount(); tail++ {
+		for _, edge := range graph.GetHalfEdgesFrom(tail) {
+			if graph.GetNode(tail).Partition() != graph.GetNode(edge.To()).Partition() {
+				cut++
+			}
+		}
+	}
+	return cut
+}
+
+func TestMultilevelPartitioningBottleneck(t *testing.T) {
+	t.Parallel()
+
+	// two 16x16 grids, which are connected by a single edge (bottleneck)
+	alg := gridGraph(16, 32)
+	for row := 0; row < 16; row++ {
+		for _, edge := range alg.GetHalfEdgesFrom(row*32 + 15) {
+			if edge.To() == row*32+16 {
+				alg.Edges[row*32+15] = removeEdge(alg.Edges[row*32+15], row*32+16)
+				alg.Edges[row*32+16] = removeEdge(alg.Edges[row*32+16], row*32+15)
+			}
+		}
+	}
+	connect(alg, 8*32+15, 8*32+16)
+
+	aag := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]](alg)
+	aag = MultilevelPartitioning(aag, 2, 0.03, 1)
+
+	if cut := cutEdges[g.WeightedHalfEdge[int]](aag); cut != 2 {
+		t.Errorf("Expected 2 cut edges, got %d", cut)
+	}
+}
+
+func removeEdge(edges []g.WeightedHalfEdge[int], to g.NodeId) []g.WeightedHalfEdge[int] {
+	result := make([]g.WeightedHalfEdge[int], 0, len(edges))
+	for _, edge := range edges {
+		if edge.To() != to {
+			result = append(result, edge)
+		}
+	}
+	return result
+}
+
+func TestMultilevelPartitioning(t *testing.T) {
+	t.Parallel()
+
+	aag := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]](gridGraph(64, 64))
+	k, imbalance := 8, 0.05
+	aag = MultilevelPartitioning(aag, k, imbalance, 1)
+
+	sizes := make([]int, k)
+	for i := 0; i < aag.NodeCount(); i++ {
+		partition := int(aag.GetNode(i).Partition())
+		if partition >= k {
+			t.Fatalf("Expected partitions in [0, %d), got %d", k, partition)
+		}
+		sizes[partition]++
+	}
+	maxSize := int(float64(aag.NodeCount()) / float64(k) * (1 + imbalance))
+	for partition, size := range sizes {
+		if size == 0 || size > maxSize {
+			t.Errorf("Expected size of partition %d in [1, %d], got %d", partition, maxSize, size)
+		}
+	}
+
+	// a grid partitioning into 2x4 regions cuts 1*64 + 3*64 undirected edges, i.e. 512 directed edges
+	if cut := cutEdges[g.WeightedHalfEdge[int]](aag); cut > 600 {
+		t.Errorf("Expected at most %d cut edges, got %d", 600, cut)
+	}
+
+	// deterministic for the same seed
+	other := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]](gridGraph(64, 64))
+	other = MultilevelPartitioning(other, k, imbalance, 1)
+	for i := 0; i < aag.NodeCount(); i++ {
+		if aag.GetNode(i).Partition() != other.GetNode(i).Partition() {
+			t.Fatalf("Expected deterministic partitions, got %d and %d for node %d", aag.GetNode(i).Partition(), other.GetNode(i).Partition(), i)
+		}
+	}
+}
+
+func TestPartitionAssignments(t *testing.T) {
+	t.Parallel()
+
+	// the assignments of a graph of GeoPoints match the partitions of a graph of PartGeoPoints
+	aag := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]](gridGraph(16, 16))
+	nodes := make([]g.GeoPoint, aag.NodeCount())
+	for i, node := range aag.Nodes {
+		nodes[i] = node.GeoPoint
+	}
+	geoGraph := &g.AdjacencyArrayGraph[g.GeoPoint, g.WeightedHalfEdge[int]]{Nodes: nodes, Edges: aag.Edges, Offsets: aag.Offsets}
+
+	partitionings := map[string]struct {
+		partition  func() *g.AdjacencyArrayGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]]
+		assignment g.PartitionAssignment
+	}{
+		"grid": {
+			func() *g.AdjacencyArrayGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]] {
+				return GridPartitioning(aag, 4, 4)
+			},
+			GridAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](geoGraph, 4, 4),
+		},
+		"kd": {
+			func() *g.AdjacencyArrayGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]] { return KdPartitioning(aag, 4) },
+			KdAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](geoGraph, 4),
+		},
+		"multilevel": {
+			func() *g.AdjacencyArrayGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]] {
+				return MultilevelPartitioning(aag, 4, 0.05, 1)
+			},
+			MultilevelAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](geoGraph, 4, 0.05, 1),
+		},
+		"natural cut": {
+			func() *g.AdjacencyArrayGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]] {
+				return NaturalCutPartitioning(aag, 4, 0.05, 1)
+			},
+			NaturalCutAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](geoGraph, 4, 0.05, 1),
+		},
+		"inertial flow": {
+			func() *g.AdjacencyArrayGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]] {
+				return InertialFlowPartitioning(aag, 2)
+			},
+			InertialFlowAssignment[g.GeoPoint, g.WeightedHalfEdge[int]](geoGraph, 2),
+		},
+	}
+	for name, partitioning := range partitionings {
+		partitions := g.ExtractPartitions[g.PartGeoPoint, g.WeightedHalfEdge[int]](partitioning.partition())
+		for i := range partitions {
+			if partitions[i] != partitioning.assignment[i] {
+				t.Fatalf("[%s] Expected partition %d of node %d, got %d", name, partitions[i], i, partitioning.assignment[i])
+			}
+		}
+	}
+}
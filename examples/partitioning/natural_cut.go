@@ -0,0 +1,202 @@
+package partitioning
+
+import (
+	"fmt"
+	"math/rand"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+// Parameters of the natural cut detection, cf. Delling et al.: "Graph Partitioning with Natural Cuts", 2011 (PUNCH)
+const (
+	naturalCutTreeFactor = 1  // the tree of each natural cut search has the weight of about naturalCutTreeFactor*U
+	naturalCutCoreFactor = 10 // the core of each natural cut search has the weight of about U/naturalCutCoreFactor
+	naturalCutCoverage   = 2  // number of passes, in each of which every node is contained in the core of a natural cut search
+)
+
+// NaturalCutPartitioning is a preprocessing step for arc flags and computes a partitioning into k regions of balanced size,
+// whose boundaries follow natural cuts, i.e. sparse cuts that separate dense regions of the graph like straits and canals.
+// Like MultilevelPartitioning, it only depends on the structure of the graph.
+//
+// The partitioning follows the two phases of PUNCH (Delling et al.: "Graph Partitioning with Natural Cuts", 2011), where U
+// is the average region size n/k. The filtering phase repeatedly grows a tree of weight U by a breadth-first search from a
+// random center, whose first U/10 nodes form the core, and computes the minimum cut between the core and the neighbors of
+// the tree. The edges of these natural cuts are kept, all other edges are contracted, such that the graph shrinks to a
+// fragment graph of much fewer nodes. The assembly phase partitions the fragment graph into k regions. Unlike PUNCH, whose
+// assembly combines a greedy algorithm with local search, the fragments are assembled by the multilevel recursive bisection
+// of MultilevelPartitioning, which respects the node weights of the fragments.
+//
+// Edges are considered undirected. The size of each region exceeds n/k by a factor of about 1+imbalance at most, unless
+// the fragments do not admit a balanced partitioning. The result is deterministic for a given seed.
+func NaturalCutPartitioning[N g.SettablePartitioner, E g.IHalfEdge](graph *g.AdjacencyArrayGraph[N, E], k int, imbalance float64, seed int64) *g.AdjacencyArrayGraph[N, E] {
+	return g.ApplyPartitions(graph, NaturalCutAssignment[N, E](graph, k, imbalance, seed))
+}
+
+// NaturalCutAssignment computes the natural cut partitioning of a graph of any node type (see NaturalCutPartitioning) as an assignment.
+func NaturalCutAssignment[N any, E g.IHalfEdge](graph g.Graph[N, E], k int, imbalance float64, seed int64) g.PartitionAssignment {
+	if k < 1 || k > maxPartitionCount {
+		panic(fmt.Sprintf("The number of partitions must be in [1, %d]. Got: k=%d", maxPartitionCount, k))
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	wg := newWeightedGraph[N, E](graph)
+	regionSize := (wg.totalWeight() + k - 1) / k
+
+	// filtering phase
+	fragments, fragmentOf := wg.contractFragments(wg.naturalCuts(regionSize, rng))
+
+	// assembly phase
+	fragmentPartitions := make([]g.PartitionId, fragments.nodeCount())
+	partitionRecursively(fragments, allNodes(fragments.nodeCount()), k, 0, recursionImbalance(k, imbalance), rng, fragmentPartitions)
+	assignment := make(g.PartitionAssignment, graph.NodeCount())
+	for u, fragment := range fragmentOf {
+		assignment[u] = fragmentPartitions[fragment]
+	}
+	return assignment
+}
+
+// naturalCuts returns for each edge whether it is part of a natural cut, which is found by natural cut searches from random
+// centers until each node has been contained in a core naturalCutCoverage times.
+func (wg *weightedGraph) naturalCuts(regionSize int, rng *rand.Rand) []bool {
+	n := wg.nodeCount()
+	treeWeight := naturalCutTreeFactor * regionSize
+	coreWeight := regionSize / naturalCutCoreFactor
+	if coreWeight < 1 {
+		coreWeight = 1
+	}
+
+	cut := make([]bool, len(wg.targets))
+	covered := make([]bool, n)
+	local := newPositions(n) // index of each node in the current search, -1 otherwise
+	for pass := 0; pass < naturalCutCoverage; pass++ {
+		for u := range covered {
+			covered[u] = false
+		}
+		for _, center := range rng.Perm(n) {
+			if covered[center] {
+				continue
+			}
+			nodes, treeSize, coreSize := wg.growNaturalCutTree(center, treeWeight, coreWeight, local)
+			for _, u := range nodes[:coreSize] {
+				covered[u] = true
+			}
+			if treeSize < len(nodes) {
+				wg.markNaturalCut(nodes, treeSize, coreSize, local, cut)
+			}
+			for _, u := range nodes {
+				local[u] = -1
+			}
+		}
+	}
+	return cut
+}
+
+// growNaturalCutTree grows a tree from the center in breadth-first order until it reaches the tree weight and returns its nodes,
+// followed by the nodes of the ring, i.e. the neighbors of the tree that are not part of it. The core consists of the first nodes
+// of the tree, which are added until the core weight is reached. local receives the index of each returned node.
+func (wg *weightedGraph) growNaturalCutTree(center int, treeWeight int, coreWeight int, local []int) ([]int, int, int) {
+	nodes := []int{center}
+	local[center] = 0
+	weight, coreSize := wg.nodeWeights[center], 1
+	for head := 0; head < len(nodes) && weight < treeWeight; head++ {
+		u := nodes[head]
+		for i := wg.offsets[u]; i < wg.offsets[u+1] && weight < treeWeight; i++ {
+			if v := wg.targets[i]; local[v] == -1 {
+				if weight < coreWeight {
+					coreSize++
+				}
+				local[v] = len(nodes)
+				nodes = append(nodes, v)
+				weight += wg.nodeWeights[v]
+			}
+		}
+	}
+
+	treeSize := len(nodes)
+	for _, u := range nodes[:treeSize] {
+		for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+			if v := wg.targets[i]; local[v] == -1 {
+				local[v] = len(nodes)
+				nodes = append(nodes, v)
+			}
+		}
+	}
+	return nodes, treeSize, coreSize
+}
+
+// markNaturalCut marks the edges of the minimum cut between the core and the ring of a natural cut search in both directions.
+func (wg *weightedGraph) markNaturalCut(nodes []int, treeSize int, coreSize int, local []int, cut []bool) {
+	// the subgraph induced by the tree and the ring and the index of each of its edges in wg
+	sub := weightedGraph{offsets: make([]int, len(nodes)+1), targets: make([]int, 0), weights: make([]int, 0), nodeWeights: make([]int, len(nodes))}
+	edges := make([]int, 0)
+	sources, sinks := make([]bool, len(nodes)), make([]bool, len(nodes))
+	for a, u := range nodes {
+		for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+			if b := local[wg.targets[i]]; b != -1 {
+				sub.targets = append(sub.targets, b)
+				sub.weights = append(sub.weights, wg.weights[i])
+				edges = append(edges, i)
+			}
+		}
+		sub.nodeWeights[a] = wg.nodeWeights[u]
+		sub.offsets[a+1] = len(sub.targets)
+		sources[a], sinks[a] = a < coreSize, a >= treeSize
+	}
+
+	network := newFlowNetwork(&sub)
+	network.maxFlow(sources, sinks)
+	sides := network.minCut(sources)
+	for a := range nodes {
+		for j := sub.offsets[a]; j < sub.offsets[a+1]; j++ {
+			if sides[a] == 0 && sides[sub.targets[j]] == 1 {
+				cut[edges[j]] = true
+				cut[edges[network.reverse[j]]] = true
+			}
+		}
+	}
+}
+
+// contractFragments contracts the connected components of the graph without the cut edges into the nodes of a fragment graph,
+// whose node weights and edge weights are the sums of the contracted weights, and returns the fragment of each node.
+func (wg *weightedGraph) contractFragments(cut []bool) (*weightedGraph, []int) {
+	n := wg.nodeCount()
+	fragmentOf := newPositions(n)
+	members := make([]int, 0, n) // nodes ordered by fragment in breadth-first order
+	starts := make([]int, 0)     // index of the first member of each fragment
+	for s := 0; s < n; s++ {
+		if fragmentOf[s] != -1 {
+			continue
+		}
+		fragment := len(starts)
+		starts = append(starts, len(members))
+		fragmentOf[s] = fragment
+		members = append(members, s)
+		for head := starts[fragment]; head < len(members); head++ {
+			u := members[head]
+			for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+				if v := wg.targets[i]; !cut[i] && fragmentOf[v] == -1 {
+					fragmentOf[v] = fragment
+					members = append(members, v)
+				}
+			}
+		}
+	}
+	starts = append(starts, n)
+
+	count := len(starts) - 1
+	fragments := weightedGraph{offsets: make([]int, count+1), targets: make([]int, 0), weights: make([]int, 0), nodeWeights: make([]int, count)}
+	positions := newPositions(count)
+	for fragment := 0; fragment < count; fragment++ {
+		start := len(fragments.targets)
+		for _, u := range members[starts[fragment]:starts[fragment+1]] {
+			fragments.nodeWeights[fragment] += wg.nodeWeights[u]
+			for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+				if other := fragmentOf[wg.targets[i]]; other != fragment {
+					fragments.addEdge(positions, start, other, wg.weights[i])
+				}
+			}
+		}
+		fragments.offsets[fragment+1] = len(fragments.targets)
+	}
+	return &fragments, fragmentOf
+}
@@ -0,0 +1,99 @@
+package partitioning
+
+import (
+	"math/rand"
+	"testing"
+
+	g "github.com/dmholtz/graffiti/graph"
+)
+
+func TestNaturalCutPartitioning(t *testing.T) {
+	t.Parallel()
+
+	// two 16x16 grids, which are connected by a single edge (bottleneck) in the upper half
+	alg := gridGraph(16, 32)
+	for row := 0; row < 16; row++ {
+		alg.Edges[row*32+15] = removeEdge(alg.Edges[row*32+15], row*32+16)
+		alg.Edges[row*32+16] = removeEdge(alg.Edges[row*32+16], row*32+15)
+	}
+	connect(alg, 12*32+15, 12*32+16)
+
+	aag := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]](alg)
+	aag = NaturalCutPartitioning(aag, 2, 0.05, 1)
+
+	if cut := cutEdges[g.WeightedHalfEdge[int]](aag); cut != 2 {
+		t.Errorf("Expected 2 cut edges, got %d", cut)
+	}
+	for row := 0; row < 16; row++ {
+		if aag.GetNode(row*32).Partition() == aag.GetNode(row*32+31).Partition() {
+			t.Errorf("Expected the grids in different partitions, got partition %d for nodes %d and %d", aag.GetNode(row*32).Partition(), row*32, row*32+31)
+		}
+	}
+}
+
+func TestNaturalCutBalance(t *testing.T) {
+	t.Parallel()
+
+	aag := g.NewAdjacencyArrayFromGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]](gridGraph(64, 64))
+	k, imbalance := 8, 0.05
+	aag = NaturalCutPartitioning(aag, k, imbalance, 1)
+
+	sizes := make([]int, k)
+	for i := 0; i < aag.NodeCount(); i++ {
+		partition := int(aag.GetNode(i).Partition())
+		if partition >= k {
+			t.Fatalf("Expected partitions in [0, %d), got %d", k, partition)
+		}
+		sizes[partition]++
+	}
+	maxSize := int(float64(aag.NodeCount()) / float64(k) * (1 + imbalance))
+	for partition, size := range sizes {
+		if size == 0 || size > maxSize {
+			t.Errorf("Expected size of partition %d in [1, %d], got %d", partition, maxSize, size)
+		}
+	}
+
+	// a grid has no natural bottlenecks, such that the cut is only slightly worse than the 512 directed edges of 2x4 regions
+	if cut := cutEdges[g.WeightedHalfEdge[int]](aag); cut > 700 {
+		t.Errorf("Expected at most %d cut edges, got %d", 700, cut)
+	}
+}
+
+func TestContractFragments(t *testing.T) {
+	t.Parallel()
+
+	// the natural cuts of two 16x16 grids, which are connected by a single edge, contain the connecting edge
+	alg := gridGraph(16, 32)
+	for row := 0; row < 16; row++ {
+		alg.Edges[row*32+15] = removeEdge(alg.Edges[row*32+15], row*32+16)
+		alg.Edges[row*32+16] = removeEdge(alg.Edges[row*32+16], row*32+15)
+	}
+	connect(alg, 12*32+15, 12*32+16)
+	wg := newWeightedGraph[g.PartGeoPoint, g.WeightedHalfEdge[int]](alg)
+	fragments, fragmentOf := wg.contractFragments(wg.naturalCuts(256, rand.New(rand.NewSource(1))))
+
+	if fragmentOf[12*32+15] == fragmentOf[12*32+16] {
+		t.Errorf("Expected the endpoints of the bottleneck in different fragments, got fragment %d", fragmentOf[12*32+15])
+	}
+	if fragments.nodeCount() >= wg.nodeCount() {
+		t.Errorf("Expected fewer than %d fragments, got %d", wg.nodeCount(), fragments.nodeCount())
+	}
+	// the weights of the fragment graph sum up to the weights of the graph between different fragments
+	if fragments.totalWeight() != wg.totalWeight() {
+		t.Errorf("Expected total node weight %d, got %d", wg.totalWeight(), fragments.totalWeight())
+	}
+	expected, actual := 0, 0
+	for u := 0; u < wg.nodeCount(); u++ {
+		for i := wg.offsets[u]; i < wg.offsets[u+1]; i++ {
+			if fragmentOf[u] != fragmentOf[wg.targets[i]] {
+				expected += wg.weights[i]
+			}
+		}
+	}
+	for _, weight := range fragments.weights {
+		actual += weight
+	}
+	if actual != expected {
+		t.Errorf("Expected total edge weight %d, got %d", expected, actual)
+	}
+}
@@ -8,7 +8,12 @@ import (
 )
 
 // GridPartitioning is a preprocessing step for two-level arc flags and computes the two-level grid partitioning of a graph of GeoPoints.
-func TwoLevelGridPartitioning[E g.IHalfEdge](graph *g.AdjacencyArrayGraph[g.TwoLevelPartGeoPoint, E], l1_lat, l1_lon, l2_lat, l2_lon int) *g.AdjacencyArrayGraph[g.TwoLevelPartGeoPoint, E] {
+func TwoLevelGridPartitioning[N geoTwoLevelPartitioner, E g.IHalfEdge](graph *g.AdjacencyArrayGraph[N, E], l1_lat, l1_lon, l2_lat, l2_lon int) *g.AdjacencyArrayGraph[N, E] {
+	return g.ApplyTwoLevelPartitions(graph, TwoLevelGridAssignment[N, E](graph, l1_lat, l1_lon, l2_lat, l2_lon))
+}
+
+// TwoLevelGridAssignment computes the two-level grid partitioning of a graph of nodes with a location (see TwoLevelGridPartitioning) as an assignment.
+func TwoLevelGridAssignment[N g.Locator, E g.IHalfEdge](graph g.Graph[N, E], l1_lat, l1_lon, l2_lat, l2_lon int) g.TwoLevelPartitionAssignment {
 	if l1_lat*l1_lon > 32 {
 		panic(fmt.Sprintf("32 bit are reserved for level 1 partitions. Got: l1_lat * l1_lon > 32"))
 	}
@@ -19,8 +24,9 @@ func TwoLevelGridPartitioning[E g.IHalfEdge](graph *g.AdjacencyArrayGraph[g.TwoL
 	l_lat := l1_lat * l2_lat
 	l_lon := l1_lon * l2_lon
 
+	assignment := g.NewTwoLevelPartitionAssignment(graph.NodeCount())
 	for i := 0; i < graph.NodeCount(); i++ {
-		geoPoint := graph.GetNode(i)
+		geoPoint := graph.GetNode(i).Location()
 
 		// determine column (lon) and row (lat) index of geoPoint in the grid
 		col := int(math.Min(((geoPoint.Lon + 180) / 360 * float64(l_lon)), float64(l_lon-1)))
@@ -29,11 +35,9 @@ func TwoLevelGridPartitioning[E g.IHalfEdge](graph *g.AdjacencyArrayGraph[g.TwoL
 		level1_partition := (row/l1_lat)*l1_lon + (col / l1_lon)
 		level2_partition := (row%l1_lat)*l2_lon + (col % l1_lon)
 
-		geoPoint.L1Part_ = g.PartitionId(level1_partition)
-		geoPoint.L2Part_ = g.PartitionId(level2_partition)
-
-		graph.Nodes[i] = geoPoint
+		assignment.L1[i] = g.PartitionId(level1_partition)
+		assignment.L2[i] = g.PartitionId(level2_partition)
 	}
 
-	return graph
+	return assignment
 }
@@ -1,3 +1,5 @@
 module github.com/dmholtz/graffiti
 
 go 1.18
+
+require github.com/klauspost/compress v1.17.2
@@ -64,3 +64,8 @@ func (aag *AdjacencyArrayGraph[N, E]) GetHalfEdgesFrom(id NodeId) []E {
 	}
 	return aag.Edges[aag.Offsets[id]:aag.Offsets[id+1]]
 }
+
+// MemorySize returns the number of bytes occupied by the nodes, offsets and edges of the graph.
+func (aag *AdjacencyArrayGraph[N, E]) MemorySize() int {
+	return sliceSize(aag.Nodes) + sliceSize(aag.Edges) + sliceSize(aag.Offsets)
+}
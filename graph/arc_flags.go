@@ -8,11 +8,45 @@ type Partitioner interface {
 	Partition() PartitionId
 }
 
+// Capability description of a node whose partition can be replaced, e.g. by a partitioning algorithm.
+type SettablePartitioner interface {
+	// SettablePartitioner inherits all capabilities of Partitioner.
+	Partitioner
+	// SetPartition(p) returns a copy of the node that belongs to partition p.
+	SetPartition(p PartitionId) Partitioner
+}
+
 type TwoLevelPartitioner interface {
 	L1Part() PartitionId
 	L2Part() PartitionId
 }
 
+// Capability description of a node whose level 1 and level 2 partitions can be replaced, e.g. by a partitioning algorithm.
+type SettableTwoLevelPartitioner interface {
+	// SettableTwoLevelPartitioner inherits all capabilities of TwoLevelPartitioner.
+	TwoLevelPartitioner
+	// SetPartitions(l1, l2) returns a copy of the node that belongs to level 1 partition l1 and level 2 partition l2.
+	SetPartitions(l1, l2 PartitionId) TwoLevelPartitioner
+}
+
+// Capability description of a node in a hierarchically partitioned graph with an arbitrary number of levels.
+// Level 0 is the coarsest level. The partition of level l > 0 identifies a region within the region of level l-1,
+// i.e. two nodes belong to the same region of level l iff their partitions of the levels 0 to l are equal.
+type MultiLevelPartitioner interface {
+	// LevelCount() returns the number of levels of the partitioning.
+	LevelCount() int
+	// LevelPart(level) returns the partition of the given level.
+	LevelPart(level int) PartitionId
+}
+
+// Capability description of a node whose partitions of all levels can be replaced, e.g. by a partitioning algorithm.
+type SettableMultiLevelPartitioner interface {
+	// SettableMultiLevelPartitioner inherits all capabilities of MultiLevelPartitioner.
+	MultiLevelPartitioner
+	// SetLevelParts(parts) returns a copy of the node that belongs to partition parts[l] on level l.
+	SetLevelParts(parts []PartitionId) MultiLevelPartitioner
+}
+
 // Arc flags are unsigned integers.
 type FlagType interface {
 	uint64 | uint32 | uint16 | uint8
@@ -50,3 +84,25 @@ type ITwoLevelFlaggedHalfEdge[W Weight] interface {
 	L1FlagRange() PartitionId
 	L2FlagRange() PartitionId
 }
+
+// Multi-level arc flags store one 64 bit flag per level, i.e. up to 64 partitions per level and up to eight levels.
+type FlagLevels interface {
+	[1]uint64 | [2]uint64 | [3]uint64 | [4]uint64 | [5]uint64 | [6]uint64 | [7]uint64 | [8]uint64
+}
+
+// Capability description of a weighted half edge with arc flags for each level of a hierarchical partitioning
+type IMultiLevelFlaggedHalfEdge[W Weight] interface {
+	// IMultiLevelFlaggedHalfEdge inherits all capabilities of IWeightedHalfEdge.
+	IWeightedHalfEdge[W]
+
+	// IsLevelFlagged returns true iff the arc flag of the given level for the given partitionId is set.
+	IsLevelFlagged(level int, partitionId PartitionId) bool
+	// AddLevelFlag sets the arc flag of the given level for the given partitionId to 1.
+	AddLevelFlag(level int, partitionId PartitionId) IMultiLevelFlaggedHalfEdge[W]
+	ResetFlags() IMultiLevelFlaggedHalfEdge[W]
+
+	// LevelCount returns the number of levels.
+	LevelCount() int
+	// LevelFlagRange returns the number of partitions per level.
+	LevelFlagRange() PartitionId
+}
@@ -0,0 +1,154 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Policy for handling parallel edges, i.e. multiple edges with the same tail and head node
+type DuplicatePolicy int
+
+const (
+	// Keep the parallel edge with the minimum weight (the first one in case of ties).
+	KEEP_MIN_WEIGHT DuplicatePolicy = iota
+	// Keep the parallel edge that has been added first.
+	KEEP_FIRST DuplicatePolicy = iota
+	// Keep all parallel edges.
+	KEEP_ALL DuplicatePolicy = iota
+	// Fail to build the graph if parallel edges exist.
+	REJECT_DUPLICATES DuplicatePolicy = iota
+)
+
+// Arc is a half edge together with its tail node, i.e. a full description of a directed edge.
+type Arc[E IHalfEdge] struct {
+	Tail NodeId
+	Edge E
+}
+
+// BuildReport summarizes which edges have been modified while building a graph.
+type BuildReport[E IHalfEdge] struct {
+	// Merged lists parallel edges that have been dropped in favor of another edge with the same tail and head.
+	Merged []Arc[E]
+	// Rejected lists edges whose tail or head node does not exist.
+	Rejected []Arc[E]
+}
+
+// AdjacencyArrayBuilder assembles an AdjacencyArrayGraph from nodes and unsorted edges in bulk.
+// Unlike inserting edges into an AdjacencyListGraph one by one, duplicate detection does not scale with the degree of the nodes:
+// edges are sorted by their tail (counting sort) and by their head before parallel edges are resolved.
+type AdjacencyArrayBuilder[N any, E IWeightedHalfEdge[W], W Weight] struct {
+	Nodes  []N
+	Arcs   []Arc[E]
+	Policy DuplicatePolicy
+}
+
+// Constructor method
+func NewAdjacencyArrayBuilder[N any, E IWeightedHalfEdge[W], W Weight](policy DuplicatePolicy) *AdjacencyArrayBuilder[N, E, W] {
+	return &AdjacencyArrayBuilder[N, E, W]{Nodes: make([]N, 0), Arcs: make([]Arc[E], 0), Policy: policy}
+}
+
+// AddNode(n) adds node 'n' to the graph and returns the ID assigned to it.
+func (b *AdjacencyArrayBuilder[N, E, W]) AddNode(n N) NodeId {
+	b.Nodes = append(b.Nodes, n)
+	return len(b.Nodes) - 1
+}
+
+// AddNodes adds multiple nodes to the graph. IDs are assigned in the order of the slice.
+func (b *AdjacencyArrayBuilder[N, E, W]) AddNodes(nodes []N) {
+	b.Nodes = append(b.Nodes, nodes...)
+}
+
+// AddEdge(tail, e) adds the half edge e leaving the node with ID='tail'.
+// Endpoints are validated when the graph is built, such that nodes and edges can be added in any order.
+func (b *AdjacencyArrayBuilder[N, E, W]) AddEdge(tail NodeId, e E) {
+	b.Arcs = append(b.Arcs, Arc[E]{Tail: tail, Edge: e})
+}
+
+// AddEdges adds multiple, not necessarily sorted edges.
+func (b *AdjacencyArrayBuilder[N, E, W]) AddEdges(arcs []Arc[E]) {
+	b.Arcs = append(b.Arcs, arcs...)
+}
+
+// Build sorts the edges into an AdjacencyArrayGraph and resolves parallel edges according to the duplicate policy.
+// The leaving edges of each node are sorted by their head; parallel edges keep the order in which they have been added.
+// Edges with non-existing endpoints are rejected and listed in the report.
+// An error is returned iff the policy is REJECT_DUPLICATES and the edges contain parallel edges.
+func (b *AdjacencyArrayBuilder[N, E, W]) Build() (*AdjacencyArrayGraph[N, E], BuildReport[E], error) {
+	report := BuildReport[E]{Merged: make([]Arc[E], 0), Rejected: make([]Arc[E], 0)}
+	nodeCount := len(b.Nodes)
+
+	// validate endpoints and count the leaving edges of each node
+	offsets := make([]int, nodeCount+1)
+	for _, arc := range b.Arcs {
+		if arc.Tail < 0 || arc.Tail >= nodeCount || arc.Edge.To() < 0 || arc.Edge.To() >= nodeCount {
+			report.Rejected = append(report.Rejected, arc)
+			continue
+		}
+		offsets[arc.Tail+1]++
+	}
+	for i := 0; i < nodeCount; i++ {
+		offsets[i+1] += offsets[i]
+	}
+
+	// counting sort by tail (stable)
+	edges := make([]E, offsets[nodeCount])
+	next := make([]int, nodeCount)
+	copy(next, offsets[:nodeCount])
+	for _, arc := range b.Arcs {
+		if arc.Tail < 0 || arc.Tail >= nodeCount || arc.Edge.To() < 0 || arc.Edge.To() >= nodeCount {
+			continue
+		}
+		edges[next[arc.Tail]] = arc.Edge
+		next[arc.Tail]++
+	}
+
+	// sort each segment by head and resolve parallel edges
+	kept := 0
+	for tail := 0; tail < nodeCount; tail++ {
+		segment := edges[offsets[tail]:offsets[tail+1]]
+		sort.SliceStable(segment, func(i, j int) bool {
+			return segment[i].To() < segment[j].To()
+		})
+
+		offsets[tail] = kept
+		for i := 0; i < len(segment); {
+			// segment[i:j] are parallel edges
+			j := i + 1
+			for j < len(segment) && segment[j].To() == segment[i].To() {
+				j++
+			}
+
+			if b.Policy == KEEP_ALL {
+				kept += copy(edges[kept:], segment[i:j])
+				i = j
+				continue
+			}
+			if b.Policy == REJECT_DUPLICATES && j-i > 1 {
+				return nil, report, fmt.Errorf("parallel edges from node %d to node %d", tail, segment[i].To())
+			}
+
+			best := i
+			if b.Policy == KEEP_MIN_WEIGHT {
+				for k := i + 1; k < j; k++ {
+					if segment[k].Weight() < segment[best].Weight() {
+						best = k
+					}
+				}
+			}
+			for k := i; k < j; k++ {
+				if k != best {
+					report.Merged = append(report.Merged, Arc[E]{Tail: tail, Edge: segment[k]})
+				}
+			}
+			// kept <= offsets of the current segment + i, hence the assignment never overwrites unprocessed edges
+			edges[kept] = segment[best]
+			kept++
+			i = j
+		}
+	}
+	offsets[nodeCount] = kept
+
+	nodes := make([]N, nodeCount)
+	copy(nodes, b.Nodes)
+	return &AdjacencyArrayGraph[N, E]{Nodes: nodes, Edges: edges[:kept:kept], Offsets: offsets}, report, nil
+}
@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"testing"
+)
+
+// buildParallelEdges creates a builder with three nodes, parallel edges between node 0 and node 1 and two invalid edges.
+func buildParallelEdges(policy DuplicatePolicy) *AdjacencyArrayBuilder[Node, WeightedHalfEdge[int], int] {
+	builder := NewAdjacencyArrayBuilder[Node, WeightedHalfEdge[int], int](policy)
+	builder.AddNodes([]Node{{}, {}, {}})
+	builder.AddEdges([]Arc[WeightedHalfEdge[int]]{
+		{Tail: 2, Edge: NewWeightedHalfEdge(0, 3)},
+		{Tail: 0, Edge: NewWeightedHalfEdge(1, 5)},
+		{Tail: 0, Edge: NewWeightedHalfEdge(2, 1)},
+		{Tail: 0, Edge: NewWeightedHalfEdge(1, 4)},
+		{Tail: 3, Edge: NewWeightedHalfEdge(0, 1)},
+		{Tail: 1, Edge: NewWeightedHalfEdge(7, 1)},
+		{Tail: 0, Edge: NewWeightedHalfEdge(1, 4)},
+	})
+	return builder
+}
+
+func TestBuilderPolicies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		policy   DuplicatePolicy
+		expected []WeightedHalfEdge[int] // expected leaving edges of node 0
+		merged   int
+	}{
+		{KEEP_MIN_WEIGHT, []WeightedHalfEdge[int]{NewWeightedHalfEdge(1, 4), NewWeightedHalfEdge(2, 1)}, 2},
+		{KEEP_FIRST, []WeightedHalfEdge[int]{NewWeightedHalfEdge(1, 5), NewWeightedHalfEdge(2, 1)}, 2},
+		{KEEP_ALL, []WeightedHalfEdge[int]{NewWeightedHalfEdge(1, 5), NewWeightedHalfEdge(1, 4), NewWeightedHalfEdge(1, 4), NewWeightedHalfEdge(2, 1)}, 0},
+	}
+
+	for _, testCase := range testCases {
+		aag, report, err := buildParallelEdges(testCase.policy).Build()
+		if err != nil {
+			t.Fatalf("[policy=%d] Unexpected error: %v", testCase.policy, err)
+		}
+		if len(report.Rejected) != 2 {
+			t.Errorf("[policy=%d] %d edges rejected, expected 2", testCase.policy, len(report.Rejected))
+		}
+		if len(report.Merged) != testCase.merged {
+			t.Errorf("[policy=%d] %d edges merged, expected %d", testCase.policy, len(report.Merged), testCase.merged)
+		}
+		if aag.EdgeCount() != len(testCase.expected)+1 {
+			t.Errorf("[policy=%d] Graph has %d edges, expected %d", testCase.policy, aag.EdgeCount(), len(testCase.expected)+1)
+		}
+		edges := aag.GetHalfEdgesFrom(0)
+		if len(edges) != len(testCase.expected) {
+			t.Fatalf("[policy=%d] Unexpected edges of node 0: %v", testCase.policy, edges)
+		}
+		for i := range edges {
+			if edges[i] != testCase.expected[i] {
+				t.Errorf("[policy=%d] Unexpected edges of node 0: got %v, expected %v", testCase.policy, edges, testCase.expected)
+				break
+			}
+		}
+		if len(aag.GetHalfEdgesFrom(1)) != 0 || len(aag.GetHalfEdgesFrom(2)) != 1 {
+			t.Errorf("[policy=%d] Unexpected edges of nodes 1 and 2", testCase.policy)
+		}
+	}
+}
+
+func TestBuilderRejectDuplicates(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := buildParallelEdges(REJECT_DUPLICATES).Build(); err == nil {
+		t.Error("Parallel edges have not been rejected")
+	}
+}
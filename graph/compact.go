@@ -0,0 +1,257 @@
+package graph
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// CompactGraph is a static graph that stores its edges as a struct of arrays:
+// heads and weights are kept in separate, typed slices and heads are stored as 32-bit unsigned integers.
+// Compared to an AdjacencyArrayGraph of WeightedHalfEdge, this avoids the padding of the edge structs and halves the memory of heads and offsets.
+//
+// Since the edges are not stored as structs, GetHalfEdgesFrom assembles a new slice on every call.
+// Performance-critical code should read the edges by a HalfEdgeReader, which decodes them by AppendHalfEdgesFrom into a reused buffer,
+// or prefer the allocation-free accessors Degree, Head and Weight.
+//
+// Implements the Graph interface
+type CompactGraph[N any, W Weight] struct {
+	Nodes   []N      // stores the nodes
+	Offsets []uint32 // use values at index i, i+1 to obtain the segment of adjacent edges for the i-th node
+	Heads   []uint32 // head of each edge
+	Weights []W      // weight of each edge
+}
+
+// Create new CompactGraph as a snapshot from another Graph interface type
+func NewCompactGraph[N any, E IWeightedHalfEdge[W], W Weight](g Graph[N, E]) *CompactGraph[N, W] {
+	checkCompactLimits(g.NodeCount(), g.EdgeCount())
+
+	cg := CompactGraph[N, W]{
+		Nodes:   make([]N, 0, g.NodeCount()),
+		Offsets: make([]uint32, g.NodeCount()+1),
+		Heads:   make([]uint32, 0, g.EdgeCount()),
+		Weights: make([]W, 0, g.EdgeCount()),
+	}
+	for i := 0; i < g.NodeCount(); i++ {
+		cg.Nodes = append(cg.Nodes, g.GetNode(i))
+		for _, halfEdge := range g.GetHalfEdgesFrom(i) {
+			cg.Heads = append(cg.Heads, uint32(halfEdge.To()))
+			cg.Weights = append(cg.Weights, halfEdge.Weight())
+		}
+		cg.Offsets[i+1] = uint32(len(cg.Heads))
+	}
+	return &cg
+}
+
+// checkCompactLimits panics iff node or edge IDs cannot be represented as 32-bit unsigned integers.
+func checkCompactLimits(nodeCount, edgeCount int) {
+	if uint64(nodeCount) > math.MaxUint32 || uint64(edgeCount) > math.MaxUint32 {
+		panic(fmt.Sprintf("Compact graphs support at most %d nodes and edges. Got: %d nodes, %d edges.\n", uint32(math.MaxUint32), nodeCount, edgeCount))
+	}
+}
+
+// NodeCount implements Graph.NodeCount
+func (cg *CompactGraph[N, W]) NodeCount() int {
+	return len(cg.Nodes)
+}
+
+// EdgeCount implements Graph.EdgeCount
+func (cg *CompactGraph[N, W]) EdgeCount() int {
+	return len(cg.Heads)
+}
+
+// GetNode implements Graph.GetNode
+func (cg *CompactGraph[N, W]) GetNode(id NodeId) N {
+	if id < 0 || id >= cg.NodeCount() {
+		panic(fmt.Sprintf("CompactGraph does not contain a node with ID=%d.\n", id))
+	}
+	return cg.Nodes[id]
+}
+
+// GetHalfEdgesFrom implements Graph.GetHalfEdgesFrom
+func (cg *CompactGraph[N, W]) GetHalfEdgesFrom(id NodeId) []WeightedHalfEdge[W] {
+	if id < 0 || id >= cg.NodeCount() {
+		panic(fmt.Sprintf("CompactGraph does not contain a node with ID=%d.\n", id))
+	}
+	return cg.AppendHalfEdgesFrom(id, make([]WeightedHalfEdge[W], 0, cg.Degree(id)))
+}
+
+// AppendHalfEdgesFrom implements IHalfEdgeDecoder.AppendHalfEdgesFrom
+func (cg *CompactGraph[N, W]) AppendHalfEdgesFrom(id NodeId, buffer []WeightedHalfEdge[W]) []WeightedHalfEdge[W] {
+	if id < 0 || id >= cg.NodeCount() {
+		panic(fmt.Sprintf("CompactGraph does not contain a node with ID=%d.\n", id))
+	}
+	for i := cg.Offsets[id]; i < cg.Offsets[id+1]; i++ {
+		buffer = append(buffer, WeightedHalfEdge[W]{To_: NodeId(cg.Heads[i]), Weight_: cg.Weights[i]})
+	}
+	return buffer
+}
+
+// Degree returns the number of edges leaving the node with ID=id.
+// The leaving edges of the node are identified by the edge indices Offsets[id] to Offsets[id+1]-1.
+func (cg *CompactGraph[N, W]) Degree(id NodeId) int {
+	return int(cg.Offsets[id+1] - cg.Offsets[id])
+}
+
+// Head returns the head of the edge with the given edge index.
+func (cg *CompactGraph[N, W]) Head(edgeIndex int) NodeId {
+	return NodeId(cg.Heads[edgeIndex])
+}
+
+// Weight returns the weight of the edge with the given edge index.
+func (cg *CompactGraph[N, W]) Weight(edgeIndex int) W {
+	return cg.Weights[edgeIndex]
+}
+
+// MemorySize returns the number of bytes occupied by the nodes, offsets and edges of the graph.
+func (cg *CompactGraph[N, W]) MemorySize() int {
+	return sliceSize(cg.Nodes) + sliceSize(cg.Offsets) + sliceSize(cg.Heads) + sliceSize(cg.Weights)
+}
+
+// CompactFlaggedGraph extends CompactGraph by a separate, typed slice of arc flags.
+//
+// Implements the Graph interface
+type CompactFlaggedGraph[N any, W Weight, F FlagType] struct {
+	CompactGraph[N, W]
+	Flags []F // arc flag of each edge
+}
+
+// Create new CompactFlaggedGraph as a snapshot from another Graph interface type
+func NewCompactFlaggedGraph[N any, W Weight, F FlagType](g Graph[N, FlaggedHalfEdge[W, F]]) *CompactFlaggedGraph[N, W, F] {
+	cfg := CompactFlaggedGraph[N, W, F]{
+		CompactGraph: *NewCompactGraph[N, FlaggedHalfEdge[W, F], W](g),
+		Flags:        make([]F, 0, g.EdgeCount()),
+	}
+	for i := 0; i < g.NodeCount(); i++ {
+		for _, halfEdge := range g.GetHalfEdgesFrom(i) {
+			cfg.Flags = append(cfg.Flags, halfEdge.Flag)
+		}
+	}
+	return &cfg
+}
+
+// GetHalfEdgesFrom implements Graph.GetHalfEdgesFrom
+func (cfg *CompactFlaggedGraph[N, W, F]) GetHalfEdgesFrom(id NodeId) []FlaggedHalfEdge[W, F] {
+	if id < 0 || id >= cfg.NodeCount() {
+		panic(fmt.Sprintf("CompactFlaggedGraph does not contain a node with ID=%d.\n", id))
+	}
+	return cfg.AppendHalfEdgesFrom(id, make([]FlaggedHalfEdge[W, F], 0, cfg.Degree(id)))
+}
+
+// AppendHalfEdgesFrom implements IHalfEdgeDecoder.AppendHalfEdgesFrom
+func (cfg *CompactFlaggedGraph[N, W, F]) AppendHalfEdgesFrom(id NodeId, buffer []FlaggedHalfEdge[W, F]) []FlaggedHalfEdge[W, F] {
+	if id < 0 || id >= cfg.NodeCount() {
+		panic(fmt.Sprintf("CompactFlaggedGraph does not contain a node with ID=%d.\n", id))
+	}
+	for i := cfg.Offsets[id]; i < cfg.Offsets[id+1]; i++ {
+		buffer = append(buffer, FlaggedHalfEdge[W, F]{To_: NodeId(cfg.Heads[i]), Weight_: cfg.Weights[i], Flag: cfg.Flags[i]})
+	}
+	return buffer
+}
+
+// IsFlagged returns true iff the arc flag of the edge with the given edge index is set for partition p.
+func (cfg *CompactFlaggedGraph[N, W, F]) IsFlagged(edgeIndex int, p PartitionId) bool {
+	return (cfg.Flags[edgeIndex] & (1 << p)) > 0
+}
+
+// MemorySize returns the number of bytes occupied by the nodes, offsets, edges and arc flags of the graph.
+func (cfg *CompactFlaggedGraph[N, W, F]) MemorySize() int {
+	return cfg.CompactGraph.MemorySize() + sliceSize(cfg.Flags)
+}
+
+// VarintGraph is a variant of CompactGraph that compresses the heads of the edges:
+// Each head is stored as the difference to the previous head of the same node (or to the node itself for the first edge),
+// zigzag- and varint-encoded into a byte stream. If neighboring nodes have similar IDs, e.g. after reordering the graph, most heads occupy one or two bytes.
+//
+// Implements the Graph interface
+type VarintGraph[N any, W Weight] struct {
+	Nodes       []N      // stores the nodes
+	Offsets     []uint32 // use values at index i, i+1 to obtain the segment of weights for the i-th node
+	HeadOffsets []uint32 // use values at index i, i+1 to obtain the segment of encoded heads for the i-th node
+	HeadBytes   []byte   // delta- and varint-encoded heads
+	Weights     []W      // weight of each edge
+}
+
+// Create new VarintGraph as a snapshot from another Graph interface type
+func NewVarintGraph[N any, E IWeightedHalfEdge[W], W Weight](g Graph[N, E]) *VarintGraph[N, W] {
+	checkCompactLimits(g.NodeCount(), g.EdgeCount())
+
+	vg := VarintGraph[N, W]{
+		Nodes:       make([]N, 0, g.NodeCount()),
+		Offsets:     make([]uint32, g.NodeCount()+1),
+		HeadOffsets: make([]uint32, g.NodeCount()+1),
+		HeadBytes:   make([]byte, 0, g.EdgeCount()),
+		Weights:     make([]W, 0, g.EdgeCount()),
+	}
+	buffer := make([]byte, binary.MaxVarintLen64)
+	for i := 0; i < g.NodeCount(); i++ {
+		vg.Nodes = append(vg.Nodes, g.GetNode(i))
+		previous := i
+		for _, halfEdge := range g.GetHalfEdgesFrom(i) {
+			n := binary.PutVarint(buffer, int64(halfEdge.To()-previous))
+			vg.HeadBytes = append(vg.HeadBytes, buffer[:n]...)
+			vg.Weights = append(vg.Weights, halfEdge.Weight())
+			previous = halfEdge.To()
+		}
+		if uint64(len(vg.HeadBytes)) > math.MaxUint32 {
+			panic("VarintGraph supports at most 4 GiB of encoded heads.")
+		}
+		vg.Offsets[i+1] = uint32(len(vg.Weights))
+		vg.HeadOffsets[i+1] = uint32(len(vg.HeadBytes))
+	}
+	return &vg
+}
+
+// NodeCount implements Graph.NodeCount
+func (vg *VarintGraph[N, W]) NodeCount() int {
+	return len(vg.Nodes)
+}
+
+// EdgeCount implements Graph.EdgeCount
+func (vg *VarintGraph[N, W]) EdgeCount() int {
+	return len(vg.Weights)
+}
+
+// GetNode implements Graph.GetNode
+func (vg *VarintGraph[N, W]) GetNode(id NodeId) N {
+	if id < 0 || id >= vg.NodeCount() {
+		panic(fmt.Sprintf("VarintGraph does not contain a node with ID=%d.\n", id))
+	}
+	return vg.Nodes[id]
+}
+
+// GetHalfEdgesFrom implements Graph.GetHalfEdgesFrom
+func (vg *VarintGraph[N, W]) GetHalfEdgesFrom(id NodeId) []WeightedHalfEdge[W] {
+	if id < 0 || id >= vg.NodeCount() {
+		panic(fmt.Sprintf("VarintGraph does not contain a node with ID=%d.\n", id))
+	}
+	return vg.AppendHalfEdgesFrom(id, make([]WeightedHalfEdge[W], 0, vg.Offsets[id+1]-vg.Offsets[id]))
+}
+
+// AppendHalfEdgesFrom implements IHalfEdgeDecoder.AppendHalfEdgesFrom
+func (vg *VarintGraph[N, W]) AppendHalfEdgesFrom(id NodeId, buffer []WeightedHalfEdge[W]) []WeightedHalfEdge[W] {
+	if id < 0 || id >= vg.NodeCount() {
+		panic(fmt.Sprintf("VarintGraph does not contain a node with ID=%d.\n", id))
+	}
+	encoded := vg.HeadBytes[vg.HeadOffsets[id]:vg.HeadOffsets[id+1]]
+	head := id
+	for i := vg.Offsets[id]; i < vg.Offsets[id+1]; i++ {
+		delta, n := binary.Varint(encoded)
+		encoded = encoded[n:]
+		head += NodeId(delta)
+		buffer = append(buffer, WeightedHalfEdge[W]{To_: head, Weight_: vg.Weights[i]})
+	}
+	return buffer
+}
+
+// MemorySize returns the number of bytes occupied by the nodes, offsets and edges of the graph.
+func (vg *VarintGraph[N, W]) MemorySize() int {
+	return sliceSize(vg.Nodes) + sliceSize(vg.Offsets) + sliceSize(vg.HeadOffsets) + sliceSize(vg.HeadBytes) + sliceSize(vg.Weights)
+}
+
+// sliceSize returns the number of bytes occupied by the elements of a slice.
+func sliceSize[T any](slice []T) int {
+	var t T
+	return len(slice) * int(reflect.TypeOf(&t).Elem().Size())
+}
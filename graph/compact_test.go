@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestCompactGraphs(t *testing.T) {
+	t.Parallel()
+
+	aag := buildTestGraph()
+	graphs := map[string]Graph[GeoPoint, WeightedHalfEdge[int]]{
+		"compact": NewCompactGraph[GeoPoint, WeightedHalfEdge[int], int](aag),
+		"varint":  NewVarintGraph[GeoPoint, WeightedHalfEdge[int], int](aag),
+	}
+
+	for name, compactGraph := range graphs {
+		if compactGraph.NodeCount() != aag.NodeCount() || compactGraph.EdgeCount() != aag.EdgeCount() {
+			t.Errorf("[%s] Graph has %d nodes and %d edges, expected %d and %d", name, compactGraph.NodeCount(), compactGraph.EdgeCount(), aag.NodeCount(), aag.EdgeCount())
+			continue
+		}
+		for i := 0; i < aag.NodeCount(); i++ {
+			if compactGraph.GetNode(i) != aag.GetNode(i) {
+				t.Errorf("[%s] Different node with ID=%d", name, i)
+			}
+			expected := aag.GetHalfEdgesFrom(i)
+			edges := compactGraph.GetHalfEdgesFrom(i)
+			if len(edges) != len(expected) {
+				t.Errorf("[%s] Node %d has %d leaving edges, expected %d", name, i, len(edges), len(expected))
+				continue
+			}
+			for j := range expected {
+				if edges[j] != expected[j] {
+					t.Errorf("[%s] Different edge: got %v, expected %v", name, edges[j], expected[j])
+				}
+			}
+		}
+	}
+}
+
+func TestCompactFlaggedGraph(t *testing.T) {
+	t.Parallel()
+
+	alg := &AdjacencyListGraph[Node, FlaggedHalfEdge[int, uint16]]{}
+	alg.AppendNode(Node{})
+	alg.AppendNode(Node{})
+	alg.InsertHalfEdge(0, FlaggedHalfEdge[int, uint16]{To_: 1, Weight_: 4, Flag: 0b101})
+	alg.InsertHalfEdge(1, FlaggedHalfEdge[int, uint16]{To_: 0, Weight_: 4, Flag: 0b010})
+
+	cfg := NewCompactFlaggedGraph[Node, int, uint16](alg)
+	if edge := cfg.GetHalfEdgesFrom(0)[0]; edge != alg.GetHalfEdgesFrom(0)[0] {
+		t.Errorf("Different edge: got %v, expected %v", edge, alg.GetHalfEdgesFrom(0)[0])
+	}
+	if !cfg.IsFlagged(0, 2) || cfg.IsFlagged(0, 1) || !cfg.IsFlagged(1, 1) {
+		t.Errorf("Unexpected arc flags: %v", cfg.Flags)
+	}
+}
+
+// not parallel, since AllocsPerRun must not be called during parallel tests
+func TestHalfEdgeReader(t *testing.T) {
+	aag := buildTestGraph()
+	graphs := map[string]Graph[GeoPoint, WeightedHalfEdge[int]]{
+		"adjacency array": aag,
+		"compact":         NewCompactGraph[GeoPoint, WeightedHalfEdge[int], int](aag),
+		"varint":          NewVarintGraph[GeoPoint, WeightedHalfEdge[int], int](aag),
+	}
+
+	for name, graph := range graphs {
+		reader := NewHalfEdgeReader(graph)
+		for i := 0; i < aag.NodeCount(); i++ {
+			expected := aag.GetHalfEdgesFrom(i)
+			edges := reader.From(i)
+			if len(edges) != len(expected) {
+				t.Errorf("[%s] Node %d has %d leaving edges, expected %d", name, i, len(edges), len(expected))
+				continue
+			}
+			for j := range expected {
+				if edges[j] != expected[j] {
+					t.Errorf("[%s] Different edge: got %v, expected %v", name, edges[j], expected[j])
+				}
+			}
+		}
+
+		// the buffer of the reader is reused once it has grown to the largest degree
+		allocs := testing.AllocsPerRun(10, func() {
+			for i := 0; i < graph.NodeCount(); i++ {
+				reader.From(i)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("[%s] Expected no allocations, got %v", name, allocs)
+		}
+	}
+}
@@ -13,6 +13,16 @@ type GeoPoint struct {
 	Lon float64
 }
 
+// Capability description of a node with a position in the geographic coordinate system
+type Locator interface {
+	Location() GeoPoint
+}
+
+// Location implements Locator.Location
+func (p GeoPoint) Location() GeoPoint {
+	return p
+}
+
 // Implementation of a GeoPoint node for a partitioned graph
 type PartGeoPoint struct {
 	GeoPoint
@@ -24,6 +34,12 @@ func (pgp PartGeoPoint) Partition() PartitionId {
 	return pgp.Partition_
 }
 
+// SetPartition implements SettablePartitioner.SetPartition
+func (pgp PartGeoPoint) SetPartition(p PartitionId) Partitioner {
+	pgp.Partition_ = p
+	return pgp
+}
+
 // Implementation of a GeoPoint node for a two level partitioned graph
 type TwoLevelPartGeoPoint struct {
 	GeoPoint
@@ -41,6 +57,35 @@ func (pgp TwoLevelPartGeoPoint) L2Part() PartitionId {
 	return pgp.L2Part_
 }
 
+// SetPartitions implements SettableTwoLevelPartitioner.SetPartitions
+func (pgp TwoLevelPartGeoPoint) SetPartitions(l1, l2 PartitionId) TwoLevelPartitioner {
+	pgp.L1Part_ = l1
+	pgp.L2Part_ = l2
+	return pgp
+}
+
+// Implementation of a GeoPoint node for a hierarchically partitioned graph with an arbitrary number of levels
+type MultiLevelPartGeoPoint struct {
+	GeoPoint
+	Parts_ []PartitionId // partition of each level, starting with the coarsest level
+}
+
+// LevelCount implements MultiLevelPartitioner.LevelCount
+func (pgp MultiLevelPartGeoPoint) LevelCount() int {
+	return len(pgp.Parts_)
+}
+
+// LevelPart implements MultiLevelPartitioner.LevelPart
+func (pgp MultiLevelPartGeoPoint) LevelPart(level int) PartitionId {
+	return pgp.Parts_[level]
+}
+
+// SetLevelParts implements SettableMultiLevelPartitioner.SetLevelParts
+func (pgp MultiLevelPartGeoPoint) SetLevelParts(parts []PartitionId) MultiLevelPartitioner {
+	pgp.Parts_ = append(make([]PartitionId, 0, len(parts)), parts...)
+	return pgp
+}
+
 // Edge types
 
 // Simple implementation of a weighted half edge without any additional metadata
@@ -64,6 +109,18 @@ func (e WeightedHalfEdge[W]) Weight() W {
 	return e.Weight_
 }
 
+// SetTo implements IRetargetableHalfEdge.SetTo
+func (e WeightedHalfEdge[W]) SetTo(to NodeId) IHalfEdge {
+	e.To_ = to
+	return e
+}
+
+// SetWeight implements IReweightableHalfEdge.SetWeight
+func (e WeightedHalfEdge[W]) SetWeight(weight W) IHalfEdge {
+	e.Weight_ = weight
+	return e
+}
+
 // Simple implementation of a weighted half edge with unsigned integer arc flag.
 type FlaggedHalfEdge[W Weight, F FlagType] struct {
 	// TODO revert to nested struct once bug in golang has been fixed
@@ -82,6 +139,18 @@ func (fhe FlaggedHalfEdge[W, F]) Weight() W {
 	return fhe.Weight_
 }
 
+// SetTo implements IRetargetableHalfEdge.SetTo
+func (fhe FlaggedHalfEdge[W, F]) SetTo(to NodeId) IHalfEdge {
+	fhe.To_ = to
+	return fhe
+}
+
+// SetWeight implements IReweightableHalfEdge.SetWeight
+func (fhe FlaggedHalfEdge[W, F]) SetWeight(weight W) IHalfEdge {
+	fhe.Weight_ = weight
+	return fhe
+}
+
 // IsFlagged implements IFlaggedHalfEdge.IsFlagged
 func (fhe FlaggedHalfEdge[W, F]) IsFlagged(p PartitionId) bool {
 	return (fhe.Flag & (1 << p)) > 0
@@ -123,6 +192,18 @@ func (fhe TwoLevelFlaggedHalfEdge[W, F1, F2]) Weight() W {
 	return fhe.Weight_
 }
 
+// SetTo implements IRetargetableHalfEdge.SetTo
+func (fhe TwoLevelFlaggedHalfEdge[W, F1, F2]) SetTo(to NodeId) IHalfEdge {
+	fhe.To_ = to
+	return fhe
+}
+
+// SetWeight implements IReweightableHalfEdge.SetWeight
+func (fhe TwoLevelFlaggedHalfEdge[W, F1, F2]) SetWeight(weight W) IHalfEdge {
+	fhe.Weight_ = weight
+	return fhe
+}
+
 // IsL1Flagged implements ITwoLevelFlaggedHalfEdge.IsL1Flagged
 func (fhe TwoLevelFlaggedHalfEdge[W, F1, F2]) IsL1Flagged(p PartitionId) bool {
 	return (fhe.L1Flag & (1 << p)) > 0
@@ -160,6 +241,63 @@ func (fhe TwoLevelFlaggedHalfEdge[W, F1, F2]) L2FlagRange() PartitionId {
 	return PartitionId(reflect.TypeOf(fhe.L2Flag).Bits())
 }
 
+// Implementation of a half edge with a 64 bit arc flag for each level of a hierarchical partitioning, e.g. L=[3]uint64 for three levels
+type MultiLevelFlaggedHalfEdge[W Weight, L FlagLevels] struct {
+	To_     int
+	Weight_ W
+	Flags   L
+}
+
+// To implements IMultiLevelFlaggedHalfEdge.To
+func (fhe MultiLevelFlaggedHalfEdge[W, L]) To() NodeId {
+	return fhe.To_
+}
+
+// Weight implements IMultiLevelFlaggedHalfEdge.Weight
+func (fhe MultiLevelFlaggedHalfEdge[W, L]) Weight() W {
+	return fhe.Weight_
+}
+
+// SetTo implements IRetargetableHalfEdge.SetTo
+func (fhe MultiLevelFlaggedHalfEdge[W, L]) SetTo(to NodeId) IHalfEdge {
+	fhe.To_ = to
+	return fhe
+}
+
+// SetWeight implements IReweightableHalfEdge.SetWeight
+func (fhe MultiLevelFlaggedHalfEdge[W, L]) SetWeight(weight W) IHalfEdge {
+	fhe.Weight_ = weight
+	return fhe
+}
+
+// IsLevelFlagged implements IMultiLevelFlaggedHalfEdge.IsLevelFlagged
+func (fhe MultiLevelFlaggedHalfEdge[W, L]) IsLevelFlagged(level int, p PartitionId) bool {
+	return (fhe.Flags[level] & (1 << p)) > 0
+}
+
+// AddLevelFlag implements IMultiLevelFlaggedHalfEdge.AddLevelFlag
+func (fhe MultiLevelFlaggedHalfEdge[W, L]) AddLevelFlag(level int, p PartitionId) IMultiLevelFlaggedHalfEdge[W] {
+	fhe.Flags[level] = fhe.Flags[level] | (1 << p)
+	return fhe
+}
+
+// ResetFlags implements IMultiLevelFlaggedHalfEdge.ResetFlags
+func (fhe MultiLevelFlaggedHalfEdge[W, L]) ResetFlags() IMultiLevelFlaggedHalfEdge[W] {
+	var flags L
+	fhe.Flags = flags
+	return fhe
+}
+
+// LevelCount implements IMultiLevelFlaggedHalfEdge.LevelCount
+func (fhe MultiLevelFlaggedHalfEdge[W, L]) LevelCount() int {
+	return len(fhe.Flags)
+}
+
+// LevelFlagRange implements IMultiLevelFlaggedHalfEdge.LevelFlagRange
+func (fhe MultiLevelFlaggedHalfEdge[W, L]) LevelFlagRange() PartitionId {
+	return 64
+}
+
 // Example of custom half edge with large arc flags (128 bit)
 type LargeFlaggedHalfEdge[W Weight] struct {
 	// TODO revert to nested struct once bug in golang has been fixed
@@ -179,6 +317,18 @@ func (lfe LargeFlaggedHalfEdge[W]) Weight() W {
 	return lfe.Weight_
 }
 
+// SetTo implements IRetargetableHalfEdge.SetTo
+func (lfe LargeFlaggedHalfEdge[W]) SetTo(to NodeId) IHalfEdge {
+	lfe.To_ = to
+	return lfe
+}
+
+// SetWeight implements IReweightableHalfEdge.SetWeight
+func (lfe LargeFlaggedHalfEdge[W]) SetWeight(weight W) IHalfEdge {
+	lfe.Weight_ = weight
+	return lfe
+}
+
 // IsFlagged implements IFlaggedHalfEdge.IsFlagged
 func (lfe LargeFlaggedHalfEdge[W]) IsFlagged(p PartitionId) bool {
 	if p < 64 {
@@ -229,6 +379,18 @@ func (fhe B256FlaggedHalfEdge[W]) Weight() W {
 	return fhe.Weight_
 }
 
+// SetTo implements IRetargetableHalfEdge.SetTo
+func (fhe B256FlaggedHalfEdge[W]) SetTo(to NodeId) IHalfEdge {
+	fhe.To_ = to
+	return fhe
+}
+
+// SetWeight implements IReweightableHalfEdge.SetWeight
+func (fhe B256FlaggedHalfEdge[W]) SetWeight(weight W) IHalfEdge {
+	fhe.Weight_ = weight
+	return fhe
+}
+
 // IsFlagged implements IFlaggedHalfEdge.IsFlagged
 func (fhe B256FlaggedHalfEdge[W]) IsFlagged(p PartitionId) bool {
 	sec := p >> 6 // division by 64
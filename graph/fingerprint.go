@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// Fingerprint computes a 64-bit hash of the topology and the edge weights of a graph.
+// Nodes are identified by their IDs, such that renumbering the nodes changes the fingerprint,
+// whereas the order of the edges that leave a node does not matter. Node data (e.g. coordinates) is not part of the fingerprint.
+//
+// Preprocessing results that depend on the distances in a graph, e.g. landmark distances, store the fingerprint of the graph
+// they have been computed for, such that stale data can be detected.
+func Fingerprint[N any, E IWeightedHalfEdge[W], W Weight](graph Graph[N, E]) uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 8)
+	write := func(value uint64) {
+		binary.LittleEndian.PutUint64(buffer, value)
+		hash.Write(buffer)
+	}
+
+	write(uint64(graph.NodeCount()))
+	write(uint64(graph.EdgeCount()))
+	for id := 0; id < graph.NodeCount(); id++ {
+		edges := graph.GetHalfEdgesFrom(id)
+		// the sum of mixed edge hashes is independent of the order of the edges
+		var sum uint64
+		for _, edge := range edges {
+			sum += mix(uint64(edge.To())*0x9e3779b97f4a7c15 ^ weightBits(edge.Weight()))
+		}
+		write(uint64(len(edges)))
+		write(sum)
+	}
+	return hash.Sum64()
+}
+
+// weightBits returns the bit pattern of a weight.
+func weightBits[W Weight](weight W) uint64 {
+	switch w := any(weight).(type) {
+	case float64:
+		return math.Float64bits(w)
+	case int:
+		return uint64(w)
+	}
+	return 0
+}
+
+// mix is the finalizer of the SplitMix64 generator, which distributes similar inputs uniformly.
+func mix(x uint64) uint64 {
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// ContentHash computes a 64-bit hash of the entire content of a graph, i.e. the nodes and the edges in the order of their IDs,
+// including node data and all fields of the edges such as arc flags. Two graphs have the same content hash iff they are
+// identical with high probability. Node and edge types may consist of numbers, booleans, strings, arrays, slices and structs.
+func ContentHash[N any, E IHalfEdge](graph Graph[N, E]) uint64 {
+	hash := fnv.New64a()
+	buffer := make([]byte, 0, 256)
+	flush := func() {
+		hash.Write(buffer)
+		buffer = buffer[:0]
+	}
+
+	buffer = appendUint64(buffer, uint64(graph.NodeCount()))
+	for id := 0; id < graph.NodeCount(); id++ {
+		buffer = appendValue(buffer, reflect.ValueOf(graph.GetNode(id)))
+		edges := graph.GetHalfEdgesFrom(id)
+		buffer = appendUint64(buffer, uint64(len(edges)))
+		for _, edge := range edges {
+			buffer = appendValue(buffer, reflect.ValueOf(edge))
+			if len(buffer) >= 128 {
+				flush()
+			}
+		}
+	}
+	flush()
+	return hash.Sum64()
+}
+
+// appendValue appends the little-endian representation of a value to b.
+func appendValue(b []byte, value reflect.Value) []byte {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendUint64(b, uint64(value.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendUint64(b, value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return appendUint64(b, math.Float64bits(value.Float()))
+	case reflect.Bool:
+		if value.Bool() {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case reflect.String:
+		b = appendUint64(b, uint64(value.Len()))
+		return append(b, value.String()...)
+	case reflect.Slice:
+		b = appendUint64(b, uint64(value.Len()))
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			b = appendValue(b, value.Index(i))
+		}
+		return b
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			b = appendValue(b, value.Field(i))
+		}
+		return b
+	default:
+		panic(fmt.Sprintf("ContentHash does not support values of kind %s.\n", value.Kind()))
+	}
+}
+
+func appendUint64(b []byte, value uint64) []byte {
+	var buffer [8]byte
+	binary.LittleEndian.PutUint64(buffer[:], value)
+	return append(b, buffer[:]...)
+}
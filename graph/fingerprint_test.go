@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	aag := buildTestGraph()
+	fingerprint := Fingerprint[GeoPoint, WeightedHalfEdge[int], int](aag)
+
+	// independent of the representation, the order of leaving edges and node data
+	cg := NewCompactGraph[GeoPoint, WeightedHalfEdge[int], int](aag)
+	if Fingerprint[GeoPoint, WeightedHalfEdge[int], int](cg) != fingerprint {
+		t.Errorf("Fingerprint of the compact graph differs")
+	}
+	swapped := NewAdjacencyArrayFromGraph[GeoPoint, WeightedHalfEdge[int]](aag)
+	swapped.Edges[0], swapped.Edges[1] = swapped.Edges[1], swapped.Edges[0]
+	swapped.Nodes[0] = GeoPoint{Lat: 1, Lon: 2}
+	if Fingerprint[GeoPoint, WeightedHalfEdge[int], int](swapped) != fingerprint {
+		t.Errorf("Fingerprint depends on the order of the edges or on node data")
+	}
+
+	// sensitive to weights and heads
+	reweighted := NewAdjacencyArrayFromGraph[GeoPoint, WeightedHalfEdge[int]](aag)
+	reweighted.Edges[2].Weight_++
+	retargeted := NewAdjacencyArrayFromGraph[GeoPoint, WeightedHalfEdge[int]](aag)
+	retargeted.Edges[2].To_ = 5
+	for name, changed := range map[string]*AdjacencyArrayGraph[GeoPoint, WeightedHalfEdge[int]]{"weight": reweighted, "head": retargeted} {
+		if Fingerprint[GeoPoint, WeightedHalfEdge[int], int](changed) == fingerprint {
+			t.Errorf("Fingerprint does not change with the %s of an edge", name)
+		}
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	t.Parallel()
+
+	aag := buildTestGraph()
+	hash := ContentHash[GeoPoint, WeightedHalfEdge[int]](aag)
+	if ContentHash[GeoPoint, WeightedHalfEdge[int]](NewAdjacencyArrayFromGraph[GeoPoint, WeightedHalfEdge[int]](aag)) != hash {
+		t.Errorf("Content hash of a copy differs")
+	}
+
+	// sensitive to node data and the order of edges, unlike the fingerprint
+	moved := NewAdjacencyArrayFromGraph[GeoPoint, WeightedHalfEdge[int]](aag)
+	moved.Nodes[3].Lon += 1e-9
+	swapped := NewAdjacencyArrayFromGraph[GeoPoint, WeightedHalfEdge[int]](aag)
+	swapped.Edges[0], swapped.Edges[1] = swapped.Edges[1], swapped.Edges[0]
+	for name, changed := range map[string]*AdjacencyArrayGraph[GeoPoint, WeightedHalfEdge[int]]{"node": moved, "edge order": swapped} {
+		if ContentHash[GeoPoint, WeightedHalfEdge[int]](changed) == hash {
+			t.Errorf("Content hash does not change with the %s", name)
+		}
+	}
+
+	// arc flags are part of the content
+	flagged := NewAdjacencyArrayFromGraph[GeoPoint, FlaggedHalfEdge[int, uint64]](&AdjacencyListGraph[GeoPoint, FlaggedHalfEdge[int, uint64]]{
+		Nodes: []GeoPoint{{}, {}}, Edges: [][]FlaggedHalfEdge[int, uint64]{{{To_: 1, Weight_: 1, Flag: 1}}, {}}, EdgeCount_: 1,
+	})
+	before := ContentHash[GeoPoint, FlaggedHalfEdge[int, uint64]](flagged)
+	flagged.Edges[0].Flag = 3
+	if ContentHash[GeoPoint, FlaggedHalfEdge[int, uint64]](flagged) == before {
+		t.Errorf("Content hash does not change with the arc flags")
+	}
+}
@@ -0,0 +1,131 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+)
+
+// FlagMatrix stores the arc flags of all edges of a graph as a matrix of bitsets with one row per edge and one column per partition.
+// In contrast to the flags of FlaggedHalfEdge, which are limited by the size of the flag type, the width of the matrix is configurable,
+// such that arc flags for hundreds or thousands of partitions can be computed without defining new edge types.
+type FlagMatrix struct {
+	Width int      // number of partitions, i.e. columns of the matrix
+	Words int      // number of 64-bit words per row
+	Bits  []uint64 // rows of the matrix, i.e. the flags of row r are stored in Bits[r*Words : (r+1)*Words]
+}
+
+// NewFlagMatrix creates a matrix of the given number of rows and columns, whose flags are all 0.
+// The method panics iff the width is not within [1, 65535], i.e. the range of PartitionId.
+func NewFlagMatrix(rows int, width int) *FlagMatrix {
+	if width < 1 || width > math.MaxUint16 {
+		panic(fmt.Sprintf("Flag matrix width %d is not within [1, %d]", width, math.MaxUint16))
+	}
+	words := (width + 63) / 64
+	return &FlagMatrix{Width: width, Words: words, Bits: make([]uint64, rows*words)}
+}
+
+// Rows returns the number of rows of the matrix.
+func (m *FlagMatrix) Rows() int {
+	return len(m.Bits) / m.Words
+}
+
+// Row returns the 64-bit words of the given row. The slice shares the memory of the matrix.
+func (m *FlagMatrix) Row(row int) []uint64 {
+	return m.Bits[row*m.Words : (row+1)*m.Words]
+}
+
+// IsSet returns true iff the flag of partition p is set in the given row.
+func (m *FlagMatrix) IsSet(row int, p PartitionId) bool {
+	return m.Bits[row*m.Words+int(p)/64]&(1<<(p%64)) != 0
+}
+
+// Set sets the flag of partition p in the given row.
+func (m *FlagMatrix) Set(row int, p PartitionId) {
+	m.Bits[row*m.Words+int(p)/64] |= 1 << (p % 64)
+}
+
+// Reset clears all flags of the given row.
+func (m *FlagMatrix) Reset(row int) {
+	words := m.Row(row)
+	for i := range words {
+		words[i] = 0
+	}
+}
+
+// Implementation of a weighted half edge, whose arc flags are stored in a row of a flag matrix that is shared by all edges of a graph.
+//
+// Since all copies of an edge refer to the same row, AddFlag and ResetFlag modify the shared matrix rather than the returned copy.
+// In particular, copies of a graph (e.g. by NewAdjacencyArrayFromGraph) share their arc flags with the original graph.
+type MatrixFlaggedHalfEdge[W Weight] struct {
+	To_     int
+	Weight_ W
+	Row     int         // row of the edge in the flag matrix
+	Matrix  *FlagMatrix // flag matrix shared by all edges of the graph
+}
+
+// To implements IFlaggedHalfEdge.To
+func (mfhe MatrixFlaggedHalfEdge[W]) To() NodeId {
+	return mfhe.To_
+}
+
+// Weight implements IFlaggedHalfEdge.Weight
+func (mfhe MatrixFlaggedHalfEdge[W]) Weight() W {
+	return mfhe.Weight_
+}
+
+// SetTo implements IRetargetableHalfEdge.SetTo
+func (mfhe MatrixFlaggedHalfEdge[W]) SetTo(to NodeId) IHalfEdge {
+	mfhe.To_ = to
+	return mfhe
+}
+
+// SetWeight implements IReweightableHalfEdge.SetWeight
+func (mfhe MatrixFlaggedHalfEdge[W]) SetWeight(weight W) IHalfEdge {
+	mfhe.Weight_ = weight
+	return mfhe
+}
+
+// IsFlagged implements IFlaggedHalfEdge.IsFlagged
+func (mfhe MatrixFlaggedHalfEdge[W]) IsFlagged(p PartitionId) bool {
+	return mfhe.Matrix.IsSet(mfhe.Row, p)
+}
+
+// AddFlag implements IFlaggedHalfEdge.AddFlag by setting the flag in the shared matrix.
+func (mfhe MatrixFlaggedHalfEdge[W]) AddFlag(p PartitionId) IFlaggedHalfEdge[W] {
+	mfhe.Matrix.Set(mfhe.Row, p)
+	return mfhe
+}
+
+// ResetFlag implements IFlaggedHalfEdge.ResetFlag by clearing the row of the edge in the shared matrix.
+func (mfhe MatrixFlaggedHalfEdge[W]) ResetFlag() IFlaggedHalfEdge[W] {
+	mfhe.Matrix.Reset(mfhe.Row)
+	return mfhe
+}
+
+// FlagRange implements IFlaggedHalfEdge.FlagRange
+func (mfhe MatrixFlaggedHalfEdge[W]) FlagRange() PartitionId {
+	return PartitionId(mfhe.Matrix.Width)
+}
+
+// NewMatrixFlaggedGraph creates an adjacency array of the given graph, whose edges store their arc flags in the given matrix.
+// The i-th edge of the adjacency array, i.e. the edges ordered by their tail, refers to the i-th row of the matrix.
+// The method panics iff the matrix does not have exactly one row per edge.
+func NewMatrixFlaggedGraph[N any, E IWeightedHalfEdge[W], W Weight](graph Graph[N, E], matrix *FlagMatrix) *AdjacencyArrayGraph[N, MatrixFlaggedHalfEdge[W]] {
+	if matrix.Rows() != graph.EdgeCount() {
+		panic(fmt.Sprintf("Flag matrix contains %d rows, but the graph contains %d edges.\n", matrix.Rows(), graph.EdgeCount()))
+	}
+	aag := &AdjacencyArrayGraph[N, MatrixFlaggedHalfEdge[W]]{
+		Nodes:   make([]N, 0, graph.NodeCount()),
+		Edges:   make([]MatrixFlaggedHalfEdge[W], 0, graph.EdgeCount()),
+		Offsets: make([]int, 0, graph.NodeCount()+1),
+	}
+	for id := 0; id < graph.NodeCount(); id++ {
+		aag.Nodes = append(aag.Nodes, graph.GetNode(id))
+		aag.Offsets = append(aag.Offsets, len(aag.Edges))
+		for _, edge := range graph.GetHalfEdgesFrom(id) {
+			aag.Edges = append(aag.Edges, MatrixFlaggedHalfEdge[W]{To_: edge.To(), Weight_: edge.Weight(), Row: len(aag.Edges), Matrix: matrix})
+		}
+	}
+	aag.Offsets = append(aag.Offsets, len(aag.Edges))
+	return aag
+}
@@ -0,0 +1,59 @@
+package graph
+
+import "testing"
+
+func TestFlagMatrix(t *testing.T) {
+	t.Parallel()
+
+	matrix := NewFlagMatrix(3, 1000)
+	if matrix.Words != 16 || matrix.Rows() != 3 {
+		t.Fatalf("Expected 3 rows of 16 words, got %d rows of %d words", matrix.Rows(), matrix.Words)
+	}
+	matrix.Set(1, 0)
+	matrix.Set(1, 999)
+	matrix.Set(2, 64)
+	for _, flag := range []struct {
+		row       int
+		partition PartitionId
+		expected  bool
+	}{{1, 0, true}, {1, 999, true}, {1, 64, false}, {2, 64, true}, {0, 0, false}, {0, 999, false}} {
+		if matrix.IsSet(flag.row, flag.partition) != flag.expected {
+			t.Errorf("Expected flag (%d, %d) to be %t", flag.row, flag.partition, flag.expected)
+		}
+	}
+	matrix.Reset(1)
+	if matrix.IsSet(1, 0) || matrix.IsSet(1, 999) || !matrix.IsSet(2, 64) {
+		t.Errorf("Expected row 1 to be reset and row 2 to be unchanged")
+	}
+}
+
+func TestMatrixFlaggedGraph(t *testing.T) {
+	t.Parallel()
+
+	base := buildTestGraph()
+	matrix := NewFlagMatrix(base.EdgeCount(), 512)
+	aag := NewMatrixFlaggedGraph[GeoPoint, WeightedHalfEdge[int], int](base, matrix)
+	if aag.NodeCount() != base.NodeCount() || aag.EdgeCount() != base.EdgeCount() {
+		t.Fatalf("Expected %d nodes and %d edges, got %d and %d", base.NodeCount(), base.EdgeCount(), aag.NodeCount(), aag.EdgeCount())
+	}
+	for i, edge := range aag.Edges {
+		if edge.Row != i || edge.To() != base.Edges[i].To() || edge.Weight() != base.Edges[i].Weight() {
+			t.Errorf("Expected edge %d to %d with weight %d, got row %d to %d with weight %d", i, base.Edges[i].To(), base.Edges[i].Weight(), edge.Row, edge.To(), edge.Weight())
+		}
+	}
+	if flagRange := aag.Edges[0].FlagRange(); flagRange != 512 {
+		t.Errorf("Expected flag range 512, got %d", flagRange)
+	}
+
+	// flags are stored in the shared matrix, i.e. they are visible to all copies of an edge
+	var edge IFlaggedHalfEdge[int] = aag.Edges[2]
+	edge = edge.AddFlag(300)
+	if !aag.Edges[2].IsFlagged(300) || !matrix.IsSet(2, 300) || aag.Edges[3].IsFlagged(300) {
+		t.Errorf("Expected flag 300 of edge 2 only")
+	}
+	copied := NewAdjacencyArrayFromGraph[GeoPoint, MatrixFlaggedHalfEdge[int]](aag)
+	copied.Edges[2].ResetFlag()
+	if aag.Edges[2].IsFlagged(300) || edge.IsFlagged(300) {
+		t.Errorf("Expected flag 300 of edge 2 to be reset")
+	}
+}
@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// FlagTable stores each distinct arc flag vector of a graph once, such that edges refer to their flag vector by a small index.
+// Since most edges of a large graph share one of few flag vectors (e.g. all flags set near the boundary of a region or
+// only the own region's flag far away from it), the table is much smaller than a flag vector per edge.
+//
+// The flag vector with index 0 is the empty vector, i.e. no flag is set. A flag table is not safe for concurrent insertions.
+type FlagTable struct {
+	Width   int      // number of partitions, i.e. bits of each vector
+	Words   int      // number of 64-bit words per vector
+	Vectors []uint64 // distinct flag vectors, i.e. the vector with index i is stored in Vectors[i*Words : (i+1)*Words]
+
+	index map[string]uint32 // maps the encoding of a vector to its index
+}
+
+// NewFlagTable creates a table of flag vectors of the given width, which contains the empty vector.
+// The method panics iff the width is not within [1, 65535], i.e. the range of PartitionId.
+func NewFlagTable(width int) *FlagTable {
+	if width < 1 || width > math.MaxUint16 {
+		panic(fmt.Sprintf("Flag table width %d is not within [1, %d]", width, math.MaxUint16))
+	}
+	words := (width + 63) / 64
+	table := &FlagTable{Width: width, Words: words, Vectors: make([]uint64, 0, words), index: make(map[string]uint32)}
+	table.Intern(make([]uint64, words))
+	return table
+}
+
+// Len returns the number of distinct flag vectors.
+func (t *FlagTable) Len() int {
+	return len(t.Vectors) / t.Words
+}
+
+// Vector returns the words of the flag vector with the given index. The slice shares the memory of the table and must not be modified.
+func (t *FlagTable) Vector(index uint32) []uint64 {
+	return t.Vectors[int(index)*t.Words : (int(index)+1)*t.Words]
+}
+
+// IsSet returns true iff the flag of partition p is set in the flag vector with the given index.
+func (t *FlagTable) IsSet(index uint32, p PartitionId) bool {
+	return t.Vectors[int(index)*t.Words+int(p)/64]&(1<<(p%64)) != 0
+}
+
+// Intern returns the index of the given flag vector, which is appended to the table if it is not contained yet.
+// The method panics iff the vector does not consist of Words words.
+func (t *FlagTable) Intern(vector []uint64) uint32 {
+	if len(vector) != t.Words {
+		panic(fmt.Sprintf("Flag vector consists of %d words, but the table stores vectors of %d words.", len(vector), t.Words))
+	}
+	key := make([]byte, 8*len(vector))
+	for i, word := range vector {
+		binary.LittleEndian.PutUint64(key[8*i:], word)
+	}
+	if index, ok := t.index[string(key)]; ok {
+		return index
+	}
+	index := uint32(t.Len())
+	t.Vectors = append(t.Vectors, vector...)
+	t.index[string(key)] = index
+	return index
+}
+
+// Implementation of a weighted half edge, whose arc flags are stored in a flag table that is shared by all edges of a graph.
+//
+// In contrast to MatrixFlaggedHalfEdge, copies of an edge are independent: AddFlag interns the modified flag vector and
+// returns a copy that refers to it, whereas the vector of the original edge remains unchanged. Since each intermediate vector
+// is interned, flags should be computed with another edge type and compressed afterwards by CompressFlags.
+type CompressedFlaggedHalfEdge[W Weight] struct {
+	To_     int
+	Weight_ W
+	Index   uint32     // index of the flag vector in the table
+	Table   *FlagTable // flag table shared by all edges of the graph
+}
+
+// To implements IFlaggedHalfEdge.To
+func (cfhe CompressedFlaggedHalfEdge[W]) To() NodeId {
+	return cfhe.To_
+}
+
+// Weight implements IFlaggedHalfEdge.Weight
+func (cfhe CompressedFlaggedHalfEdge[W]) Weight() W {
+	return cfhe.Weight_
+}
+
+// SetTo implements IRetargetableHalfEdge.SetTo
+func (cfhe CompressedFlaggedHalfEdge[W]) SetTo(to NodeId) IHalfEdge {
+	cfhe.To_ = to
+	return cfhe
+}
+
+// SetWeight implements IReweightableHalfEdge.SetWeight
+func (cfhe CompressedFlaggedHalfEdge[W]) SetWeight(weight W) IHalfEdge {
+	cfhe.Weight_ = weight
+	return cfhe
+}
+
+// IsFlagged implements IFlaggedHalfEdge.IsFlagged
+func (cfhe CompressedFlaggedHalfEdge[W]) IsFlagged(p PartitionId) bool {
+	return cfhe.Table.IsSet(cfhe.Index, p)
+}
+
+// AddFlag implements IFlaggedHalfEdge.AddFlag by interning the flag vector of the edge with the additional flag.
+func (cfhe CompressedFlaggedHalfEdge[W]) AddFlag(p PartitionId) IFlaggedHalfEdge[W] {
+	if cfhe.IsFlagged(p) {
+		return cfhe
+	}
+	vector := append([]uint64(nil), cfhe.Table.Vector(cfhe.Index)...)
+	vector[p/64] |= 1 << (p % 64)
+	cfhe.Index = cfhe.Table.Intern(vector)
+	return cfhe
+}
+
+// ResetFlag implements IFlaggedHalfEdge.ResetFlag by referring to the empty flag vector.
+func (cfhe CompressedFlaggedHalfEdge[W]) ResetFlag() IFlaggedHalfEdge[W] {
+	cfhe.Index = 0
+	return cfhe
+}
+
+// FlagRange implements IFlaggedHalfEdge.FlagRange
+func (cfhe CompressedFlaggedHalfEdge[W]) FlagRange() PartitionId {
+	return PartitionId(cfhe.Table.Width)
+}
+
+// CompressFlags creates an adjacency array of the given graph, whose edges refer to their flag vectors in a new flag table.
+// The width of the table is the flag range of the first edge. Compressing a graph of CompressedFlaggedHalfEdges removes
+// flag vectors that are not used by any edge.
+func CompressFlags[N any, E IFlaggedHalfEdge[W], W Weight](graph Graph[N, E]) *AdjacencyArrayGraph[N, CompressedFlaggedHalfEdge[W]] {
+	width := 1
+	aag := &AdjacencyArrayGraph[N, CompressedFlaggedHalfEdge[W]]{
+		Nodes:   make([]N, 0, graph.NodeCount()),
+		Edges:   make([]CompressedFlaggedHalfEdge[W], 0, graph.EdgeCount()),
+		Offsets: make([]int, 0, graph.NodeCount()+1),
+	}
+	var table *FlagTable
+	var vector []uint64
+	for id := 0; id < graph.NodeCount(); id++ {
+		aag.Nodes = append(aag.Nodes, graph.GetNode(id))
+		aag.Offsets = append(aag.Offsets, len(aag.Edges))
+		for _, edge := range graph.GetHalfEdgesFrom(id) {
+			if table == nil {
+				width = int(edge.FlagRange())
+				table = NewFlagTable(width)
+				vector = make([]uint64, table.Words)
+			}
+			// copy the flags of matrix rows directly, probe all partitions otherwise
+			if matrixEdge, ok := any(edge).(MatrixFlaggedHalfEdge[W]); ok {
+				copy(vector, matrixEdge.Matrix.Row(matrixEdge.Row))
+			} else {
+				for i := range vector {
+					vector[i] = 0
+				}
+				for p := 0; p < width; p++ {
+					if edge.IsFlagged(PartitionId(p)) {
+						vector[p/64] |= 1 << (p % 64)
+					}
+				}
+			}
+			aag.Edges = append(aag.Edges, CompressedFlaggedHalfEdge[W]{To_: edge.To(), Weight_: edge.Weight(), Index: table.Intern(vector), Table: table})
+		}
+	}
+	aag.Offsets = append(aag.Offsets, len(aag.Edges))
+	return aag
+}
@@ -0,0 +1,69 @@
+package graph
+
+import "testing"
+
+func TestCompressedFlaggedHalfEdge(t *testing.T) {
+	t.Parallel()
+
+	table := NewFlagTable(200)
+	if table.Len() != 1 || table.Words != 4 {
+		t.Fatalf("Expected the empty vector of 4 words, got %d vectors of %d words", table.Len(), table.Words)
+	}
+	edge := CompressedFlaggedHalfEdge[int]{To_: 1, Weight_: 5, Table: table}
+
+	// copies are independent
+	flagged := edge.AddFlag(3).AddFlag(199).(CompressedFlaggedHalfEdge[int])
+	if edge.IsFlagged(3) || !flagged.IsFlagged(3) || !flagged.IsFlagged(199) || flagged.IsFlagged(4) {
+		t.Errorf("Expected flags 3 and 199 of the copy only")
+	}
+	// identical flag vectors share their index
+	other := edge.AddFlag(199).AddFlag(3).(CompressedFlaggedHalfEdge[int])
+	if other.Index != flagged.Index || table.Len() != 4 {
+		t.Errorf("Expected index %d and 4 vectors, got %d and %d", flagged.Index, other.Index, table.Len())
+	}
+	if reset := flagged.ResetFlag().(CompressedFlaggedHalfEdge[int]); reset.Index != 0 || reset.IsFlagged(3) {
+		t.Errorf("Expected the empty vector, got index %d", reset.Index)
+	}
+	if flagRange := edge.FlagRange(); flagRange != 200 {
+		t.Errorf("Expected flag range 200, got %d", flagRange)
+	}
+}
+
+func TestCompressFlags(t *testing.T) {
+	t.Parallel()
+
+	base := buildTestGraph()
+	flags := []uint64{1, 1, 3, 1 << 63, 0, 3}
+	aag := &AdjacencyArrayGraph[GeoPoint, FlaggedHalfEdge[int, uint64]]{Nodes: base.Nodes, Offsets: base.Offsets}
+	for i, edge := range base.Edges {
+		aag.Edges = append(aag.Edges, FlaggedHalfEdge[int, uint64]{To_: edge.To(), Weight_: edge.Weight(), Flag: flags[i]})
+	}
+
+	compressed := CompressFlags[GeoPoint, FlaggedHalfEdge[int, uint64], int](aag)
+	if table := compressed.Edges[0].Table; table.Len() != 4 || table.Width != 64 {
+		t.Errorf("Expected 4 vectors of width 64, got %d of width %d", table.Len(), table.Width)
+	}
+	for i, edge := range compressed.Edges {
+		if edge.To() != aag.Edges[i].To() || edge.Weight() != aag.Edges[i].Weight() {
+			t.Errorf("Expected edge to %d with weight %d, got %d with weight %d", aag.Edges[i].To(), aag.Edges[i].Weight(), edge.To(), edge.Weight())
+		}
+		for p := PartitionId(0); p < 64; p++ {
+			if edge.IsFlagged(p) != aag.Edges[i].IsFlagged(p) {
+				t.Errorf("Expected flag %d of edge %d to be %t", p, i, aag.Edges[i].IsFlagged(p))
+			}
+		}
+	}
+	if compressed.Edges[2].Index != compressed.Edges[5].Index || compressed.Edges[4].Index != 0 {
+		t.Errorf("Expected edges 2 and 5 to share their vector and edge 4 to refer to the empty vector")
+	}
+
+	// flags of a flag matrix are compressed as well
+	matrix := NewFlagMatrix(base.EdgeCount(), 1000)
+	maag := NewMatrixFlaggedGraph[GeoPoint, WeightedHalfEdge[int], int](base, matrix)
+	matrix.Set(0, 999)
+	matrix.Set(1, 999)
+	fromMatrix := CompressFlags[GeoPoint, MatrixFlaggedHalfEdge[int], int](maag)
+	if table := fromMatrix.Edges[0].Table; table.Len() != 2 || !fromMatrix.Edges[1].IsFlagged(999) || fromMatrix.Edges[2].IsFlagged(999) {
+		t.Errorf("Expected 2 vectors and flag 999 of edges 0 and 1, got %d vectors", table.Len())
+	}
+}
@@ -1,6 +1,7 @@
 package graph
 
-// A node of a graph is identified by an nonnegative 32 bit integer. Depending on the application, negative node ids might represent errors.
+// A node of a graph is identified by an nonnegative integer. Depending on the application, negative node ids might represent errors.
+// Compact graph representations (e.g. CompactGraph) store node ids as 32 bit unsigned integers.
 type NodeId = int
 
 // Simplest capability description of an outgoing edge without any annotations such as weight.
@@ -9,6 +10,14 @@ type IHalfEdge interface {
 	To() NodeId
 }
 
+// Capability description of a half edge whose head can be replaced, e.g. when the nodes of a graph are renumbered.
+type IRetargetableHalfEdge interface {
+	// IRetargetableHalfEdge inherits all capabilities of IHalfEdge.
+	IHalfEdge
+	// SetTo(to) returns a copy of the edge that points to the node with ID 'to'.
+	SetTo(to NodeId) IHalfEdge
+}
+
 // The weight of an edge can be of any number type that supports addition and subtraction.
 type Weight interface {
 	int | float64
@@ -22,6 +31,14 @@ type IWeightedHalfEdge[W Weight] interface {
 	Weight() W
 }
 
+// Capability description of a weighted half edge whose weight can be replaced, e.g. when a route is closed.
+type IReweightableHalfEdge[W Weight] interface {
+	// IReweightableHalfEdge inherits all capabilities of IWeightedHalfEdge.
+	IWeightedHalfEdge[W]
+	// SetWeight(weight) returns a copy of the edge with the given weight.
+	SetWeight(weight W) IHalfEdge
+}
+
 // Generic interface of a graph
 type Graph[N any, E IHalfEdge] interface {
 	// NodeCount() returns the number of nodes in the graph.
@@ -36,3 +53,35 @@ type Graph[N any, E IHalfEdge] interface {
 	// The method panics iff the graph does not contain a node with ID 'id'.
 	GetHalfEdgesFrom(id NodeId) []E
 }
+
+// Capability description of a graph that does not store its edges as slices (e.g. CompactGraph), such that GetHalfEdgesFrom
+// has to allocate a new slice on every call. Instead, the edges can be decoded into a buffer that is owned by the caller.
+type IHalfEdgeDecoder[E IHalfEdge] interface {
+	// AppendHalfEdgesFrom(id, buffer) appends the edges, which leave the node with ID=id, to the buffer and returns the extended buffer.
+	// The method panics iff the graph does not contain a node with ID 'id'.
+	AppendHalfEdgesFrom(id NodeId, buffer []E) []E
+}
+
+// HalfEdgeReader reads the edges of a graph without allocating a slice per node: graphs implementing IHalfEdgeDecoder decode
+// their edges into a buffer that is reused by all calls, other graphs return the slices of GetHalfEdgesFrom.
+// A HalfEdgeReader is not safe for concurrent use.
+type HalfEdgeReader[N any, E IHalfEdge] struct {
+	graph   Graph[N, E]
+	decoder IHalfEdgeDecoder[E]
+	buffer  []E
+}
+
+// NewHalfEdgeReader creates a reader of the edges of the given graph.
+func NewHalfEdgeReader[N any, E IHalfEdge](graph Graph[N, E]) *HalfEdgeReader[N, E] {
+	decoder, _ := graph.(IHalfEdgeDecoder[E])
+	return &HalfEdgeReader[N, E]{graph: graph, decoder: decoder}
+}
+
+// From returns the edges, which leave the node with ID=id. The slice is only valid until the next call of From.
+func (r *HalfEdgeReader[N, E]) From(id NodeId) []E {
+	if r.decoder == nil {
+		return r.graph.GetHalfEdgesFrom(id)
+	}
+	r.buffer = r.decoder.AppendHalfEdgesFrom(id, r.buffer[:0])
+	return r.buffer
+}
@@ -0,0 +1,224 @@
+package graph
+
+import "fmt"
+
+// PartitionAssignment assigns each node of a graph to a partition, i.e. the node with ID=id belongs to partition a[id].
+//
+// In contrast to the partition of a Partitioner node, an assignment is stored separately from the graph, such that
+// assignments of different partitionings can be stored, loaded and swapped without rewriting the nodes of the graph.
+type PartitionAssignment []PartitionId
+
+// PartitionCount returns the number of partitions, i.e. the largest assigned partition ID plus one.
+func (a PartitionAssignment) PartitionCount() int {
+	count := 0
+	for _, p := range a {
+		if int(p) >= count {
+			count = int(p) + 1
+		}
+	}
+	return count
+}
+
+// TwoLevelPartitionAssignment assigns each node of a graph to a level 1 and a level 2 partition.
+// Both slices have one entry per node.
+type TwoLevelPartitionAssignment struct {
+	L1 PartitionAssignment
+	L2 PartitionAssignment
+}
+
+// NewTwoLevelPartitionAssignment creates an assignment of n nodes to level 1 and level 2 partition 0.
+func NewTwoLevelPartitionAssignment(n int) TwoLevelPartitionAssignment {
+	return TwoLevelPartitionAssignment{L1: make(PartitionAssignment, n), L2: make(PartitionAssignment, n)}
+}
+
+// MultiLevelPartitionAssignment assigns each node of a graph to a partition on each level of a hierarchical partitioning,
+// i.e. the node with ID=id belongs to partition a[l][id] on level l.
+type MultiLevelPartitionAssignment []PartitionAssignment
+
+// NewMultiLevelPartitionAssignment creates an assignment of n nodes to partition 0 on each of the given number of levels.
+func NewMultiLevelPartitionAssignment(n int, levels int) MultiLevelPartitionAssignment {
+	assignment := make(MultiLevelPartitionAssignment, levels)
+	for level := range assignment {
+		assignment[level] = make(PartitionAssignment, n)
+	}
+	return assignment
+}
+
+// ExtractPartitions returns the partitions of the nodes of a partitioned graph as an assignment.
+func ExtractPartitions[N Partitioner, E IHalfEdge](graph Graph[N, E]) PartitionAssignment {
+	assignment := make(PartitionAssignment, graph.NodeCount())
+	for id := range assignment {
+		assignment[id] = graph.GetNode(id).Partition()
+	}
+	return assignment
+}
+
+// ExtractTwoLevelPartitions returns the level 1 and level 2 partitions of the nodes of a two-level partitioned graph as an assignment.
+func ExtractTwoLevelPartitions[N TwoLevelPartitioner, E IHalfEdge](graph Graph[N, E]) TwoLevelPartitionAssignment {
+	assignment := NewTwoLevelPartitionAssignment(graph.NodeCount())
+	for id := range assignment.L1 {
+		assignment.L1[id] = graph.GetNode(id).L1Part()
+		assignment.L2[id] = graph.GetNode(id).L2Part()
+	}
+	return assignment
+}
+
+// ExtractMultiLevelPartitions returns the partitions of all levels of the nodes of a hierarchically partitioned graph as an assignment.
+// The number of levels is determined by the first node.
+func ExtractMultiLevelPartitions[N MultiLevelPartitioner, E IHalfEdge](graph Graph[N, E]) MultiLevelPartitionAssignment {
+	levels := 0
+	if graph.NodeCount() > 0 {
+		levels = graph.GetNode(0).LevelCount()
+	}
+	assignment := NewMultiLevelPartitionAssignment(graph.NodeCount(), levels)
+	for id := 0; id < graph.NodeCount(); id++ {
+		for level := range assignment {
+			assignment[level][id] = graph.GetNode(id).LevelPart(level)
+		}
+	}
+	return assignment
+}
+
+// ApplyPartitions replaces the partition of each node of the graph by its assigned partition and returns the graph.
+// The method panics iff the assignment does not contain exactly one partition per node.
+func ApplyPartitions[N SettablePartitioner, E IHalfEdge](aag *AdjacencyArrayGraph[N, E], assignment PartitionAssignment) *AdjacencyArrayGraph[N, E] {
+	if len(assignment) != aag.NodeCount() {
+		panic(fmt.Sprintf("Assignment contains %d partitions, but the graph contains %d nodes.\n", len(assignment), aag.NodeCount()))
+	}
+	for id, p := range assignment {
+		aag.Nodes[id] = aag.Nodes[id].SetPartition(p).(N)
+	}
+	return aag
+}
+
+// ApplyTwoLevelPartitions replaces the level 1 and level 2 partitions of each node of the graph by its assigned partitions and returns the graph.
+// The method panics iff the assignment does not contain exactly one level 1 and one level 2 partition per node.
+func ApplyTwoLevelPartitions[N SettableTwoLevelPartitioner, E IHalfEdge](aag *AdjacencyArrayGraph[N, E], assignment TwoLevelPartitionAssignment) *AdjacencyArrayGraph[N, E] {
+	if len(assignment.L1) != aag.NodeCount() || len(assignment.L2) != aag.NodeCount() {
+		panic(fmt.Sprintf("Assignment contains %d level 1 and %d level 2 partitions, but the graph contains %d nodes.\n", len(assignment.L1), len(assignment.L2), aag.NodeCount()))
+	}
+	for id := range assignment.L1 {
+		aag.Nodes[id] = aag.Nodes[id].SetPartitions(assignment.L1[id], assignment.L2[id]).(N)
+	}
+	return aag
+}
+
+// ApplyMultiLevelPartitions replaces the partitions of all levels of each node of the graph by its assigned partitions and returns the graph.
+// The method panics iff the assignment does not contain exactly one partition per node on each level.
+func ApplyMultiLevelPartitions[N SettableMultiLevelPartitioner, E IHalfEdge](aag *AdjacencyArrayGraph[N, E], assignment MultiLevelPartitionAssignment) *AdjacencyArrayGraph[N, E] {
+	for level, partitions := range assignment {
+		if len(partitions) != aag.NodeCount() {
+			panic(fmt.Sprintf("Assignment contains %d partitions on level %d, but the graph contains %d nodes.\n", len(partitions), level, aag.NodeCount()))
+		}
+	}
+	parts := make([]PartitionId, len(assignment))
+	for id := range aag.Nodes {
+		for level, partitions := range assignment {
+			parts[level] = partitions[id]
+		}
+		aag.Nodes[id] = aag.Nodes[id].SetLevelParts(parts).(N)
+	}
+	return aag
+}
+
+// PartitionedNode attaches a partition to a node of any type.
+//
+// Implements the SettablePartitioner interface
+type PartitionedNode[N any] struct {
+	Node       N
+	Partition_ PartitionId
+}
+
+// Partition implements Partitioner.Partition
+func (pn PartitionedNode[N]) Partition() PartitionId {
+	return pn.Partition_
+}
+
+// SetPartition implements SettablePartitioner.SetPartition
+func (pn PartitionedNode[N]) SetPartition(p PartitionId) Partitioner {
+	pn.Partition_ = p
+	return pn
+}
+
+// PartitionedGraph is a view of a graph, whose nodes are partitioned by an assignment, such that arc flags can be computed
+// and queried for a graph of any node type. Swapping the assignment does not modify the underlying graph.
+//
+// Implements the Graph interface
+type PartitionedGraph[N any, E IHalfEdge] struct {
+	Graph      Graph[N, E]
+	Assignment PartitionAssignment
+}
+
+// NodeCount implements Graph.NodeCount
+func (pg *PartitionedGraph[N, E]) NodeCount() int {
+	return pg.Graph.NodeCount()
+}
+
+// EdgeCount implements Graph.EdgeCount
+func (pg *PartitionedGraph[N, E]) EdgeCount() int {
+	return pg.Graph.EdgeCount()
+}
+
+// GetNode implements Graph.GetNode
+func (pg *PartitionedGraph[N, E]) GetNode(id NodeId) PartitionedNode[N] {
+	return PartitionedNode[N]{Node: pg.Graph.GetNode(id), Partition_: pg.Assignment[id]}
+}
+
+// GetHalfEdgesFrom implements Graph.GetHalfEdgesFrom
+func (pg *PartitionedGraph[N, E]) GetHalfEdgesFrom(id NodeId) []E {
+	return pg.Graph.GetHalfEdgesFrom(id)
+}
+
+// TwoLevelPartitionedNode attaches a level 1 and a level 2 partition to a node of any type.
+//
+// Implements the SettableTwoLevelPartitioner interface
+type TwoLevelPartitionedNode[N any] struct {
+	Node    N
+	L1Part_ PartitionId
+	L2Part_ PartitionId
+}
+
+// L1Part implements TwoLevelPartitioner.L1Part
+func (pn TwoLevelPartitionedNode[N]) L1Part() PartitionId {
+	return pn.L1Part_
+}
+
+// L2Part implements TwoLevelPartitioner.L2Part
+func (pn TwoLevelPartitionedNode[N]) L2Part() PartitionId {
+	return pn.L2Part_
+}
+
+// SetPartitions implements SettableTwoLevelPartitioner.SetPartitions
+func (pn TwoLevelPartitionedNode[N]) SetPartitions(l1, l2 PartitionId) TwoLevelPartitioner {
+	pn.L1Part_ = l1
+	pn.L2Part_ = l2
+	return pn
+}
+
+// TwoLevelPartitionedGraph is a view of a graph, whose nodes are partitioned by a two-level assignment (see PartitionedGraph).
+//
+// Implements the Graph interface
+type TwoLevelPartitionedGraph[N any, E IHalfEdge] struct {
+	Graph      Graph[N, E]
+	Assignment TwoLevelPartitionAssignment
+}
+
+// NodeCount implements Graph.NodeCount
+func (pg *TwoLevelPartitionedGraph[N, E]) NodeCount() int {
+	return pg.Graph.NodeCount()
+}
+
+// EdgeCount implements Graph.EdgeCount
+func (pg *TwoLevelPartitionedGraph[N, E]) EdgeCount() int {
+	return pg.Graph.EdgeCount()
+}
+
+// GetNode implements Graph.GetNode
+func (pg *TwoLevelPartitionedGraph[N, E]) GetNode(id NodeId) TwoLevelPartitionedNode[N] {
+	return TwoLevelPartitionedNode[N]{Node: pg.Graph.GetNode(id), L1Part_: pg.Assignment.L1[id], L2Part_: pg.Assignment.L2[id]}
+}
+
+// GetHalfEdgesFrom implements Graph.GetHalfEdgesFrom
+func (pg *TwoLevelPartitionedGraph[N, E]) GetHalfEdgesFrom(id NodeId) []E {
+	return pg.Graph.GetHalfEdgesFrom(id)
+}
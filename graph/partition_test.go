@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPartitionAssignment(t *testing.T) {
+	t.Parallel()
+
+	base := buildTestGraph()
+	assignment := PartitionAssignment{0, 1, 0, 1, 0, 2}
+	if count := assignment.PartitionCount(); count != 3 {
+		t.Errorf("Expected 3 partitions, got %d", count)
+	}
+
+	// apply to and extract from a graph of PartGeoPoints
+	nodes := make([]PartGeoPoint, base.NodeCount())
+	for i, node := range base.Nodes {
+		nodes[i] = PartGeoPoint{GeoPoint: node}
+	}
+	aag := ApplyPartitions(&AdjacencyArrayGraph[PartGeoPoint, WeightedHalfEdge[int]]{Nodes: nodes, Edges: base.Edges, Offsets: base.Offsets}, assignment)
+	if extracted := ExtractPartitions[PartGeoPoint, WeightedHalfEdge[int]](aag); !reflect.DeepEqual(extracted, assignment) {
+		t.Errorf("Expected partitions %v, got %v", assignment, extracted)
+	}
+	if aag.GetNode(5).GeoPoint != base.GetNode(5) {
+		t.Errorf("Expected location %v, got %v", base.GetNode(5), aag.GetNode(5).GeoPoint)
+	}
+
+	// a view of the graph of GeoPoints, whose assignment is swapped
+	pg := &PartitionedGraph[GeoPoint, WeightedHalfEdge[int]]{Graph: base, Assignment: assignment}
+	if p := pg.GetNode(3).Partition(); p != 1 {
+		t.Errorf("Expected partition 1, got %d", p)
+	}
+	pg.Assignment = PartitionAssignment{5, 4, 3, 2, 1, 0}
+	if extracted := ExtractPartitions[PartitionedNode[GeoPoint], WeightedHalfEdge[int]](pg); !reflect.DeepEqual(extracted, pg.Assignment) {
+		t.Errorf("Expected partitions %v, got %v", pg.Assignment, extracted)
+	}
+	if pg.GetNode(1).Node != base.GetNode(1) || len(pg.GetHalfEdgesFrom(0)) != 2 {
+		t.Errorf("Expected view of node %v with 2 edges, got %v with %d edges", base.GetNode(1), pg.GetNode(1).Node, len(pg.GetHalfEdgesFrom(0)))
+	}
+}
+
+func TestTwoLevelPartitionAssignment(t *testing.T) {
+	t.Parallel()
+
+	base := buildTestGraph()
+	assignment := TwoLevelPartitionAssignment{L1: PartitionAssignment{0, 1, 0, 1, 0, 1}, L2: PartitionAssignment{0, 0, 1, 1, 2, 2}}
+
+	nodes := make([]TwoLevelPartGeoPoint, base.NodeCount())
+	for i, node := range base.Nodes {
+		nodes[i] = TwoLevelPartGeoPoint{GeoPoint: node}
+	}
+	aag := ApplyTwoLevelPartitions(&AdjacencyArrayGraph[TwoLevelPartGeoPoint, WeightedHalfEdge[int]]{Nodes: nodes, Edges: base.Edges, Offsets: base.Offsets}, assignment)
+	if extracted := ExtractTwoLevelPartitions[TwoLevelPartGeoPoint, WeightedHalfEdge[int]](aag); !reflect.DeepEqual(extracted, assignment) {
+		t.Errorf("Expected partitions %v, got %v", assignment, extracted)
+	}
+
+	pg := &TwoLevelPartitionedGraph[GeoPoint, WeightedHalfEdge[int]]{Graph: base, Assignment: assignment}
+	if extracted := ExtractTwoLevelPartitions[TwoLevelPartitionedNode[GeoPoint], WeightedHalfEdge[int]](pg); !reflect.DeepEqual(extracted, assignment) {
+		t.Errorf("Expected partitions %v, got %v", assignment, extracted)
+	}
+}
@@ -0,0 +1,230 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Node reordering improves the memory locality of graph searches on static graphs such as AdjacencyArrayGraph:
+// nodes that are close to each other in the graph are stored close to each other in memory.
+//
+// An order is a slice of node IDs, which lists the nodes of a graph in the order in which they are stored after reordering,
+// i.e. order[newId] = oldId. A permutation is the inverse of an order, i.e. permutation[oldId] = newId.
+
+// resolution of the grid on which space-filling curves are computed (2^16 cells per axis)
+const curveOrder = 16
+
+// BfsOrder lists all nodes of the graph in the order in which a breadth-first search starting at node 'source' visits them.
+// Nodes that are not reachable from the source node are appended by starting further searches at the unvisited node with the lowest ID.
+func BfsOrder[N any, E IHalfEdge](graph Graph[N, E], source NodeId) []NodeId {
+	order := make([]NodeId, 0, graph.NodeCount())
+	visited := make([]bool, graph.NodeCount())
+
+	root, cursor := source, 0
+	for len(order) < graph.NodeCount() {
+		visited[root] = true
+		order = append(order, root)
+
+		// the order itself serves as the queue of the search
+		for head := len(order) - 1; head < len(order); head++ {
+			for _, edge := range graph.GetHalfEdgesFrom(order[head]) {
+				if !visited[edge.To()] {
+					visited[edge.To()] = true
+					order = append(order, edge.To())
+				}
+			}
+		}
+
+		// continue with the unvisited node with the lowest ID
+		for cursor < len(visited) && visited[cursor] {
+			cursor++
+		}
+		root = cursor
+	}
+	return order
+}
+
+// DfsOrder lists all nodes of the graph in the order in which a depth-first search starting at node 'source' visits them (preorder).
+// Nodes that are not reachable from the source node are appended by starting further searches at the unvisited node with the lowest ID.
+func DfsOrder[N any, E IHalfEdge](graph Graph[N, E], source NodeId) []NodeId {
+	order := make([]NodeId, 0, graph.NodeCount())
+	visited := make([]bool, graph.NodeCount())
+
+	root, cursor := source, 0
+	for len(order) < graph.NodeCount() {
+		stack := []NodeId{root}
+		for len(stack) > 0 {
+			// pop
+			nodeId := stack[len(stack)-1]
+			stack = stack[0 : len(stack)-1]
+
+			if visited[nodeId] {
+				continue
+			}
+			visited[nodeId] = true
+			order = append(order, nodeId)
+
+			// push in reverse order such that the first leaving edge is visited first
+			edges := graph.GetHalfEdgesFrom(nodeId)
+			for i := len(edges) - 1; i >= 0; i-- {
+				if !visited[edges[i].To()] {
+					stack = append(stack, edges[i].To())
+				}
+			}
+		}
+
+		// continue with the unvisited node with the lowest ID
+		for cursor < len(visited) && visited[cursor] {
+			cursor++
+		}
+		root = cursor
+	}
+	return order
+}
+
+// HilbertOrder lists all nodes of the graph in the order of their position on a Hilbert curve through the geographic coordinate system.
+func HilbertOrder[N Locator, E IHalfEdge](graph Graph[N, E]) []NodeId {
+	return curveSort(graph, hilbertIndex)
+}
+
+// MortonOrder lists all nodes of the graph in the order of their position on a Morton curve (Z-order curve) through the geographic coordinate system.
+func MortonOrder[N Locator, E IHalfEdge](graph Graph[N, E]) []NodeId {
+	return curveSort(graph, mortonIndex)
+}
+
+// curveSort orders the nodes of the graph by their index on a space-filling curve.
+func curveSort[N Locator, E IHalfEdge](graph Graph[N, E], curveIndex func(x, y uint32) uint64) []NodeId {
+	order := make([]NodeId, graph.NodeCount())
+	keys := make([]uint64, graph.NodeCount())
+	for i := 0; i < graph.NodeCount(); i++ {
+		order[i] = i
+		x, y := gridCell(graph.GetNode(i).Location())
+		keys[i] = curveIndex(x, y)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return keys[order[i]] < keys[order[j]]
+	})
+	return order
+}
+
+// gridCell maps a GeoPoint to the column (lon) and row (lat) of a 2^curveOrder x 2^curveOrder grid.
+func gridCell(p GeoPoint) (uint32, uint32) {
+	cells := float64(uint32(1) << curveOrder)
+	x := (p.Lon + 180) / 360 * cells
+	y := (p.Lat + 90) / 180 * cells
+	return clampCell(x), clampCell(y)
+}
+
+func clampCell(v float64) uint32 {
+	if v < 0 {
+		return 0
+	}
+	if last := uint32(1)<<curveOrder - 1; v >= float64(last) {
+		return last
+	}
+	return uint32(v)
+}
+
+// hilbertIndex computes the distance of grid cell (x, y) along the Hilbert curve.
+// cf. https://en.wikipedia.org/wiki/Hilbert_curve
+func hilbertIndex(x, y uint32) uint64 {
+	var d uint64
+	for s := uint32(1) << (curveOrder - 1); s > 0; s /= 2 {
+		var rx, ry uint32
+		if x&s > 0 {
+			rx = 1
+		}
+		if y&s > 0 {
+			ry = 1
+		}
+		d += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+
+		// rotate the quadrant
+		if ry == 0 {
+			if rx == 1 {
+				x = s - 1 - x
+				y = s - 1 - y
+			}
+			x, y = y, x
+		}
+	}
+	return d
+}
+
+// mortonIndex computes the distance of grid cell (x, y) along the Morton curve by interleaving the bits of x and y.
+func mortonIndex(x, y uint32) uint64 {
+	var d uint64
+	for i := 0; i < curveOrder; i++ {
+		d |= uint64((x>>i)&1) << (2 * i)
+		d |= uint64((y>>i)&1) << (2*i + 1)
+	}
+	return d
+}
+
+// PartitionOrder lists all nodes of the graph grouped by their partition.
+// Within each partition, nodes keep their relative order.
+func PartitionOrder[N Partitioner, E IHalfEdge](graph Graph[N, E]) []NodeId {
+	order := make([]NodeId, graph.NodeCount())
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return graph.GetNode(order[i]).Partition() < graph.GetNode(order[j]).Partition()
+	})
+	return order
+}
+
+// Permutation computes the inverse of an order, i.e. a slice that maps every old node ID to its new node ID.
+// The method panics iff order is not a permutation of the node IDs 0 to len(order)-1.
+func Permutation(order []NodeId) []NodeId {
+	permutation := make([]NodeId, len(order))
+	for i := range permutation {
+		permutation[i] = -1
+	}
+	for newId, oldId := range order {
+		if oldId < 0 || oldId >= len(order) || permutation[oldId] != -1 {
+			panic(fmt.Sprintf("Order is not a permutation: invalid or duplicate node ID=%d at position %d.\n", oldId, newId))
+		}
+		permutation[oldId] = newId
+	}
+	return permutation
+}
+
+// ReorderNodes creates a copy of the graph whose nodes are stored in the given order.
+// Nodes, edges and offsets are rearranged consistently and edge heads are rewritten to the new node IDs.
+// The relative order of the leaving edges of each node is preserved.
+//
+// Additionally, the permutation (old node ID -> new node ID) is returned, which allows to rearrange external data such as landmarks or queries.
+func ReorderNodes[N any, E IRetargetableHalfEdge](aag *AdjacencyArrayGraph[N, E], order []NodeId) (*AdjacencyArrayGraph[N, E], []NodeId) {
+	if len(order) != aag.NodeCount() {
+		panic(fmt.Sprintf("Order contains %d nodes, but the graph contains %d nodes.\n", len(order), aag.NodeCount()))
+	}
+	permutation := Permutation(order)
+
+	nodes := make([]N, 0, aag.NodeCount())
+	edges := make([]E, 0, aag.EdgeCount())
+	offsets := make([]int, aag.NodeCount()+1)
+
+	for newId, oldId := range order {
+		nodes = append(nodes, aag.Nodes[oldId])
+		for _, halfEdge := range aag.GetHalfEdgesFrom(oldId) {
+			edges = append(edges, halfEdge.SetTo(permutation[halfEdge.To()]).(E))
+		}
+		offsets[newId+1] = len(edges)
+	}
+
+	return &AdjacencyArrayGraph[N, E]{Nodes: nodes, Edges: edges, Offsets: offsets}, permutation
+}
+
+// PermuteSlice rearranges per-node data (e.g. distance arrays) such that it follows the nodes of a reordered graph.
+// The returned slice stores data[oldId] at index permutation[oldId].
+func PermuteSlice[T any](data []T, permutation []NodeId) []T {
+	if len(data) != len(permutation) {
+		panic(fmt.Sprintf("Length of data (%d) does not match length of permutation (%d).\n", len(data), len(permutation)))
+	}
+	permuted := make([]T, len(data))
+	for oldId, newId := range permutation {
+		permuted[newId] = data[oldId]
+	}
+	return permuted
+}
@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"testing"
+)
+
+// buildTestGraph creates a small directed graph of GeoPoints with two weakly connected components.
+func buildTestGraph() *AdjacencyArrayGraph[GeoPoint, WeightedHalfEdge[int]] {
+	alg := &AdjacencyListGraph[GeoPoint, WeightedHalfEdge[int]]{}
+	alg.AppendNode(GeoPoint{Lat: 10, Lon: 10})
+	alg.AppendNode(GeoPoint{Lat: -40, Lon: 120})
+	alg.AppendNode(GeoPoint{Lat: 11, Lon: 11})
+	alg.AppendNode(GeoPoint{Lat: -41, Lon: 121})
+	alg.AppendNode(GeoPoint{Lat: 10, Lon: 12})
+	alg.AppendNode(GeoPoint{Lat: 60, Lon: -30})
+	alg.InsertHalfEdge(0, NewWeightedHalfEdge(2, 3))
+	alg.InsertHalfEdge(0, NewWeightedHalfEdge(4, 5))
+	alg.InsertHalfEdge(2, NewWeightedHalfEdge(4, 1))
+	alg.InsertHalfEdge(4, NewWeightedHalfEdge(0, 5))
+	alg.InsertHalfEdge(1, NewWeightedHalfEdge(3, 7))
+	alg.InsertHalfEdge(3, NewWeightedHalfEdge(1, 7))
+	return NewAdjacencyArrayFromGraph[GeoPoint, WeightedHalfEdge[int]](alg)
+}
+
+func TestReorderNodes(t *testing.T) {
+	t.Parallel()
+
+	aag := buildTestGraph()
+	orders := map[string][]NodeId{
+		"bfs":     BfsOrder[GeoPoint, WeightedHalfEdge[int]](aag, 1),
+		"dfs":     DfsOrder[GeoPoint, WeightedHalfEdge[int]](aag, 0),
+		"hilbert": HilbertOrder[GeoPoint, WeightedHalfEdge[int]](aag),
+		"morton":  MortonOrder[GeoPoint, WeightedHalfEdge[int]](aag),
+	}
+
+	for name, order := range orders {
+		reordered, permutation := ReorderNodes(aag, order)
+
+		if reordered.NodeCount() != aag.NodeCount() || reordered.EdgeCount() != aag.EdgeCount() {
+			t.Errorf("[%s] Reordered graph has %d nodes and %d edges, expected %d and %d", name, reordered.NodeCount(), reordered.EdgeCount(), aag.NodeCount(), aag.EdgeCount())
+			continue
+		}
+
+		for oldId := 0; oldId < aag.NodeCount(); oldId++ {
+			newId := permutation[oldId]
+			if reordered.GetNode(newId) != aag.GetNode(oldId) {
+				t.Errorf("[%s] Node %d has not been moved to %d", name, oldId, newId)
+			}
+			oldEdges := aag.GetHalfEdgesFrom(oldId)
+			newEdges := reordered.GetHalfEdgesFrom(newId)
+			if len(oldEdges) != len(newEdges) {
+				t.Errorf("[%s] Node %d has %d leaving edges after reordering, expected %d", name, oldId, len(newEdges), len(oldEdges))
+				continue
+			}
+			for i := range oldEdges {
+				if newEdges[i].To() != permutation[oldEdges[i].To()] || newEdges[i].Weight() != oldEdges[i].Weight() {
+					t.Errorf("[%s] Edge %d->%d has not been rewritten correctly: got %v", name, oldId, oldEdges[i].To(), newEdges[i])
+				}
+			}
+		}
+	}
+}
+
+func TestBfsOrder(t *testing.T) {
+	t.Parallel()
+
+	order := BfsOrder[GeoPoint, WeightedHalfEdge[int]](buildTestGraph(), 0)
+	expected := []NodeId{0, 2, 4, 1, 3, 5}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Unexpected BFS order: got %v, expected %v", order, expected)
+		}
+	}
+}
+
+func TestHilbertOrderLocality(t *testing.T) {
+	t.Parallel()
+
+	// nodes 0, 2 and 4 as well as nodes 1 and 3 are close to each other and should be neighbors on the curve
+	order := HilbertOrder[GeoPoint, WeightedHalfEdge[int]](buildTestGraph())
+	permutation := Permutation(order)
+	if d := permutation[1] - permutation[3]; d != 1 && d != -1 {
+		t.Errorf("Nodes 1 and 3 are not adjacent in Hilbert order %v", order)
+	}
+	positions := []int{permutation[0], permutation[2], permutation[4]}
+	min, max := positions[0], positions[0]
+	for _, p := range positions {
+		if p < min {
+			min = p
+		}
+		if p > max {
+			max = p
+		}
+	}
+	if max-min != 2 {
+		t.Errorf("Nodes 0, 2 and 4 are not contiguous in Hilbert order %v", order)
+	}
+}
+
+func TestPermuteSlice(t *testing.T) {
+	t.Parallel()
+
+	order := []NodeId{2, 0, 1}
+	permuted := PermuteSlice([]string{"a", "b", "c"}, Permutation(order))
+	if permuted[0] != "c" || permuted[1] != "a" || permuted[2] != "b" {
+		t.Errorf("Unexpected permuted slice: %v", permuted)
+	}
+}